@@ -3,6 +3,9 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type Config struct {
@@ -14,10 +17,49 @@ type Config struct {
 	DBName            string
 	DBSSLMode         string
 	JWTSecret         string
+	// TokenEncryptionKey encrypts users' stored API tokens; JWTSecret is used
+	// when it isn't set
+	TokenEncryptionKey string
 	GitHubClientID    string
 	GitHubClientSecret string
 	GitHubRedirectURL string
+	// GitHub endpoints, configurable for GitHub Enterprise
+	GitHubBaseURL    string
+	GitHubAPIURL     string
+	GitHubGraphQLURL string
+	// GitHubWebhookSecret verifies webhook deliveries; webhooks are disabled without it
+	GitHubWebhookSecret string
+	// GitLab OAuth application; GitLabBaseURL points at gitlab.com or a self-hosted instance
+	GitLabBaseURL      string
+	GitLabClientID     string
+	GitLabClientSecret string
+	GitLabRedirectURL  string
+	// Bitbucket Cloud OAuth consumer
+	BitbucketBaseURL      string
+	BitbucketAPIURL       string
+	BitbucketClientID     string
+	BitbucketClientSecret string
+	BitbucketRedirectURL  string
 	CORSOrigins       string
+	UploadDir         string
+	ExportDir         string
+	// MigrateOnStart applies pending migrations at boot instead of refusing to start
+	MigrateOnStart bool
+	// JobWorkers is the number of workers for the default background job queue
+	JobWorkers int
+	// How often connected accounts are re-synced automatically (0 disables)
+	GitHubSyncInterval time.Duration
+	ZennSyncInterval   time.Duration
+	QiitaSyncInterval  time.Duration
+	GitLabSyncInterval    time.Duration
+	BitbucketSyncInterval time.Duration
+	FeedSyncInterval      time.Duration
+	// FeedAllowPrivateHosts lets feeds be read from private and loopback
+	// addresses, for local development
+	FeedAllowPrivateHosts bool
+	// RankingWeights weighs each metric in the composite ranking score, as
+	// "metric=weight" pairs separated by commas
+	RankingWeights string
 }
 
 func Load() *Config {
@@ -30,10 +72,36 @@ func Load() *Config {
 		DBName:             getEnv("DB_NAME", "devsync"),
 		DBSSLMode:          getEnv("DB_SSLMODE", "disable"),
 		JWTSecret:          getEnv("JWT_SECRET", "devsync-dev-secret-change-me"),
+		TokenEncryptionKey: getEnv("TOKEN_ENCRYPTION_KEY", ""),
 		GitHubClientID:     getEnv("GITHUB_CLIENT_ID", ""),
 		GitHubClientSecret: getEnv("GITHUB_CLIENT_SECRET", ""),
 		GitHubRedirectURL: getEnv("GITHUB_REDIRECT_URL", "http://localhost:5173/github/callback"),
+		GitHubWebhookSecret: getEnv("GITHUB_WEBHOOK_SECRET", ""),
+		GitHubBaseURL:       strings.TrimRight(getEnv("GITHUB_BASE_URL", "https://github.com"), "/"),
+		GitHubAPIURL:        strings.TrimRight(getEnv("GITHUB_API_URL", "https://api.github.com"), "/"),
+		GitHubGraphQLURL:    getEnv("GITHUB_GRAPHQL_URL", "https://api.github.com/graphql"),
+		GitLabBaseURL:         strings.TrimRight(getEnv("GITLAB_BASE_URL", "https://gitlab.com"), "/"),
+		GitLabClientID:        getEnv("GITLAB_CLIENT_ID", ""),
+		GitLabClientSecret:    getEnv("GITLAB_CLIENT_SECRET", ""),
+		GitLabRedirectURL:     getEnv("GITLAB_REDIRECT_URL", "http://localhost:5173/code-hosts/gitlab/callback"),
+		BitbucketBaseURL:      strings.TrimRight(getEnv("BITBUCKET_BASE_URL", "https://bitbucket.org"), "/"),
+		BitbucketAPIURL:       strings.TrimRight(getEnv("BITBUCKET_API_URL", "https://api.bitbucket.org/2.0"), "/"),
+		BitbucketClientID:     getEnv("BITBUCKET_CLIENT_ID", ""),
+		BitbucketClientSecret: getEnv("BITBUCKET_CLIENT_SECRET", ""),
+		BitbucketRedirectURL:  getEnv("BITBUCKET_REDIRECT_URL", "http://localhost:5173/code-hosts/bitbucket/callback"),
 		CORSOrigins:       getEnv("CORS_ORIGINS", "http://localhost:5173"),
+		UploadDir:         getEnv("UPLOAD_DIR", "./uploads"),
+		ExportDir:         getEnv("EXPORT_DIR", "./exports"),
+		MigrateOnStart:    getEnv("MIGRATE_ON_START", "false") == "true",
+		JobWorkers:        getEnvInt("JOB_WORKERS", 4),
+		GitHubSyncInterval: getEnvDuration("GITHUB_SYNC_INTERVAL", 6*time.Hour),
+		ZennSyncInterval:   getEnvDuration("ZENN_SYNC_INTERVAL", 12*time.Hour),
+		QiitaSyncInterval:  getEnvDuration("QIITA_SYNC_INTERVAL", 12*time.Hour),
+		GitLabSyncInterval:    getEnvDuration("GITLAB_SYNC_INTERVAL", 6*time.Hour),
+		BitbucketSyncInterval: getEnvDuration("BITBUCKET_SYNC_INTERVAL", 6*time.Hour),
+		FeedSyncInterval:      getEnvDuration("FEED_SYNC_INTERVAL", 3*time.Hour),
+		FeedAllowPrivateHosts: getEnv("FEED_ALLOW_PRIVATE_HOSTS", "false") == "true",
+		RankingWeights:        getEnv("RANKING_WEIGHTS", "contributions=1,posts=5,likes_received=2,accepted_answers=10,goals_completed=10,roadmaps_completed=20,article_likes=2"),
 	}
 }
 
@@ -50,3 +118,17 @@ func getEnv(key, fallback string) string {
 	}
 	return fallback
 }
+
+func getEnvInt(key string, fallback int) int {
+	if v, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return v
+	}
+	return fallback
+}
+
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	if v, err := time.ParseDuration(os.Getenv(key)); err == nil {
+		return v
+	}
+	return fallback
+}
@@ -0,0 +1,361 @@
+// Package githubtest provides a fake GitHub server that replays recorded API
+// responses, so GitHub integrations can be exercised offline. Point
+// GITHUB_BASE_URL, GITHUB_API_URL and GITHUB_GRAPHQL_URL (or the matching
+// config.Config fields) at Server.URL and Server.GraphQLURL.
+package githubtest
+
+import (
+	"crypto/sha1"
+	"embed"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+//go:embed fixtures/*.json
+var fixtures embed.FS
+
+// Code is the OAuth code the fake server exchanges for Token.
+const Code = "fixture-code"
+
+// Server is a fake GitHub server covering the OAuth code exchange, /user,
+// /user/emails, paginated /user/repos, /repos/{owner}/{repo}/languages and
+// the GraphQL contribution, pull request, issue and review queries. REST responses carry rate-limit headers,
+// and /languages supports conditional requests.
+type Server struct {
+	*httptest.Server
+	// GraphQLURL is the GraphQL endpoint of the fake server
+	GraphQLURL string
+	// Token is the access token issued for Code and required by the API
+	Token string
+	// User is the recorded /user response
+	User map[string]any
+	// Repos are the repositories listed by /user/repos
+	Repos []json.RawMessage
+	// Languages holds the /languages response per repository full name
+	Languages map[string]map[string]int64
+	// RateLimitRemaining is the token's remaining REST requests; at zero
+	// requests fail with 403 like GitHub's. Conditional requests answered
+	// with 304 Not Modified are free.
+	RateLimitRemaining int
+	// RateLimitReset is when the rate limit resets
+	RateLimitReset time.Time
+	// GraphQLPageSize splits the recorded pull requests, issues and reviews
+	// into pages of this many nodes, following the query's $cursor. At zero
+	// they're served as one page.
+	GraphQLPageSize int
+
+	emails json.RawMessage
+	// graphQL holds the recorded response for each query, by the field
+	// that identifies it
+	graphQL map[string]json.RawMessage
+
+	mu       sync.Mutex
+	requests []string
+	failures int
+}
+
+// NewServer starts a fake GitHub server loaded with the recorded fixtures.
+// Callers must Close it.
+func NewServer() *Server {
+	s := &Server{RateLimitRemaining: 5000, RateLimitReset: time.Now().Add(time.Hour)}
+	var token struct {
+		AccessToken string `json:"access_token"`
+	}
+	mustLoad("access_token.json", &token)
+	s.Token = token.AccessToken
+	mustLoad("user.json", &s.User)
+	mustLoad("user_emails.json", &s.emails)
+	mustLoad("repos.json", &s.Repos)
+	mustLoad("languages.json", &s.Languages)
+	s.graphQL = make(map[string]json.RawMessage)
+	for field, name := range graphQLFixtures {
+		var response json.RawMessage
+		mustLoad(name, &response)
+		s.graphQL[field] = response
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login/oauth/access_token", s.handleAccessToken)
+	mux.HandleFunc("/user", s.authorized(s.handleUser))
+	mux.HandleFunc("/user/emails", s.authorized(s.handleEmails))
+	mux.HandleFunc("/user/repos", s.authorized(s.handleRepos))
+	mux.HandleFunc("/repos/", s.authorized(s.handleLanguages))
+	mux.HandleFunc("/graphql", s.authorized(s.handleGraphQL))
+
+	s.Server = httptest.NewServer(s.record(mux))
+	s.GraphQLURL = s.URL + "/graphql"
+	return s
+}
+
+// graphQLFixtures maps the field a query asks for to its recorded response.
+var graphQLFixtures = map[string]string{
+	"pullRequestReviewContributions": "graphql_reviews.json",
+	"pullRequests":                   "graphql_pull_requests.json",
+	"issues":                         "graphql_issues.json",
+	"contributionCalendar":           "graphql_contributions.json",
+}
+
+func mustLoad(name string, v any) {
+	data, err := fixtures.ReadFile("fixtures/" + name)
+	if err != nil {
+		panic(err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		panic(fmt.Sprintf("githubtest: %s: %v", name, err))
+	}
+}
+
+// FailNext makes the next n requests fail with 502 Bad Gateway.
+func (s *Server) FailNext(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = n
+}
+
+// Requests returns the method and path of every request received, e.g.
+// "GET /user/repos?page=1&per_page=100&sort=updated".
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+func (s *Server) record(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests = append(s.requests, r.Method+" "+r.URL.RequestURI())
+		fail := s.failures > 0
+		if fail {
+			s.failures--
+		}
+		s.mu.Unlock()
+
+		if fail {
+			writeJSON(w, http.StatusBadGateway, map[string]string{"message": "Server Error"})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) authorized(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+s.Token {
+			writeJSON(w, http.StatusUnauthorized, map[string]string{
+				"message":           "Bad credentials",
+				"documentation_url": "https://docs.github.com/rest",
+			})
+			return
+		}
+		w.Header().Set("X-OAuth-Scopes", "read:user, repo, user:email")
+
+		if r.URL.Path != "/graphql" {
+			s.mu.Lock()
+			remaining := s.RateLimitRemaining
+			if remaining > 0 && !notModified(r, s.etag(r)) {
+				s.RateLimitRemaining--
+			}
+			w.Header().Set("X-RateLimit-Limit", "5000")
+			w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(s.RateLimitRemaining))
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(s.RateLimitReset.Unix(), 10))
+			s.mu.Unlock()
+
+			if remaining == 0 {
+				writeJSON(w, http.StatusForbidden, map[string]string{
+					"message": "API rate limit exceeded for user.",
+				})
+				return
+			}
+		}
+		next(w, r)
+	}
+}
+
+// etag returns the ETag of the resource at r, or "" if it has none. Only
+// /languages responses carry one.
+func (s *Server) etag(r *http.Request) string {
+	fullName, ok := languagesRepo(r)
+	if !ok {
+		return ""
+	}
+	data, _ := json.Marshal(s.Languages[fullName])
+	sum := sha1.Sum(data)
+	return `"` + hex.EncodeToString(sum[:]) + `"`
+}
+
+func notModified(r *http.Request, etag string) bool {
+	return etag != "" && r.Header.Get("If-None-Match") == etag
+}
+
+func languagesRepo(r *http.Request) (string, bool) {
+	return strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/repos/"), "/languages")
+}
+
+func (s *Server) handleAccessToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	var body struct {
+		Code string `json:"code"`
+	}
+	json.NewDecoder(r.Body).Decode(&body)
+
+	// GitHub reports a bad code with 200 and an error field
+	if body.Code != Code {
+		writeJSON(w, http.StatusOK, map[string]string{
+			"error":             "bad_verification_code",
+			"error_description": "The code passed is incorrect or expired.",
+		})
+		return
+	}
+	writeFixture(w, "access_token.json")
+}
+
+func (s *Server) handleUser(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.User)
+}
+
+func (s *Server) handleEmails(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.emails)
+}
+
+// handleRepos pages through Repos like GitHub, including the Link header.
+func (s *Server) handleRepos(w http.ResponseWriter, r *http.Request) {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+	if perPage < 1 || perPage > 100 {
+		perPage = 30
+	}
+
+	start := (page - 1) * perPage
+	if start > len(s.Repos) {
+		start = len(s.Repos)
+	}
+	end := start + perPage
+	if end > len(s.Repos) {
+		end = len(s.Repos)
+	}
+
+	lastPage := (len(s.Repos) + perPage - 1) / perPage
+	if page < lastPage {
+		link := func(p int, rel string) string {
+			return fmt.Sprintf(`<%s/user/repos?per_page=%d&page=%d>; rel="%s"`, s.URL, perPage, p, rel)
+		}
+		w.Header().Set("Link", link(page+1, "next")+", "+link(lastPage, "last"))
+	}
+	writeJSON(w, http.StatusOK, s.Repos[start:end])
+}
+
+func (s *Server) handleLanguages(w http.ResponseWriter, r *http.Request) {
+	fullName, ok := languagesRepo(r)
+	if !ok {
+		writeJSON(w, http.StatusNotFound, map[string]string{"message": "Not Found"})
+		return
+	}
+	etag := s.etag(r)
+	w.Header().Set("ETag", etag)
+	if notModified(r, etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	langs := s.Languages[fullName]
+	if langs == nil {
+		langs = map[string]int64{}
+	}
+	writeJSON(w, http.StatusOK, langs)
+}
+
+func (s *Server) handleGraphQL(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Query     string         `json:"query"`
+		Variables map[string]any `json:"variables"`
+	}
+	if r.Method != http.MethodPost || json.NewDecoder(r.Body).Decode(&body) != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"message": "Problems parsing JSON"})
+		return
+	}
+	// Reviews are asked for within a contributions collection, so they're
+	// matched before the other queries
+	for _, field := range []string{"pullRequestReviewContributions", "pullRequests", "issues", "contributionCalendar"} {
+		if !strings.Contains(body.Query, field) {
+			continue
+		}
+		cursor, _ := body.Variables["cursor"].(string)
+		writeJSON(w, http.StatusOK, s.graphQLPage(field, cursor))
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"errors": []map[string]string{{"message": "githubtest: no recorded response for this query"}},
+	})
+}
+
+// graphQLPage returns the recorded response for field, cut down to the page
+// after cursor when GraphQLPageSize is set. Cursors are node offsets.
+func (s *Server) graphQLPage(field, cursor string) any {
+	if s.GraphQLPageSize <= 0 || field == "contributionCalendar" {
+		return s.graphQL[field]
+	}
+	var response map[string]any
+	json.Unmarshal(s.graphQL[field], &response)
+	connection := findConnection(response, field)
+	if connection == nil {
+		return response
+	}
+
+	nodes, _ := connection["nodes"].([]any)
+	start, _ := strconv.Atoi(cursor)
+	if start > len(nodes) {
+		start = len(nodes)
+	}
+	end := start + s.GraphQLPageSize
+	if end > len(nodes) {
+		end = len(nodes)
+	}
+	connection["nodes"] = nodes[start:end]
+	connection["pageInfo"] = map[string]any{"hasNextPage": end < len(nodes), "endCursor": strconv.Itoa(end)}
+	return response
+}
+
+// findConnection returns the object under key field anywhere in v.
+func findConnection(v any, field string) map[string]any {
+	object, ok := v.(map[string]any)
+	if !ok {
+		return nil
+	}
+	if connection, ok := object[field].(map[string]any); ok {
+		return connection
+	}
+	for _, child := range object {
+		if connection := findConnection(child, field); connection != nil {
+			return connection
+		}
+	}
+	return nil
+}
+
+func writeFixture(w http.ResponseWriter, name string) {
+	data, err := fixtures.ReadFile("fixtures/" + name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Write(data)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -7,15 +7,18 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/norman6464/devsync/backend/internal/model"
 	"github.com/norman6464/devsync/backend/internal/repository"
+	"github.com/norman6464/devsync/backend/internal/service"
 )
 
 type AnswerHandler struct {
-	answerRepo   *repository.AnswerRepository
-	questionRepo *repository.QuestionRepository
+	answerRepo     *repository.AnswerRepository
+	questionRepo   *repository.QuestionRepository
+	mentionService *service.MentionService
+	badgeService   *service.BadgeService
 }
 
-func NewAnswerHandler(answerRepo *repository.AnswerRepository, questionRepo *repository.QuestionRepository) *AnswerHandler {
-	return &AnswerHandler{answerRepo: answerRepo, questionRepo: questionRepo}
+func NewAnswerHandler(answerRepo *repository.AnswerRepository, questionRepo *repository.QuestionRepository, mentionService *service.MentionService, badgeService *service.BadgeService) *AnswerHandler {
+	return &AnswerHandler{answerRepo: answerRepo, questionRepo: questionRepo, mentionService: mentionService, badgeService: badgeService}
 }
 
 func (h *AnswerHandler) GetByQuestionID(c *gin.Context) {
@@ -73,6 +76,9 @@ func (h *AnswerHandler) Create(c *gin.Context) {
 		return
 	}
 
+	h.mentionService.EnqueueSync(userID, answerMentionTarget(answer), answer.Body, nil)
+	h.badgeService.Evaluate(userID)
+
 	c.JSON(http.StatusCreated, answer)
 }
 
@@ -108,6 +114,8 @@ func (h *AnswerHandler) Update(c *gin.Context) {
 		return
 	}
 
+	h.mentionService.EnqueueSync(userID, answerMentionTarget(answer), answer.Body, nil)
+
 	c.JSON(http.StatusOK, answer)
 }
 
@@ -134,6 +142,7 @@ func (h *AnswerHandler) Delete(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete answer"})
 		return
 	}
+	h.mentionService.Remove(model.MentionSourceAnswer, answer.ID)
 
 	c.JSON(http.StatusOK, gin.H{"message": "Answer deleted successfully"})
 }
@@ -220,3 +229,8 @@ func (h *AnswerHandler) RemoveVote(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "Vote removed successfully"})
 }
+
+func answerMentionTarget(answer *model.Answer) service.MentionTarget {
+	questionID := answer.QuestionID
+	return service.MentionTarget{SourceType: model.MentionSourceAnswer, SourceID: answer.ID, QuestionID: &questionID}
+}
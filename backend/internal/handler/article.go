@@ -0,0 +1,292 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/norman6464/devsync/backend/internal/model"
+	"github.com/norman6464/devsync/backend/internal/repository"
+	"github.com/norman6464/devsync/backend/internal/service"
+)
+
+// ArticleHandler connects article sources such as Zenn and Qiita and lists a
+// user's articles from every source, feeds included.
+type ArticleHandler struct {
+	articleRepo    *repository.ArticleRepository
+	userRepo       *repository.UserRepository
+	articleService *service.ArticleService
+	syncService    *service.SyncService
+}
+
+func NewArticleHandler(articleRepo *repository.ArticleRepository, userRepo *repository.UserRepository, articleService *service.ArticleService, syncService *service.SyncService) *ArticleHandler {
+	return &ArticleHandler{
+		articleRepo:    articleRepo,
+		userRepo:       userRepo,
+		articleService: articleService,
+		syncService:    syncService,
+	}
+}
+
+// Connect sets the user's username on the source and syncs their articles.
+// Sources that support it also take the user's access token.
+func (h *ArticleHandler) Connect(c *gin.Context) {
+	source, ok := h.source(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Username string `json:"username" binding:"required"`
+		Token    string `json:"token"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "username is required"})
+		return
+	}
+
+	user, err := h.userRepo.FindByID(c.GetUint("userID"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		return
+	}
+
+	err = h.articleService.Connect(source, user, req.Username, req.Token)
+	switch {
+	case errors.Is(err, service.ErrInvalidArticleUser):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + string(source) + " username"})
+		return
+	case errors.Is(err, service.ErrInvalidArticleToken), errors.Is(err, service.ErrArticleTokenUnsupported):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update user"})
+		return
+	}
+
+	count, err := h.syncService.Sync(source, user)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to sync articles"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":        string(source) + " connected successfully",
+		"articles_count": count,
+		"authenticated":  h.articleService.Authenticated(source, user),
+	})
+}
+
+// Disconnect clears the user's username on the source and deletes its
+// articles
+func (h *ArticleHandler) Disconnect(c *gin.Context) {
+	source, ok := h.source(c)
+	if !ok {
+		return
+	}
+
+	user, err := h.userRepo.FindByID(c.GetUint("userID"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		return
+	}
+
+	if err := h.articleService.Disconnect(source, user); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to disconnect"})
+		return
+	}
+	h.syncService.Forget(source, user.ID)
+
+	c.JSON(http.StatusOK, gin.H{"message": string(source) + " disconnected successfully"})
+}
+
+// Sync refreshes the current user's articles from the source
+func (h *ArticleHandler) Sync(c *gin.Context) {
+	source, ok := h.source(c)
+	if !ok {
+		return
+	}
+
+	user, err := h.userRepo.FindByID(c.GetUint("userID"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		return
+	}
+	if !h.articleService.Connected(source, user) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": string(source) + " not connected"})
+		return
+	}
+
+	count, err := h.syncService.Sync(source, user)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to sync articles"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":        string(source) + " synced successfully",
+		"articles_count": count,
+	})
+}
+
+// GetArticles returns a user's articles, newest first. ?source=zenn|qiita|feed
+// limits them to one source. Limited-share Qiita articles are only listed for
+// their author.
+func (h *ArticleHandler) GetArticles(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("userId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	source, ok := sourceFilter(c)
+	if !ok {
+		return
+	}
+
+	own := c.GetUint("userID") == uint(userID)
+	articles, err := h.articleRepo.GetArticles(uint(userID), source, own, page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get articles"})
+		return
+	}
+	c.JSON(http.StatusOK, articles)
+}
+
+// GetStats returns a user's article statistics, in total and per source.
+// ?source= limits them to one source.
+func (h *ArticleHandler) GetStats(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("userId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	source, ok := sourceFilter(c)
+	if !ok {
+		return
+	}
+
+	own := c.GetUint("userID") == uint(userID)
+	stats, err := h.articleRepo.GetStats(uint(userID), source, own)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get stats"})
+		return
+	}
+	c.JSON(http.StatusOK, stats)
+}
+
+// GetEngagement returns the daily like, comment and stock counts summed over
+// a user's articles for the last ?days= days (default 30). ?source= limits
+// them to one source.
+func (h *ArticleHandler) GetEngagement(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("userId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+	source, ok := sourceFilter(c)
+	if !ok {
+		return
+	}
+
+	own := c.GetUint("userID") == uint(userID)
+	series, err := h.articleRepo.GetUserEngagement(uint(userID), source, own, engagementSince(c, 30, 365))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get engagement"})
+		return
+	}
+	c.JSON(http.StatusOK, series)
+}
+
+// GetArticleEngagement returns one article's daily like, comment and stock
+// counts for the last ?days= days (default 30)
+func (h *ArticleHandler) GetArticleEngagement(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("userId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+	articleID, err := strconv.ParseUint(c.Param("articleId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid article ID"})
+		return
+	}
+
+	article, err := h.articleRepo.FindByID(uint(articleID))
+	if err != nil || article.UserID != uint(userID) || (article.Private && c.GetUint("userID") != article.UserID) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "article not found"})
+		return
+	}
+
+	series, err := h.articleRepo.GetArticleEngagement(article.ID, engagementSince(c, 30, 365))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get engagement"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"article":    article,
+		"engagement": series,
+	})
+}
+
+// GetTrending returns the articles across all members that gained the most
+// likes in the last ?days= days (default 7)
+func (h *ArticleHandler) GetTrending(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if limit < 1 || limit > 50 {
+		limit = 20
+	}
+
+	articles, err := h.articleRepo.GetTrending(engagementSince(c, 7, 30), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get trending articles"})
+		return
+	}
+	c.JSON(http.StatusOK, articles)
+}
+
+// engagementSince returns the start of the window given by ?days=, which
+// defaults to def and is capped at max.
+func engagementSince(c *gin.Context, def, max int) time.Time {
+	days, err := strconv.Atoi(c.Query("days"))
+	if err != nil || days < 1 {
+		days = def
+	}
+	if days > max {
+		days = max
+	}
+	return time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -days)
+}
+
+// source resolves the :source parameter to a connectable article source,
+// responding with 404 if there is none.
+func (h *ArticleHandler) source(c *gin.Context) (model.IntegrationProvider, bool) {
+	source := model.IntegrationProvider(c.Param("source"))
+	if _, err := h.articleService.Source(source); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return "", false
+	}
+	return source, true
+}
+
+// sourceFilter parses the optional ?source= query parameter.
+func sourceFilter(c *gin.Context) (model.IntegrationProvider, bool) {
+	source := model.IntegrationProvider(c.Query("source"))
+	switch source {
+	case "", model.IntegrationZenn, model.IntegrationQiita, model.IntegrationFeed:
+		return source, true
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": "invalid source"})
+	return "", false
+}
@@ -18,14 +18,18 @@ type AuthHandler struct {
 	githubService     *service.GitHubService
 	userRepo          *repository.UserRepository
 	passwordResetRepo *repository.PasswordResetRepository
+	deletionService   *service.AccountDeletionService
+	syncService       *service.SyncService
 }
 
-func NewAuthHandler(authService *service.AuthService, githubService *service.GitHubService, userRepo *repository.UserRepository, passwordResetRepo *repository.PasswordResetRepository) *AuthHandler {
+func NewAuthHandler(authService *service.AuthService, githubService *service.GitHubService, userRepo *repository.UserRepository, passwordResetRepo *repository.PasswordResetRepository, deletionService *service.AccountDeletionService, syncService *service.SyncService) *AuthHandler {
 	return &AuthHandler{
 		authService:       authService,
 		githubService:     githubService,
 		userRepo:          userRepo,
 		passwordResetRepo: passwordResetRepo,
+		deletionService:   deletionService,
+		syncService:       syncService,
 	}
 }
 
@@ -104,9 +108,8 @@ func (h *AuthHandler) GitHubLoginCallback(c *gin.Context) {
 	}
 
 	// Sync GitHub data in background
-	user, _ := h.userRepo.FindByID(resp.User.ID)
-	if user != nil && user.GitHubConnected {
-		go h.githubService.SyncData(user)
+	if resp.User.GitHubConnected {
+		h.syncService.Enqueue(model.IntegrationGitHub, resp.User.ID)
 	}
 
 	c.JSON(http.StatusOK, resp)
@@ -120,7 +123,7 @@ func (h *AuthHandler) Me(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, user)
+	c.JSON(http.StatusOK, withSyncStatus(user, h.syncService, true))
 }
 
 // RequestPasswordReset generates a password reset token and returns it
@@ -213,7 +216,9 @@ func (h *AuthHandler) ResetPassword(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Password has been reset successfully"})
 }
 
-// DeleteAccount permanently deletes the user's account and all related data
+// DeleteAccount schedules the user's account for deletion after a grace
+// period during which it can be restored. With ?immediate=true the account and
+// all related data are deleted right away.
 func (h *AuthHandler) DeleteAccount(c *gin.Context) {
 	userID := c.GetUint("userID")
 
@@ -244,11 +249,42 @@ func (h *AuthHandler) DeleteAccount(c *gin.Context) {
 		}
 	}
 
-	// Delete user and all related data
-	if err := h.userRepo.DeleteWithRelatedData(userID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete account"})
+	if c.Query("immediate") == "true" {
+		if err := h.deletionService.DeleteNow(userID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete account"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Account deleted successfully"})
+		return
+	}
+
+	if err := h.deletionService.Schedule(user); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to schedule account deletion"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Account deleted successfully"})
+	c.JSON(http.StatusOK, gin.H{
+		"message":               "Account scheduled for deletion",
+		"deletion_scheduled_at": user.DeletionScheduledAt,
+	})
+}
+
+// RestoreAccount cancels a scheduled account deletion
+func (h *AuthHandler) RestoreAccount(c *gin.Context) {
+	user, err := h.userRepo.FindByID(c.GetUint("userID"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		return
+	}
+	if user.DeletionScheduledAt == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "account is not scheduled for deletion"})
+		return
+	}
+
+	if err := h.deletionService.Restore(user); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to restore account"})
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
 }
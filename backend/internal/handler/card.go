@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/norman6464/devsync/backend/internal/model"
+	"github.com/norman6464/devsync/backend/internal/repository"
+	"github.com/norman6464/devsync/backend/internal/service"
+	"gorm.io/gorm"
+)
+
+// CardHandler serves embeddable SVG cards for READMEs and blogs. Cards follow
+// the same privacy rules as the public profile.
+type CardHandler struct {
+	db               *gorm.DB
+	handleService    *service.HandleService
+	privacyRepo      *repository.PrivacyRepository
+	githubRepo       *repository.GitHubRepository
+	contributionRepo *repository.ContributionRepository
+	badgeService     *service.BadgeService
+}
+
+func NewCardHandler(db *gorm.DB, handleService *service.HandleService, privacyRepo *repository.PrivacyRepository,
+	githubRepo *repository.GitHubRepository, contributionRepo *repository.ContributionRepository, badgeService *service.BadgeService) *CardHandler {
+	return &CardHandler{db: db, handleService: handleService, privacyRepo: privacyRepo, githubRepo: githubRepo, contributionRepo: contributionRepo, badgeService: badgeService}
+}
+
+// Heatmap renders the contribution calendar card
+func (h *CardHandler) Heatmap(c *gin.Context) {
+	user, public, ok := h.findCardUser(c, func(s *model.PrivacySettings) bool { return s.ShowContributions })
+	if !ok {
+		return
+	}
+	contributions, err := h.contributionRepo.GetContributions(user.ID, time.Now().AddDate(-1, 0, -7), time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	writeSVG(c, service.RenderHeatmapCard(user, contributions, cardTheme(c)), public)
+}
+
+// Languages renders the top languages card. ?limit= controls how many are shown.
+func (h *CardHandler) Languages(c *gin.Context) {
+	user, public, ok := h.findCardUser(c, func(s *model.PrivacySettings) bool { return s.ShowLanguages })
+	if !ok {
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "6"))
+	if limit < 1 || limit > 10 {
+		limit = 6
+	}
+	stats, err := h.githubRepo.GetLanguageStats(user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	writeSVG(c, service.RenderLanguagesCard(user, stats, limit, cardTheme(c)), public)
+}
+
+// Badges renders the earned badges card
+func (h *CardHandler) Badges(c *gin.Context) {
+	user, public, ok := h.findCardUser(c, func(s *model.PrivacySettings) bool { return s.ShowBadges })
+	if !ok {
+		return
+	}
+	badges, err := h.badgeService.Results(user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	writeSVG(c, service.RenderBadgesCard(user, badges, cardTheme(c)), public)
+}
+
+// Streak renders the current/longest streak card
+func (h *CardHandler) Streak(c *gin.Context) {
+	user, public, ok := h.findCardUser(c, func(s *model.PrivacySettings) bool { return s.ShowContributions })
+	if !ok {
+		return
+	}
+	stats, err := service.GetStreakStats(h.db, user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	writeSVG(c, service.RenderStreakCard(user, stats, cardTheme(c)), public)
+}
+
+// findCardUser resolves the profile and checks that the card's section is
+// shared. public reports whether the card may be stored by shared caches: it
+// may not when the profile is private and only its owner can see it.
+func (h *CardHandler) findCardUser(c *gin.Context, visible func(*model.PrivacySettings) bool) (user *model.User, public bool, ok bool) {
+	user, settings, ok := findPublicUser(c, h.handleService, h.privacyRepo)
+	if !ok {
+		return nil, false, false
+	}
+	if !visible(settings) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "card not available"})
+		return nil, false, false
+	}
+	return user, settings.ProfilePublic && c.GetUint("userID") != user.ID, true
+}
+
+func cardTheme(c *gin.Context) service.CardTheme {
+	overrides := make(map[string]string)
+	for _, key := range []string{"bg", "border", "title", "text", "accent"} {
+		if v := c.Query(key); v != "" {
+			overrides[key] = v
+		}
+	}
+	return service.ResolveCardTheme(c.Query("theme"), overrides)
+}
+
+// writeSVG sends an SVG with an ETag so image proxies like GitHub's camo can
+// revalidate cheaply instead of re-downloading unchanged cards. Only public
+// cards are cacheable by shared caches.
+func writeSVG(c *gin.Context, svg []byte, public bool) {
+	sum := sha1.Sum(svg)
+	etag := `"` + hex.EncodeToString(sum[:]) + `"`
+
+	c.Header("ETag", etag)
+	c.Header("Vary", "Authorization")
+	if public {
+		c.Header("Cache-Control", "public, max-age=3600")
+	} else {
+		c.Header("Cache-Control", "private, no-store")
+	}
+	if etagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, "image/svg+xml; charset=utf-8", svg)
+}
+
+// etagMatches reports whether an If-None-Match header lists etag, comparing
+// weakly as RFC 9110 requires for it.
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, tag := range strings.Split(ifNoneMatch, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
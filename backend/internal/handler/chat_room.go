@@ -12,13 +12,21 @@ import (
 )
 
 type ChatRoomHandler struct {
-	roomRepo    *repository.ChatRoomRepository
-	messageRepo *repository.GroupMessageRepository
-	hub         *service.Hub
+	roomRepo       *repository.ChatRoomRepository
+	messageRepo    *repository.GroupMessageRepository
+	hub            *service.Hub
+	mentionService *service.MentionService
+	members        groupMembers[model.ChatRoomMember]
 }
 
-func NewChatRoomHandler(roomRepo *repository.ChatRoomRepository, messageRepo *repository.GroupMessageRepository, hub *service.Hub) *ChatRoomHandler {
-	return &ChatRoomHandler{roomRepo: roomRepo, messageRepo: messageRepo, hub: hub}
+func NewChatRoomHandler(roomRepo *repository.ChatRoomRepository, messageRepo *repository.GroupMessageRepository, hub *service.Hub, mentionService *service.MentionService) *ChatRoomHandler {
+	return &ChatRoomHandler{
+		roomRepo:       roomRepo,
+		messageRepo:    messageRepo,
+		hub:            hub,
+		mentionService: mentionService,
+		members:        groupMembers[model.ChatRoomMember]{store: roomRepo, noun: "room"},
+	}
 }
 
 func (h *ChatRoomHandler) Create(c *gin.Context) {
@@ -39,24 +47,11 @@ func (h *ChatRoomHandler) Create(c *gin.Context) {
 		Description: input.Description,
 		OwnerID:     userID,
 	}
-	if err := h.roomRepo.Create(room); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
-	// Add owner as member
-	if err := h.roomRepo.AddMember(room.ID, userID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.roomRepo.Create(room, memberIDs(input.MemberIDs, userID)); err != nil {
+		addError(c, err)
 		return
 	}
 
-	// Add other members
-	for _, memberID := range input.MemberIDs {
-		if memberID != userID {
-			h.roomRepo.AddMember(room.ID, memberID)
-		}
-	}
-
 	room, _ = h.roomRepo.FindByID(room.ID)
 	c.JSON(http.StatusCreated, room)
 }
@@ -72,20 +67,12 @@ func (h *ChatRoomHandler) GetMyRooms(c *gin.Context) {
 }
 
 func (h *ChatRoomHandler) GetByID(c *gin.Context) {
-	userID := c.GetUint("userID")
-	roomID, err := strconv.ParseUint(c.Param("id"), 10, 64)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid room id"})
-		return
-	}
-
-	isMember, err := h.roomRepo.IsMember(uint(roomID), userID)
-	if err != nil || !isMember {
-		c.JSON(http.StatusForbidden, gin.H{"error": "not a member"})
+	roomID, ok := h.members.groupID(c)
+	if !ok || !h.members.requireMember(c, roomID) {
 		return
 	}
 
-	room, err := h.roomRepo.FindByID(uint(roomID))
+	room, err := h.roomRepo.FindByID(roomID)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "room not found"})
 		return
@@ -158,86 +145,15 @@ func (h *ChatRoomHandler) Delete(c *gin.Context) {
 }
 
 func (h *ChatRoomHandler) GetMembers(c *gin.Context) {
-	userID := c.GetUint("userID")
-	roomID, err := strconv.ParseUint(c.Param("id"), 10, 64)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid room id"})
-		return
-	}
-
-	isMember, err := h.roomRepo.IsMember(uint(roomID), userID)
-	if err != nil || !isMember {
-		c.JSON(http.StatusForbidden, gin.H{"error": "not a member"})
-		return
-	}
-
-	members, err := h.roomRepo.GetMembers(uint(roomID))
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-	c.JSON(http.StatusOK, members)
+	h.members.list(c)
 }
 
 func (h *ChatRoomHandler) AddMember(c *gin.Context) {
-	userID := c.GetUint("userID")
-	roomID, err := strconv.ParseUint(c.Param("id"), 10, 64)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid room id"})
-		return
-	}
-
-	isMember, err := h.roomRepo.IsMember(uint(roomID), userID)
-	if err != nil || !isMember {
-		c.JSON(http.StatusForbidden, gin.H{"error": "not a member"})
-		return
-	}
-
-	var input struct {
-		UserID uint `json:"user_id" binding:"required"`
-	}
-	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	if err := h.roomRepo.AddMember(uint(roomID), input.UserID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-	c.JSON(http.StatusOK, gin.H{"message": "member added"})
+	h.members.add(c)
 }
 
 func (h *ChatRoomHandler) RemoveMember(c *gin.Context) {
-	userID := c.GetUint("userID")
-	roomID, err := strconv.ParseUint(c.Param("id"), 10, 64)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid room id"})
-		return
-	}
-	targetID, err := strconv.ParseUint(c.Param("userId"), 10, 64)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
-		return
-	}
-
-	room, err := h.roomRepo.FindByID(uint(roomID))
-	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "room not found"})
-		return
-	}
-
-	// Owner can remove anyone, others can only remove themselves
-	if room.OwnerID != userID && userID != uint(targetID) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "only owner can remove members"})
-		return
-	}
-
-	if err := h.roomRepo.RemoveMember(uint(roomID), uint(targetID)); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-	c.JSON(http.StatusOK, gin.H{"message": "member removed"})
+	h.members.remove(c)
 }
 
 func (h *ChatRoomHandler) GetMessages(c *gin.Context) {
@@ -314,5 +230,13 @@ func (h *ChatRoomHandler) SendMessage(c *gin.Context) {
 		h.hub.SendToRoom(uint(roomID), userID, data)
 	}()
 
+	// Mentions are limited to room members so room content stays private
+	chatRoomID := uint(roomID)
+	h.mentionService.EnqueueSync(userID, service.MentionTarget{
+		SourceType: model.MentionSourceGroupMessage,
+		SourceID:   msg.ID,
+		ChatRoomID: &chatRoomID,
+	}, msg.Content, h.messageRepo.GetMemberUserIDs(chatRoomID))
+
 	c.JSON(http.StatusCreated, msg)
 }
@@ -0,0 +1,233 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/norman6464/devsync/backend/internal/model"
+	"github.com/norman6464/devsync/backend/internal/repository"
+	"github.com/norman6464/devsync/backend/internal/service"
+)
+
+// CodeHostHandler connects GitLab and Bitbucket accounts and serves the data
+// synced from them. The provider comes from the :provider path segment.
+type CodeHostHandler struct {
+	codeHostService  *service.CodeHostService
+	authService      *service.AuthService
+	userRepo         *repository.UserRepository
+	codeHostRepo     *repository.CodeHostRepository
+	contributionRepo *repository.ContributionRepository
+	syncService      *service.SyncService
+}
+
+func NewCodeHostHandler(
+	codeHostService *service.CodeHostService,
+	authService *service.AuthService,
+	userRepo *repository.UserRepository,
+	codeHostRepo *repository.CodeHostRepository,
+	contributionRepo *repository.ContributionRepository,
+	syncService *service.SyncService,
+) *CodeHostHandler {
+	return &CodeHostHandler{
+		codeHostService:  codeHostService,
+		authService:      authService,
+		userRepo:         userRepo,
+		codeHostRepo:     codeHostRepo,
+		contributionRepo: contributionRepo,
+		syncService:      syncService,
+	}
+}
+
+// provider reads the :provider path segment, responding with an error if it
+// isn't a configured code host.
+func (h *CodeHostHandler) provider(c *gin.Context) (model.IntegrationProvider, bool) {
+	provider := model.IntegrationProvider(c.Param("provider"))
+	if _, err := h.codeHostService.Host(provider); errors.Is(err, service.ErrUnknownCodeHost) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "unknown code host"})
+		return "", false
+	} else if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return "", false
+	}
+	return provider, true
+}
+
+func (h *CodeHostHandler) Connect(c *gin.Context) {
+	provider, ok := h.provider(c)
+	if !ok {
+		return
+	}
+	userID := c.GetUint("userID")
+	state, err := h.authService.GenerateOAuthState(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to generate state"})
+		return
+	}
+	host, _ := h.codeHostService.Host(provider)
+	c.JSON(http.StatusOK, gin.H{"url": host.OAuthURL(state)})
+}
+
+func (h *CodeHostHandler) Callback(c *gin.Context) {
+	provider, ok := h.provider(c)
+	if !ok {
+		return
+	}
+	code := c.Query("code")
+	state := c.Query("state")
+
+	if code == "" || state == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "missing code or state"})
+		return
+	}
+
+	userID, err := h.authService.ValidateOAuthState(state)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid state"})
+		return
+	}
+
+	account, err := h.codeHostService.Connect(userID, provider, code)
+	if errors.Is(err, service.ErrCodeHostAccountTaken) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to connect " + string(provider)})
+		return
+	}
+
+	// Sync data in the background
+	h.syncService.Enqueue(provider, userID)
+
+	c.JSON(http.StatusOK, gin.H{"message": string(provider) + " connected", "account": account})
+}
+
+func (h *CodeHostHandler) Sync(c *gin.Context) {
+	provider, ok := h.provider(c)
+	if !ok {
+		return
+	}
+	userID := c.GetUint("userID")
+	if !h.codeHostService.Connected(provider, userID) {
+		c.JSON(http.StatusNotFound, gin.H{"error": string(provider) + " is not connected"})
+		return
+	}
+	user, err := h.userRepo.FindByID(userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		return
+	}
+
+	if _, err := h.syncService.Sync(provider, user); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "sync complete"})
+}
+
+func (h *CodeHostHandler) Disconnect(c *gin.Context) {
+	provider := model.IntegrationProvider(c.Param("provider"))
+	if _, err := h.codeHostService.Host(provider); errors.Is(err, service.ErrUnknownCodeHost) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "unknown code host"})
+		return
+	}
+	userID := c.GetUint("userID")
+
+	if err := h.codeHostService.Disconnect(provider, userID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	h.syncService.Forget(provider, userID)
+
+	c.JSON(http.StatusOK, gin.H{"message": string(provider) + " disconnected"})
+}
+
+// GetAccounts lists the code host accounts a user has connected.
+func (h *CodeHostHandler) GetAccounts(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("userId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+
+	accounts, err := h.codeHostRepo.FindAccountsByUserID(uint(userID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, accounts)
+}
+
+// GetRepos lists a user's repositories on a code host. Private repositories
+// are only shown to the user themselves.
+func (h *CodeHostHandler) GetRepos(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("userId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+
+	repos, err := h.codeHostRepo.GetRepos(uint(userID), model.IntegrationProvider(c.Param("provider")))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if c.GetUint("userID") != uint(userID) {
+		visible := make([]model.CodeHostRepository, 0, len(repos))
+		for _, repo := range repos {
+			if !repo.IsPrivate {
+				visible = append(visible, repo)
+			}
+		}
+		repos = visible
+	}
+	c.JSON(http.StatusOK, repos)
+}
+
+func (h *CodeHostHandler) GetLanguages(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("userId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+
+	stats, err := h.codeHostRepo.GetLanguageStats(uint(userID), model.IntegrationProvider(c.Param("provider")))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, stats)
+}
+
+// GetContributions returns a user's daily contributions summed over GitHub
+// and every connected code host, for the last twelve months or a calendar
+// year with ?year=.
+func (h *CodeHostHandler) GetContributions(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("userId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+
+	to := time.Now()
+	from := to.AddDate(-1, 0, 0)
+	if y := c.Query("year"); y != "" {
+		year, err := strconv.Atoi(y)
+		if err != nil || year < 2008 || year > to.Year() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid year"})
+			return
+		}
+		from = time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+		to = time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC)
+	}
+
+	contributions, err := h.contributionRepo.GetContributions(uint(userID), from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, contributions)
+}
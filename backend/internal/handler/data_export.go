@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/norman6464/devsync/backend/internal/model"
+	"github.com/norman6464/devsync/backend/internal/repository"
+	"github.com/norman6464/devsync/backend/internal/service"
+)
+
+type DataExportHandler struct {
+	exportService *service.DataExportService
+	exportRepo    *repository.DataExportRepository
+}
+
+func NewDataExportHandler(exportService *service.DataExportService, exportRepo *repository.DataExportRepository) *DataExportHandler {
+	return &DataExportHandler{exportService: exportService, exportRepo: exportRepo}
+}
+
+type dataExportResponse struct {
+	model.DataExport
+	DownloadURL string `json:"download_url,omitempty"`
+}
+
+func newDataExportResponse(export *model.DataExport) dataExportResponse {
+	resp := dataExportResponse{DataExport: *export}
+	if export.IsDownloadable() {
+		resp.DownloadURL = "/api/v1/export/takeout/download/" + export.Token
+	}
+	return resp
+}
+
+// Request starts building a ZIP of all the current user's data. Poll Get for
+// its status; the download link appears once it is completed.
+func (h *DataExportHandler) Request(c *gin.Context) {
+	export, err := h.exportService.Request(c.GetUint("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to start export"})
+		return
+	}
+	c.JSON(http.StatusAccepted, newDataExportResponse(export))
+}
+
+// List returns the current user's recent exports
+func (h *DataExportHandler) List(c *gin.Context) {
+	exports, err := h.exportRepo.FindByUserID(c.GetUint("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	resp := make([]dataExportResponse, 0, len(exports))
+	for i := range exports {
+		resp = append(resp, newDataExportResponse(&exports[i]))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// Get returns the status of one of the current user's exports
+func (h *DataExportHandler) Get(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid export ID"})
+		return
+	}
+
+	export, err := h.exportRepo.FindByID(uint(id))
+	if err != nil || export.UserID != c.GetUint("userID") {
+		c.JSON(http.StatusNotFound, gin.H{"error": "export not found"})
+		return
+	}
+	c.JSON(http.StatusOK, newDataExportResponse(export))
+}
+
+// Download serves a finished export by its token until the link expires
+func (h *DataExportHandler) Download(c *gin.Context) {
+	export, err := h.exportRepo.FindByToken(c.Param("token"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "export not found"})
+		return
+	}
+	if !export.IsDownloadable() {
+		c.JSON(http.StatusGone, gin.H{"error": "export is not available for download"})
+		return
+	}
+
+	c.Header("Cache-Control", "private, no-store")
+	c.FileAttachment(export.FilePath, fmt.Sprintf("devsync-export-%s.zip", export.CreatedAt.Format("20060102")))
+}
@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/norman6464/devsync/backend/internal/model"
+	"github.com/norman6464/devsync/backend/internal/repository"
+	"github.com/norman6464/devsync/backend/internal/service"
+	"gorm.io/gorm"
+)
+
+// FeedHandler manages the RSS, Atom and JSON Feeds users publish articles
+// through. Their articles are listed by ArticleHandler.
+type FeedHandler struct {
+	feedService *service.FeedService
+	feedRepo    *repository.FeedRepository
+	userRepo    *repository.UserRepository
+	syncService *service.SyncService
+}
+
+func NewFeedHandler(feedService *service.FeedService, feedRepo *repository.FeedRepository, userRepo *repository.UserRepository, syncService *service.SyncService) *FeedHandler {
+	return &FeedHandler{
+		feedService: feedService,
+		feedRepo:    feedRepo,
+		userRepo:    userRepo,
+		syncService: syncService,
+	}
+}
+
+// List returns the current user's feeds
+func (h *FeedHandler) List(c *gin.Context) {
+	feeds, err := h.feedRepo.FindByUserID(c.GetUint("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get feeds"})
+		return
+	}
+	c.JSON(http.StatusOK, feeds)
+}
+
+// Add registers a feed and reads its articles
+func (h *FeedHandler) Add(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var req struct {
+		URL string `json:"url" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "url is required"})
+		return
+	}
+
+	feed, count, err := h.feedService.AddFeed(userID, req.URL)
+	switch {
+	case errors.Is(err, service.ErrFeedExists):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, service.ErrFeedLimitReached):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	case feed == nil && err != nil:
+		// The URL couldn't be read as a feed
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save feed articles"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"feed":           feed,
+		"articles_count": count,
+	})
+}
+
+// Remove deletes a feed and its articles
+func (h *FeedHandler) Remove(c *gin.Context) {
+	userID := c.GetUint("userID")
+	feedID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid feed ID"})
+		return
+	}
+
+	if err := h.feedService.RemoveFeed(userID, uint(feedID)); errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "feed not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete feed"})
+		return
+	}
+	if !h.feedService.Connected(userID) {
+		h.syncService.Forget(model.IntegrationFeed, userID)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "feed removed"})
+}
+
+// Sync refreshes all the current user's feeds
+func (h *FeedHandler) Sync(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	user, err := h.userRepo.FindByID(userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		return
+	}
+	if !h.feedService.Connected(userID) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "no feeds registered"})
+		return
+	}
+
+	count, err := h.syncService.Sync(model.IntegrationFeed, user)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to sync feeds"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":        "feeds synced successfully",
+		"articles_count": count,
+	})
+}
@@ -7,14 +7,16 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/norman6464/devsync/backend/internal/model"
 	"github.com/norman6464/devsync/backend/internal/repository"
+	"github.com/norman6464/devsync/backend/internal/service"
 )
 
 type FollowHandler struct {
-	repo *repository.FollowRepository
+	repo         *repository.FollowRepository
+	badgeService *service.BadgeService
 }
 
-func NewFollowHandler(repo *repository.FollowRepository) *FollowHandler {
-	return &FollowHandler{repo: repo}
+func NewFollowHandler(repo *repository.FollowRepository, badgeService *service.BadgeService) *FollowHandler {
+	return &FollowHandler{repo: repo, badgeService: badgeService}
 }
 
 func (h *FollowHandler) Follow(c *gin.Context) {
@@ -32,6 +34,8 @@ func (h *FollowHandler) Follow(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	h.badgeService.Evaluate(userID)
+	h.badgeService.Evaluate(uint(targetID))
 	c.JSON(http.StatusOK, gin.H{"message": "followed"})
 }
 
@@ -3,8 +3,10 @@ package handler
 import (
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/norman6464/devsync/backend/internal/model"
 	"github.com/norman6464/devsync/backend/internal/repository"
 	"github.com/norman6464/devsync/backend/internal/service"
 )
@@ -14,6 +16,8 @@ type GitHubHandler struct {
 	authService   *service.AuthService
 	userRepo      *repository.UserRepository
 	githubRepo    *repository.GitHubRepository
+	syncService   *service.SyncService
+	activityRepo  *repository.GitHubActivityRepository
 }
 
 func NewGitHubHandler(
@@ -21,12 +25,16 @@ func NewGitHubHandler(
 	authService *service.AuthService,
 	userRepo *repository.UserRepository,
 	githubRepo *repository.GitHubRepository,
+	syncService *service.SyncService,
+	activityRepo *repository.GitHubActivityRepository,
 ) *GitHubHandler {
 	return &GitHubHandler{
 		githubService: githubService,
 		authService:   authService,
 		userRepo:      userRepo,
 		githubRepo:    githubRepo,
+		syncService:   syncService,
+		activityRepo:  activityRepo,
 	}
 }
 
@@ -87,8 +95,8 @@ func (h *GitHubHandler) Callback(c *gin.Context) {
 		return
 	}
 
-	// Sync data in the background-ish (synchronous for MVP)
-	go h.githubService.SyncData(user)
+	// Sync data in the background
+	h.syncService.Enqueue(model.IntegrationGitHub, user.ID)
 
 	c.JSON(http.StatusOK, gin.H{"message": "github connected"})
 }
@@ -100,7 +108,20 @@ func (h *GitHubHandler) GetContributions(c *gin.Context) {
 		return
 	}
 
-	contributions, err := h.githubRepo.GetContributions(uint(userID))
+	// The last twelve months, or a calendar year with ?year=
+	to := time.Now()
+	from := to.AddDate(-1, 0, 0)
+	if y := c.Query("year"); y != "" {
+		year, err := strconv.Atoi(y)
+		if err != nil || year < 2008 || year > to.Year() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid year"})
+			return
+		}
+		from = time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+		to = time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC)
+	}
+
+	contributions, err := h.githubRepo.GetContributions(uint(userID), from, to)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -108,6 +129,75 @@ func (h *GitHubHandler) GetContributions(c *gin.Context) {
 	c.JSON(http.StatusOK, contributions)
 }
 
+// GetContributionYears returns a user's contribution summary per calendar
+// year, latest first.
+func (h *GitHubHandler) GetContributionYears(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("userId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+
+	years, err := h.githubRepo.GetContributionYears(uint(userID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, years)
+}
+
+// GetMetrics returns a user's pull request, review and issue counts for each
+// of the last ?count= weekly or monthly periods (?period=weekly|monthly).
+func (h *GitHubHandler) GetMetrics(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("userId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+
+	period := model.ReportPeriodWeekly
+	if c.Query("period") == "monthly" {
+		period = model.ReportPeriodMonthly
+	}
+	count, _ := strconv.Atoi(c.DefaultQuery("count", "12"))
+	if count < 1 || count > 52 {
+		count = 12
+	}
+
+	metrics, err := h.githubRepo.GetMetricsBreakdown(uint(userID), period, count)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, metrics)
+}
+
+// GetActivity returns a user's recent GitHub activity received via webhooks.
+// Activity in private repositories is only shown to the user themselves.
+func (h *GitHubHandler) GetActivity(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("userId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "30"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 30
+	}
+
+	includePrivate := c.GetUint("userID") == uint(userID)
+	activities, err := h.activityRepo.FindByUserID(uint(userID), includePrivate, page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, activities)
+}
+
 func (h *GitHubHandler) GetLanguages(c *gin.Context) {
 	userID, err := strconv.ParseUint(c.Param("userId"), 10, 64)
 	if err != nil {
@@ -146,7 +236,7 @@ func (h *GitHubHandler) Sync(c *gin.Context) {
 		return
 	}
 
-	if err := h.githubService.SyncData(user); err != nil {
+	if _, err := h.syncService.Sync(model.IntegrationGitHub, user); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -165,6 +255,7 @@ func (h *GitHubHandler) Disconnect(c *gin.Context) {
 	user.GitHubToken = ""
 	user.GitHubUsername = ""
 	user.GitHubConnected = false
+	user.GitHubCreatedAt = nil
 
 	if err := h.userRepo.Update(user); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -172,6 +263,7 @@ func (h *GitHubHandler) Disconnect(c *gin.Context) {
 	}
 
 	h.githubRepo.DeleteUserData(userID)
+	h.syncService.Forget(model.IntegrationGitHub, userID)
 
 	c.JSON(http.StatusOK, gin.H{"message": "github disconnected"})
 }
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/norman6464/devsync/backend/internal/service"
+)
+
+// maxWebhookPayload matches GitHub's own cap on webhook payloads
+const maxWebhookPayload = 25 << 20
+
+// ingestedGitHubEvents are the webhook events we process; others (e.g. ping)
+// are acknowledged and dropped. star and fork only update repository counts.
+var ingestedGitHubEvents = map[string]bool{
+	"push": true, "pull_request": true, "issues": true, "release": true,
+	"star": true, "fork": true,
+}
+
+type GitHubWebhookHandler struct {
+	webhookService *service.GitHubWebhookService
+}
+
+func NewGitHubWebhookHandler(webhookService *service.GitHubWebhookService) *GitHubWebhookHandler {
+	return &GitHubWebhookHandler{webhookService: webhookService}
+}
+
+// Receive accepts a webhook delivery from GitHub. Deliveries are verified
+// against the shared secret and processed in the background.
+func (h *GitHubWebhookHandler) Receive(c *gin.Context) {
+	if !h.webhookService.Enabled() {
+		c.JSON(http.StatusNotFound, gin.H{"error": "webhooks are not configured"})
+		return
+	}
+
+	payload, err := io.ReadAll(io.LimitReader(c.Request.Body, maxWebhookPayload+1))
+	if err != nil || len(payload) > maxWebhookPayload {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload"})
+		return
+	}
+	if err := h.webhookService.Verify(c.GetHeader("X-Hub-Signature-256"), payload); err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	event := c.GetHeader("X-GitHub-Event")
+	deliveryID := c.GetHeader("X-GitHub-Delivery")
+	if !ingestedGitHubEvents[event] {
+		c.JSON(http.StatusOK, gin.H{"message": "event ignored"})
+		return
+	}
+	if deliveryID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "missing delivery id"})
+		return
+	}
+
+	if err := h.webhookService.Enqueue(event, deliveryID, payload); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to queue event"})
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"message": "event accepted"})
+}
@@ -8,14 +8,16 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/norman6464/devsync/backend/internal/model"
 	"github.com/norman6464/devsync/backend/internal/repository"
+	"github.com/norman6464/devsync/backend/internal/service"
 )
 
 type LearningGoalHandler struct {
-	goalRepo *repository.LearningGoalRepository
+	goalRepo     *repository.LearningGoalRepository
+	badgeService *service.BadgeService
 }
 
-func NewLearningGoalHandler(goalRepo *repository.LearningGoalRepository) *LearningGoalHandler {
-	return &LearningGoalHandler{goalRepo: goalRepo}
+func NewLearningGoalHandler(goalRepo *repository.LearningGoalRepository, badgeService *service.BadgeService) *LearningGoalHandler {
+	return &LearningGoalHandler{goalRepo: goalRepo, badgeService: badgeService}
 }
 
 // Create creates a new learning goal
@@ -144,6 +146,9 @@ func (h *LearningGoalHandler) Update(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update goal"})
 		return
 	}
+	if goal.Status == model.GoalStatusCompleted {
+		h.badgeService.Evaluate(goal.UserID)
+	}
 
 	c.JSON(http.StatusOK, goal)
 }
@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/norman6464/devsync/backend/internal/repository"
+)
+
+// memberStore stores the members of groups users join, such as chat rooms
+// and teams
+type memberStore[M any] interface {
+	FindOwnerID(groupID uint) (uint, error)
+	IsMember(groupID, userID uint) (bool, error)
+	GetMembers(groupID uint) ([]M, error)
+	AddMember(groupID, userID uint) error
+	RemoveMember(groupID, userID uint) error
+}
+
+// groupMembers serves the member endpoints chat rooms and teams share.
+type groupMembers[M any] struct {
+	store memberStore[M]
+	// noun names the group in error messages
+	noun string
+	// ownerAdds restricts adding members to the group's owner
+	ownerAdds bool
+}
+
+// groupID parses the :id parameter
+func (g groupMembers[M]) groupID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + g.noun + " id"})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// requireMember responds 403 unless the current user belongs to the group
+func (g groupMembers[M]) requireMember(c *gin.Context, groupID uint) bool {
+	isMember, err := g.store.IsMember(groupID, c.GetUint("userID"))
+	if err != nil || !isMember {
+		c.JSON(http.StatusForbidden, gin.H{"error": "not a member"})
+		return false
+	}
+	return true
+}
+
+// memberIDs returns the distinct users in ids other than ownerID, for
+// creating a group with them as members
+func memberIDs(ids []uint, ownerID uint) []uint {
+	seen := map[uint]bool{ownerID: true}
+	var members []uint
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			members = append(members, id)
+		}
+	}
+	return members
+}
+
+// addError responds to an error adding members to a group
+func addError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repository.ErrAlreadyMember):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrUnknownMember):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+func (g groupMembers[M]) list(c *gin.Context) {
+	groupID, ok := g.groupID(c)
+	if !ok || !g.requireMember(c, groupID) {
+		return
+	}
+
+	members, err := g.store.GetMembers(groupID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, members)
+}
+
+func (g groupMembers[M]) add(c *gin.Context) {
+	groupID, ok := g.groupID(c)
+	if !ok {
+		return
+	}
+	if g.ownerAdds {
+		ownerID, err := g.store.FindOwnerID(groupID)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": g.noun + " not found"})
+			return
+		}
+		if ownerID != c.GetUint("userID") {
+			c.JSON(http.StatusForbidden, gin.H{"error": "only owner can add members"})
+			return
+		}
+	} else if !g.requireMember(c, groupID) {
+		return
+	}
+
+	var input struct {
+		UserID uint `json:"user_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := g.store.AddMember(groupID, input.UserID); err != nil {
+		addError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "member added"})
+}
+
+func (g groupMembers[M]) remove(c *gin.Context) {
+	userID := c.GetUint("userID")
+	groupID, ok := g.groupID(c)
+	if !ok {
+		return
+	}
+	targetID, err := strconv.ParseUint(c.Param("userId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+
+	ownerID, err := g.store.FindOwnerID(groupID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": g.noun + " not found"})
+		return
+	}
+
+	// Owner can remove anyone, others can only remove themselves
+	if ownerID != userID && userID != uint(targetID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "only owner can remove members"})
+		return
+	}
+
+	if err := g.store.RemoveMember(groupID, uint(targetID)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "member removed"})
+}
@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/norman6464/devsync/backend/internal/repository"
+)
+
+type MentionHandler struct {
+	repo *repository.MentionRepository
+}
+
+func NewMentionHandler(repo *repository.MentionRepository) *MentionHandler {
+	return &MentionHandler{repo: repo}
+}
+
+// GetMyMentions returns the feed of content mentioning the current user
+func (h *MentionHandler) GetMyMentions(c *gin.Context) {
+	userID := c.GetUint("userID")
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 50 {
+		limit = 20
+	}
+
+	mentions, err := h.repo.FindByUserID(userID, page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	total, err := h.repo.CountByUserID(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"mentions": mentions,
+		"total":    total,
+	})
+}
@@ -7,15 +7,17 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/norman6464/devsync/backend/internal/model"
 	"github.com/norman6464/devsync/backend/internal/repository"
+	"github.com/norman6464/devsync/backend/internal/service"
 )
 
 type MessageHandler struct {
-	repo             *repository.MessageRepository
-	notificationRepo *repository.NotificationRepository
+	repo                *repository.MessageRepository
+	notificationService *service.NotificationService
+	mentionService      *service.MentionService
 }
 
-func NewMessageHandler(repo *repository.MessageRepository, notificationRepo *repository.NotificationRepository) *MessageHandler {
-	return &MessageHandler{repo: repo, notificationRepo: notificationRepo}
+func NewMessageHandler(repo *repository.MessageRepository, notificationService *service.NotificationService, mentionService *service.MentionService) *MessageHandler {
+	return &MessageHandler{repo: repo, notificationService: notificationService, mentionService: mentionService}
 }
 
 func (h *MessageHandler) GetConversations(c *gin.Context) {
@@ -77,14 +79,13 @@ func (h *MessageHandler) SendMessage(c *gin.Context) {
 	}
 
 	// Create notification for message receiver
-	go func(senderID, receiverID uint) {
-		notification := &model.Notification{
-			UserID:  receiverID,
-			Type:    model.NotificationTypeMessage,
-			ActorID: senderID,
-		}
-		h.notificationRepo.Create(notification)
-	}(userID, uint(receiverID))
+	h.notificationService.NotifyMessage(userID, uint(receiverID))
+
+	// Only the receiver can read a direct message, so only they can be mentioned
+	h.mentionService.EnqueueSync(userID, service.MentionTarget{
+		SourceType: model.MentionSourceMessage,
+		SourceID:   msg.ID,
+	}, msg.Content, []uint{msg.ReceiverID})
 
 	c.JSON(http.StatusCreated, msg)
 }
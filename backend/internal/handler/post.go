@@ -7,15 +7,18 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/norman6464/devsync/backend/internal/model"
 	"github.com/norman6464/devsync/backend/internal/repository"
+	"github.com/norman6464/devsync/backend/internal/service"
 )
 
 type PostHandler struct {
-	repo             *repository.PostRepository
-	notificationRepo *repository.NotificationRepository
+	repo                *repository.PostRepository
+	notificationService *service.NotificationService
+	mentionService      *service.MentionService
+	badgeService        *service.BadgeService
 }
 
-func NewPostHandler(repo *repository.PostRepository, notificationRepo *repository.NotificationRepository) *PostHandler {
-	return &PostHandler{repo: repo, notificationRepo: notificationRepo}
+func NewPostHandler(repo *repository.PostRepository, notificationService *service.NotificationService, mentionService *service.MentionService, badgeService *service.BadgeService) *PostHandler {
+	return &PostHandler{repo: repo, notificationService: notificationService, mentionService: mentionService, badgeService: badgeService}
 }
 
 func (h *PostHandler) Create(c *gin.Context) {
@@ -42,22 +45,10 @@ func (h *PostHandler) Create(c *gin.Context) {
 	}
 
 	// Create notifications for followers
-	go func(postID, actorID uint) {
-		followerIDs, err := h.notificationRepo.GetFollowerIDs(actorID)
-		if err != nil || len(followerIDs) == 0 {
-			return
-		}
-		var notifications []*model.Notification
-		for _, followerID := range followerIDs {
-			notifications = append(notifications, &model.Notification{
-				UserID:  followerID,
-				Type:    model.NotificationTypePost,
-				ActorID: actorID,
-				PostID:  &postID,
-			})
-		}
-		h.notificationRepo.CreateBatch(notifications)
-	}(post.ID, userID)
+	h.notificationService.NotifyFollowers(post.ID, userID)
+
+	h.mentionService.EnqueueSync(userID, postMentionTarget(post.ID), post.Content, nil)
+	h.badgeService.Evaluate(userID)
 
 	post, _ = h.repo.FindByID(post.ID)
 	c.JSON(http.StatusCreated, post)
@@ -140,6 +131,9 @@ func (h *PostHandler) Update(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+
+	h.mentionService.EnqueueSync(userID, postMentionTarget(post.ID), post.Content, nil)
+
 	c.JSON(http.StatusOK, post)
 }
 
@@ -210,6 +204,10 @@ func (h *PostHandler) Like(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	// The like may earn the author a badge
+	if post, err := h.repo.FindByID(uint(id)); err == nil {
+		h.badgeService.Evaluate(post.UserID)
+	}
 	c.JSON(http.StatusOK, gin.H{"message": "liked"})
 }
 
@@ -264,6 +262,13 @@ func (h *PostHandler) CreateComment(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+
+	postID := comment.PostID
+	h.mentionService.EnqueueSync(userID, service.MentionTarget{
+		SourceType: model.MentionSourceComment,
+		SourceID:   comment.ID,
+		PostID:     &postID,
+	}, comment.Content, nil)
 	c.JSON(http.StatusCreated, comment)
 }
 
@@ -279,5 +284,10 @@ func (h *PostHandler) DeleteComment(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	h.mentionService.Remove(model.MentionSourceComment, uint(commentID))
 	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
 }
+
+func postMentionTarget(postID uint) service.MentionTarget {
+	return service.MentionTarget{SourceType: model.MentionSourcePost, SourceID: postID, PostID: &postID}
+}
@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/norman6464/devsync/backend/internal/repository"
+)
+
+type PrivacyHandler struct {
+	repo *repository.PrivacyRepository
+}
+
+func NewPrivacyHandler(repo *repository.PrivacyRepository) *PrivacyHandler {
+	return &PrivacyHandler{repo: repo}
+}
+
+// Get returns the current user's privacy settings
+func (h *PrivacyHandler) Get(c *gin.Context) {
+	userID := c.GetUint("userID")
+	settings, err := h.repo.FindByUserID(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, settings)
+}
+
+// Update changes the current user's privacy settings. Omitted fields keep
+// their current value.
+func (h *PrivacyHandler) Update(c *gin.Context) {
+	userID := c.GetUint("userID")
+	settings, err := h.repo.FindByUserID(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	var input struct {
+		ProfilePublic     *bool `json:"profile_public"`
+		ShowBadges        *bool `json:"show_badges"`
+		ShowContributions *bool `json:"show_contributions"`
+		ShowLanguages     *bool `json:"show_languages"`
+		ShowProjects      *bool `json:"show_projects"`
+		ShowArticles      *bool `json:"show_articles"`
+		ShowRoadmaps      *bool `json:"show_roadmaps"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if input.ProfilePublic != nil {
+		settings.ProfilePublic = *input.ProfilePublic
+	}
+	if input.ShowBadges != nil {
+		settings.ShowBadges = *input.ShowBadges
+	}
+	if input.ShowContributions != nil {
+		settings.ShowContributions = *input.ShowContributions
+	}
+	if input.ShowLanguages != nil {
+		settings.ShowLanguages = *input.ShowLanguages
+	}
+	if input.ShowProjects != nil {
+		settings.ShowProjects = *input.ShowProjects
+	}
+	if input.ShowArticles != nil {
+		settings.ShowArticles = *input.ShowArticles
+	}
+	if input.ShowRoadmaps != nil {
+		settings.ShowRoadmaps = *input.ShowRoadmaps
+	}
+
+	if err := h.repo.Upsert(settings); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, settings)
+}
@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/norman6464/devsync/backend/internal/model"
+	"github.com/norman6464/devsync/backend/internal/repository"
+	"github.com/norman6464/devsync/backend/internal/service"
+	"gorm.io/gorm"
+)
+
+type PublicProfileHandler struct {
+	badgeService     *service.BadgeService
+	handleService    *service.HandleService
+	privacyRepo      *repository.PrivacyRepository
+	githubRepo       *repository.GitHubRepository
+	contributionRepo *repository.ContributionRepository
+	projectRepo      *repository.ProjectRepository
+	articleRepo      *repository.ArticleRepository
+	roadmapRepo      *repository.RoadmapRepository
+}
+
+func NewPublicProfileHandler(
+	badgeService *service.BadgeService,
+	handleService *service.HandleService,
+	privacyRepo *repository.PrivacyRepository,
+	githubRepo *repository.GitHubRepository,
+	contributionRepo *repository.ContributionRepository,
+	projectRepo *repository.ProjectRepository,
+	articleRepo *repository.ArticleRepository,
+	roadmapRepo *repository.RoadmapRepository,
+) *PublicProfileHandler {
+	return &PublicProfileHandler{
+		badgeService:     badgeService,
+		handleService:    handleService,
+		privacyRepo:      privacyRepo,
+		githubRepo:       githubRepo,
+		contributionRepo: contributionRepo,
+		projectRepo:      projectRepo,
+		articleRepo:      articleRepo,
+		roadmapRepo:      roadmapRepo,
+	}
+}
+
+// maxProfileArticles caps the articles listed on a public profile
+const maxProfileArticles = 20
+
+type publicProfile struct {
+	User             model.PublicUser           `json:"user"`
+	Badges           []service.BadgeResult      `json:"badges,omitempty"`
+	Contributions    []model.Contribution       `json:"contributions,omitempty"`
+	Languages        []model.GitHubLanguageStat `json:"languages,omitempty"`
+	FeaturedProjects []model.Project            `json:"featured_projects,omitempty"`
+	// Latest articles from every source, with totals
+	Articles          []model.ExternalArticle `json:"articles,omitempty"`
+	ArticleStats      *model.ArticleStats     `json:"article_stats,omitempty"`
+	CompletedRoadmaps []model.Roadmap         `json:"completed_roadmaps,omitempty"`
+}
+
+// GetProfile returns a user's shareable portfolio. Anonymous visitors only see
+// profiles that were made public; the owner can always preview their own.
+func (h *PublicProfileHandler) GetProfile(c *gin.Context) {
+	user, settings, ok := findPublicUser(c, h.handleService, h.privacyRepo)
+	if !ok {
+		return
+	}
+	isOwner := c.GetUint("userID") == user.ID
+
+	profile := publicProfile{User: model.NewPublicUser(user)}
+
+	if settings.ShowBadges {
+		badges, err := h.badgeService.Results(user.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		for _, badge := range badges {
+			if badge.Earned {
+				profile.Badges = append(profile.Badges, badge)
+			}
+		}
+	}
+
+	if settings.ShowContributions {
+		profile.Contributions, _ = h.contributionRepo.GetContributions(user.ID, time.Now().AddDate(-1, 0, 0), time.Now())
+	}
+
+	if settings.ShowLanguages {
+		profile.Languages, _ = h.githubRepo.GetLanguageStats(user.ID)
+	}
+
+	if settings.ShowProjects {
+		projects, _ := h.projectRepo.FindFeaturedByUserID(user.ID)
+		for i := range projects {
+			// Never expose details of a private linked repository
+			if projects[i].GithubRepo != nil && projects[i].GithubRepo.IsPrivate {
+				projects[i].GithubRepo = nil
+			}
+		}
+		profile.FeaturedProjects = projects
+	}
+
+	if settings.ShowArticles {
+		profile.Articles, _ = h.articleRepo.GetArticles(user.ID, "", false, 1, maxProfileArticles)
+		profile.ArticleStats, _ = h.articleRepo.GetStats(user.ID, "", false)
+	}
+
+	if settings.ShowRoadmaps {
+		profile.CompletedRoadmaps, _ = h.roadmapRepo.GetCompletedPublicByUserID(user.ID)
+	}
+
+	c.Header("Vary", "Authorization")
+	if isOwner {
+		c.Header("Cache-Control", "private, no-store")
+	} else {
+		c.Header("Cache-Control", "public, max-age=300")
+	}
+	c.JSON(http.StatusOK, profile)
+}
+
+// findPublicUser resolves the :handle of a public route and checks that the
+// profile is visible to the caller. On failure it writes the response (a
+// redirect for renamed handles, 404 otherwise) and returns ok=false.
+func findPublicUser(c *gin.Context, handleService *service.HandleService, privacyRepo *repository.PrivacyRepository) (*model.User, *model.PrivacySettings, bool) {
+	handle := c.Param("handle")
+	user, redirected, err := handleService.Resolve(handle)
+	if err == nil && user.DeletionScheduledAt != nil {
+		err = gorm.ErrRecordNotFound
+	}
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		return nil, nil, false
+	}
+	if redirected {
+		location := strings.Replace(c.Request.URL.Path, "/users/"+handle, "/users/"+user.Handle, 1)
+		if c.Request.URL.RawQuery != "" {
+			location += "?" + c.Request.URL.RawQuery
+		}
+		c.Header("Location", location)
+		c.JSON(http.StatusMovedPermanently, gin.H{"handle": user.Handle})
+		return nil, nil, false
+	}
+
+	settings, err := privacyRepo.FindByUserID(user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, nil, false
+	}
+	if !settings.ProfilePublic && c.GetUint("userID") != user.ID {
+		// Private profiles are indistinguishable from missing ones
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		return nil, nil, false
+	}
+	return user, settings, true
+}
@@ -7,14 +7,16 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/norman6464/devsync/backend/internal/model"
 	"github.com/norman6464/devsync/backend/internal/repository"
+	"github.com/norman6464/devsync/backend/internal/service"
 )
 
 type QuestionHandler struct {
-	repo *repository.QuestionRepository
+	repo           *repository.QuestionRepository
+	mentionService *service.MentionService
 }
 
-func NewQuestionHandler(repo *repository.QuestionRepository) *QuestionHandler {
-	return &QuestionHandler{repo: repo}
+func NewQuestionHandler(repo *repository.QuestionRepository, mentionService *service.MentionService) *QuestionHandler {
+	return &QuestionHandler{repo: repo, mentionService: mentionService}
 }
 
 type CreateQuestionRequest struct {
@@ -54,6 +56,8 @@ func (h *QuestionHandler) Create(c *gin.Context) {
 		return
 	}
 
+	h.mentionService.EnqueueSync(userID, questionMentionTarget(question.ID), question.Body, nil)
+
 	c.JSON(http.StatusCreated, question)
 }
 
@@ -187,6 +191,8 @@ func (h *QuestionHandler) Update(c *gin.Context) {
 		return
 	}
 
+	h.mentionService.EnqueueSync(userID, questionMentionTarget(question.ID), question.Body, nil)
+
 	c.JSON(http.StatusOK, question)
 }
 
@@ -213,6 +219,7 @@ func (h *QuestionHandler) Delete(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete question"})
 		return
 	}
+	h.mentionService.Remove(model.MentionSourceQuestion, uint(id))
 
 	c.JSON(http.StatusOK, gin.H{"message": "Question deleted successfully"})
 }
@@ -254,3 +261,7 @@ func (h *QuestionHandler) RemoveVote(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "Vote removed successfully"})
 }
+
+func questionMentionTarget(questionID uint) service.MentionTarget {
+	return service.MentionTarget{SourceType: model.MentionSourceQuestion, SourceID: questionID, QuestionID: &questionID}
+}
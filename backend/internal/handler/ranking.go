@@ -2,22 +2,115 @@ package handler
 
 import (
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/norman6464/devsync/backend/internal/model"
 	"github.com/norman6464/devsync/backend/internal/repository"
+	"github.com/norman6464/devsync/backend/internal/service"
 )
 
 type RankingHandler struct {
-	repo *repository.RankingRepository
+	repo           *repository.RankingRepository
+	chatRoomRepo   *repository.ChatRoomRepository
+	teamRepo       *repository.TeamRepository
+	rankingService *service.RankingService
 }
 
-func NewRankingHandler(repo *repository.RankingRepository) *RankingHandler {
-	return &RankingHandler{repo: repo}
+func NewRankingHandler(repo *repository.RankingRepository, chatRoomRepo *repository.ChatRoomRepository, teamRepo *repository.TeamRepository, rankingService *service.RankingService) *RankingHandler {
+	return &RankingHandler{repo: repo, chatRoomRepo: chatRoomRepo, teamRepo: teamRepo, rankingService: rankingService}
+}
+
+// Ranking ranks users by ?metric= - one metric, or "composite" (the default)
+// for the configured weighting - or by custom ?weights=metric:weight,...
+// ?period=weekly|monthly|yearly|all and ?scope=all|following|room|team with
+// ?room_id= or ?team_id= choose the window and the users ranked.
+func (h *RankingHandler) Ranking(c *gin.Context) {
+	q, limit, ok := h.query(c)
+	if !ok || !h.metric(c, &q) {
+		return
+	}
+
+	if spec := c.Query("weights"); spec != "" {
+		weights, err := service.ParseRankingWeights(spec)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		q.Weights = weights
+	}
+
+	entries, err := h.rankingService.Leaderboard(q, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, entries)
+}
+
+// MyPosition returns the current user's rank, score and percentile in a
+// ranking chosen as for Ranking, even outside the top of the leaderboard.
+func (h *RankingHandler) MyPosition(c *gin.Context) {
+	q, _, ok := h.query(c)
+	if !ok || !h.metric(c, &q) {
+		return
+	}
+
+	position, err := h.rankingService.Position(c.GetUint("userID"), q)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, position)
+}
+
+// History returns a user's daily rank on a leaderboard over the last ?days=
+// (default 30, at most 365).
+func (h *RankingHandler) History(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("userId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+	q := service.RankingQuery{Period: model.RankingPeriod(c.DefaultQuery("period", "weekly"))}
+	if !q.Period.Valid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid period"})
+		return
+	}
+	if !h.metric(c, &q) {
+		return
+	}
+	days, _ := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if days < 1 || days > 365 {
+		days = 30
+	}
+
+	since := time.Now().UTC().AddDate(0, 0, -days)
+	history, err := h.rankingService.History(uint(userID), q.Metric, q.Period, since)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, history)
+}
+
+// Metrics lists the metrics users can be ranked by and the composite weights
+func (h *RankingHandler) Metrics(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"metrics":         repository.RankingMetrics(),
+		"default_weights": h.rankingService.DefaultWeights(),
+	})
 }
 
 func (h *RankingHandler) ContributionRanking(c *gin.Context) {
-	period := c.DefaultQuery("period", "weekly")
-	entries, err := h.repo.ContributionRanking(period)
+	q, limit, ok := h.query(c)
+	if !ok {
+		return
+	}
+	q.Metric = model.RankingMetricContributions
+
+	entries, err := h.rankingService.Leaderboard(q, limit)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -26,14 +119,17 @@ func (h *RankingHandler) ContributionRanking(c *gin.Context) {
 }
 
 func (h *RankingHandler) LanguageRanking(c *gin.Context) {
-	lang := c.Param("lang")
-	period := c.DefaultQuery("period", "weekly")
-	entries, err := h.repo.LanguageRanking(lang, period)
+	q, limit, ok := h.query(c)
+	if !ok {
+		return
+	}
+
+	entries, err := h.rankingService.RankLanguage(c.Param("lang"), q.Period, q.Scope)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, entries)
+	c.JSON(http.StatusOK, truncateRanking(entries, limit))
 }
 
 func (h *RankingHandler) AvailableLanguages(c *gin.Context) {
@@ -44,3 +140,69 @@ func (h *RankingHandler) AvailableLanguages(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, languages)
 }
+
+// query reads the period, scope and limit shared by every ranking. A room or
+// team can only be ranked by its members.
+func (h *RankingHandler) query(c *gin.Context) (service.RankingQuery, int, bool) {
+	q := service.RankingQuery{Period: model.RankingPeriod(c.DefaultQuery("period", "weekly"))}
+	if !q.Period.Valid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid period"})
+		return q, 0, false
+	}
+
+	userID := c.GetUint("userID")
+	switch c.DefaultQuery("scope", "all") {
+	case "all":
+	case "following":
+		q.Scope.FollowerID = userID
+	case "room":
+		roomID, err := strconv.ParseUint(c.Query("room_id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid room ID"})
+			return q, 0, false
+		}
+		if member, err := h.chatRoomRepo.IsMember(uint(roomID), userID); err != nil || !member {
+			c.JSON(http.StatusForbidden, gin.H{"error": "not a member of this room"})
+			return q, 0, false
+		}
+		q.Scope.ChatRoomID = uint(roomID)
+	case "team":
+		teamID, err := strconv.ParseUint(c.Query("team_id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid team ID"})
+			return q, 0, false
+		}
+		if member, err := h.teamRepo.IsMember(uint(teamID), userID); err != nil || !member {
+			c.JSON(http.StatusForbidden, gin.H{"error": "not a member of this team"})
+			return q, 0, false
+		}
+		q.Scope.TeamID = uint(teamID)
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid scope"})
+		return q, 0, false
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if limit < 1 || limit > 100 {
+		limit = 50
+	}
+	return q, limit, true
+}
+
+// metric reads the leaderboard named by ?metric=, defaulting to the
+// composite.
+func (h *RankingHandler) metric(c *gin.Context, q *service.RankingQuery) bool {
+	q.Metric = c.DefaultQuery("metric", service.RankingComposite)
+	if _, ok := h.rankingService.Weights(q.Metric); !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unknown metric"})
+		return false
+	}
+	return true
+}
+
+func truncateRanking(entries []model.RankingEntry, limit int) []model.RankingEntry {
+	if len(entries) > limit {
+		return entries[:limit]
+	}
+	return entries
+}
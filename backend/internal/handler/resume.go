@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/norman6464/devsync/backend/internal/service"
+)
+
+type ResumeHandler struct {
+	resumeService *service.ResumeService
+}
+
+func NewResumeHandler(resumeService *service.ResumeService) *ResumeHandler {
+	return &ResumeHandler{resumeService: resumeService}
+}
+
+// Export generates the current user's résumé. ?format= is markdown (default),
+// json (JSON Resume schema) or pdf.
+func (h *ResumeHandler) Export(c *gin.Context) {
+	format := c.DefaultQuery("format", "markdown")
+	if format != "markdown" && format != "json" && format != "pdf" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be markdown, json or pdf"})
+		return
+	}
+
+	resume, err := h.resumeService.Build(c.GetUint("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to generate resume"})
+		return
+	}
+
+	name := "resume"
+	if resume.User.Handle != "" {
+		name += "-" + resume.User.Handle
+	}
+	c.Header("Cache-Control", "private, no-store")
+
+	switch format {
+	case "json":
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.json"`, name))
+		c.JSON(http.StatusOK, service.ToJSONResume(resume))
+	case "pdf":
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.pdf"`, name))
+		c.Data(http.StatusOK, "application/pdf", service.RenderResumePDF(resume))
+	default:
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.md"`, name))
+		c.Data(http.StatusOK, "text/markdown; charset=utf-8", service.RenderResumeMarkdown(resume))
+	}
+}
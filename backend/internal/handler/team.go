@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/norman6464/devsync/backend/internal/model"
+	"github.com/norman6464/devsync/backend/internal/repository"
+)
+
+// TeamHandler manages teams. Their leaderboards are served by RankingHandler
+// with ?scope=team&team_id=. Only the owner adds members, since a team's
+// leaderboard shows its members' activity to each other.
+type TeamHandler struct {
+	teamRepo *repository.TeamRepository
+	members  groupMembers[model.TeamMember]
+}
+
+func NewTeamHandler(teamRepo *repository.TeamRepository) *TeamHandler {
+	return &TeamHandler{
+		teamRepo: teamRepo,
+		members:  groupMembers[model.TeamMember]{store: teamRepo, noun: "team", ownerAdds: true},
+	}
+}
+
+func (h *TeamHandler) Create(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var input struct {
+		Name        string `json:"name" binding:"required"`
+		Description string `json:"description"`
+		MemberIDs   []uint `json:"member_ids"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	team := &model.Team{
+		Name:        input.Name,
+		Description: input.Description,
+		OwnerID:     userID,
+	}
+	if err := h.teamRepo.Create(team, memberIDs(input.MemberIDs, userID)); err != nil {
+		addError(c, err)
+		return
+	}
+
+	team, _ = h.teamRepo.FindByID(team.ID)
+	c.JSON(http.StatusCreated, team)
+}
+
+func (h *TeamHandler) GetMyTeams(c *gin.Context) {
+	userID := c.GetUint("userID")
+	teams, err := h.teamRepo.FindByUserID(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, teams)
+}
+
+func (h *TeamHandler) GetByID(c *gin.Context) {
+	teamID, ok := h.members.groupID(c)
+	if !ok || !h.members.requireMember(c, teamID) {
+		return
+	}
+
+	team, err := h.teamRepo.FindByID(teamID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "team not found"})
+		return
+	}
+	c.JSON(http.StatusOK, team)
+}
+
+func (h *TeamHandler) Update(c *gin.Context) {
+	userID := c.GetUint("userID")
+	teamID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid team id"})
+		return
+	}
+
+	team, err := h.teamRepo.FindByID(uint(teamID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "team not found"})
+		return
+	}
+	if team.OwnerID != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "only owner can update"})
+		return
+	}
+
+	var input struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if input.Name != "" {
+		team.Name = input.Name
+	}
+	team.Description = input.Description
+
+	if err := h.teamRepo.Update(team); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, team)
+}
+
+func (h *TeamHandler) Delete(c *gin.Context) {
+	userID := c.GetUint("userID")
+	teamID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid team id"})
+		return
+	}
+
+	team, err := h.teamRepo.FindByID(uint(teamID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "team not found"})
+		return
+	}
+	if team.OwnerID != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "only owner can delete"})
+		return
+	}
+
+	if err := h.teamRepo.Delete(uint(teamID)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "team deleted"})
+}
+
+func (h *TeamHandler) GetMembers(c *gin.Context) {
+	h.members.list(c)
+}
+
+func (h *TeamHandler) AddMember(c *gin.Context) {
+	h.members.add(c)
+}
+
+func (h *TeamHandler) RemoveMember(c *gin.Context) {
+	h.members.remove(c)
+}
@@ -16,12 +16,7 @@ type UploadHandler struct {
 	uploadDir string
 }
 
-func NewUploadHandler() *UploadHandler {
-	uploadDir := os.Getenv("UPLOAD_DIR")
-	if uploadDir == "" {
-		uploadDir = "./uploads"
-	}
-
+func NewUploadHandler(uploadDir string) *UploadHandler {
 	// Create upload directory if not exists
 	if err := os.MkdirAll(uploadDir, 0755); err != nil {
 		panic(fmt.Sprintf("Failed to create upload directory: %v", err))
@@ -1,19 +1,45 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/norman6464/devsync/backend/internal/model"
 	"github.com/norman6464/devsync/backend/internal/repository"
+	"github.com/norman6464/devsync/backend/internal/service"
 )
 
 type UserHandler struct {
-	repo *repository.UserRepository
+	repo          *repository.UserRepository
+	handleService *service.HandleService
+	syncService   *service.SyncService
 }
 
-func NewUserHandler(repo *repository.UserRepository) *UserHandler {
-	return &UserHandler{repo: repo}
+func NewUserHandler(repo *repository.UserRepository, handleService *service.HandleService, syncService *service.SyncService) *UserHandler {
+	return &UserHandler{repo: repo, handleService: handleService, syncService: syncService}
+}
+
+// userWithSyncStatus is a user with the sync state of their connected accounts
+type userWithSyncStatus struct {
+	*model.User
+	SyncStatus []model.IntegrationSync `json:"sync_status"`
+}
+
+// withSyncStatus attaches the user's sync state. Sync errors can mention
+// account details, so they are only included for the user themselves.
+func withSyncStatus(user *model.User, syncService *service.SyncService, includeErrors bool) userWithSyncStatus {
+	status, _ := syncService.Status(user.ID)
+	if status == nil {
+		status = []model.IntegrationSync{}
+	}
+	if !includeErrors {
+		for i := range status {
+			status[i].LastSyncError = ""
+		}
+	}
+	return userWithSyncStatus{User: user, SyncStatus: status}
 }
 
 func (h *UserHandler) GetAll(c *gin.Context) {
@@ -48,7 +74,7 @@ func (h *UserHandler) GetByID(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
 		return
 	}
-	c.JSON(http.StatusOK, user)
+	c.JSON(http.StatusOK, withSyncStatus(user, h.syncService, c.GetUint("userID") == user.ID))
 }
 
 func (h *UserHandler) Update(c *gin.Context) {
@@ -77,6 +103,7 @@ func (h *UserHandler) Update(c *gin.Context) {
 		SkillsLanguages     *string `json:"skills_languages"`
 		SkillsFrameworks    *string `json:"skills_frameworks"`
 		OnboardingCompleted *bool   `json:"onboarding_completed"`
+		GitHubAutoPost      *bool   `json:"github_auto_post"`
 	}
 	if err := c.ShouldBindJSON(&input); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -97,6 +124,9 @@ func (h *UserHandler) Update(c *gin.Context) {
 	if input.OnboardingCompleted != nil {
 		existing.OnboardingCompleted = *input.OnboardingCompleted
 	}
+	if input.GitHubAutoPost != nil {
+		existing.GitHubAutoPost = *input.GitHubAutoPost
+	}
 
 	if err := h.repo.Update(existing); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -104,3 +134,83 @@ func (h *UserHandler) Update(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, existing)
 }
+
+// GetByHandle looks a user up by handle. Old handles still in their grace
+// period redirect to the user's current handle.
+func (h *UserHandler) GetByHandle(c *gin.Context) {
+	user, redirected, err := h.handleService.Resolve(c.Param("handle"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		return
+	}
+	if redirected {
+		c.Header("Location", "/api/v1/users/by-handle/"+user.Handle)
+		c.JSON(http.StatusMovedPermanently, gin.H{"handle": user.Handle, "user_id": user.ID})
+		return
+	}
+	c.JSON(http.StatusOK, user)
+}
+
+// UpdateHandle renames the current user's handle
+func (h *UserHandler) UpdateHandle(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	userID := c.GetUint("userID")
+	if userID != uint(id) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "cannot update other user's handle"})
+		return
+	}
+
+	user, err := h.repo.FindByID(userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		return
+	}
+
+	var input struct {
+		Handle string `json:"handle" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.handleService.Change(user, input.Handle); err != nil {
+		switch {
+		case errors.Is(err, service.ErrHandleTaken):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrHandleInvalid), errors.Is(err, service.ErrHandleReserved):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, user)
+}
+
+// GetHandleHistory returns the current user's previous handles
+func (h *UserHandler) GetHandleHistory(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	userID := c.GetUint("userID")
+	if userID != uint(id) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "cannot view other user's handle history"})
+		return
+	}
+
+	history, err := h.repo.GetHandleHistory(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, history)
+}
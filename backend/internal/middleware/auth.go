@@ -35,3 +35,17 @@ func AuthRequired(authService *service.AuthService) gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// AuthOptional identifies the caller when a valid bearer token is present but
+// never rejects the request, for routes that anonymous visitors may also use.
+func AuthOptional(authService *service.AuthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		parts := strings.SplitN(c.GetHeader("Authorization"), " ", 2)
+		if len(parts) == 2 && parts[0] == "Bearer" {
+			if userID, err := authService.ValidateToken(parts[1]); err == nil {
+				c.Set("userID", userID)
+			}
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,28 @@
+DROP TABLE IF EXISTS group_messages;
+DROP TABLE IF EXISTS chat_room_members;
+DROP TABLE IF EXISTS chat_rooms;
+DROP TABLE IF EXISTS roadmap_steps;
+DROP TABLE IF EXISTS roadmaps;
+DROP TABLE IF EXISTS book_reviews;
+DROP TABLE IF EXISTS resource_saves;
+DROP TABLE IF EXISTS resource_likes;
+DROP TABLE IF EXISTS learning_resources;
+DROP TABLE IF EXISTS projects;
+DROP TABLE IF EXISTS learning_goals;
+DROP TABLE IF EXISTS qiita_articles;
+DROP TABLE IF EXISTS zenn_articles;
+DROP TABLE IF EXISTS password_reset_tokens;
+DROP TABLE IF EXISTS notifications;
+DROP TABLE IF EXISTS answer_votes;
+DROP TABLE IF EXISTS answers;
+DROP TABLE IF EXISTS question_votes;
+DROP TABLE IF EXISTS questions;
+DROP TABLE IF EXISTS messages;
+DROP TABLE IF EXISTS comments;
+DROP TABLE IF EXISTS likes;
+DROP TABLE IF EXISTS posts;
+DROP TABLE IF EXISTS git_hub_repositories;
+DROP TABLE IF EXISTS git_hub_language_stats;
+DROP TABLE IF EXISTS git_hub_contributions;
+DROP TABLE IF EXISTS follows;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,422 @@
+-- Baseline schema, matching what AutoMigrate created before versioned
+-- migrations existed. Everything is IF NOT EXISTS so databases that were
+-- already created by AutoMigrate can apply it as a no-op. Columns and tables
+-- added since then belong in later migrations, never here.
+
+CREATE TABLE IF NOT EXISTS users (
+    id bigserial,
+    name text NOT NULL,
+    email text NOT NULL,
+    password text,
+    avatar_url text,
+    bio text,
+    git_hub_id bigint,
+    git_hub_username text,
+    git_hub_token text,
+    git_hub_connected boolean DEFAULT false,
+    zenn_username text,
+    qiita_username text,
+    skills_languages text,
+    skills_frameworks text,
+    onboarding_completed boolean DEFAULT false,
+    created_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_git_hub_id ON users (git_hub_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users (email);
+
+CREATE TABLE IF NOT EXISTS follows (
+    id bigserial,
+    follower_id bigint NOT NULL,
+    followee_id bigint NOT NULL,
+    created_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_follows_follower FOREIGN KEY (follower_id) REFERENCES users(id),
+    CONSTRAINT fk_follows_followee FOREIGN KEY (followee_id) REFERENCES users(id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_follower_following ON follows (follower_id, followee_id);
+
+CREATE TABLE IF NOT EXISTS git_hub_contributions (
+    id bigserial,
+    user_id bigint NOT NULL,
+    date timestamptz NOT NULL,
+    count bigint NOT NULL DEFAULT 0,
+    created_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_date ON git_hub_contributions (user_id, date);
+
+CREATE TABLE IF NOT EXISTS git_hub_language_stats (
+    id bigserial,
+    user_id bigint NOT NULL,
+    language text NOT NULL,
+    bytes bigint NOT NULL DEFAULT 0,
+    repo_count bigint NOT NULL DEFAULT 0,
+    updated_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_lang ON git_hub_language_stats (user_id, language);
+
+CREATE TABLE IF NOT EXISTS git_hub_repositories (
+    id bigserial,
+    user_id bigint NOT NULL,
+    git_hub_repo_id bigint NOT NULL,
+    name text NOT NULL,
+    full_name text,
+    description text,
+    language text,
+    stars bigint,
+    forks bigint,
+    is_private boolean,
+    updated_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_git_hub_repositories_git_hub_repo_id ON git_hub_repositories (git_hub_repo_id);
+CREATE INDEX IF NOT EXISTS idx_git_hub_repositories_user_id ON git_hub_repositories (user_id);
+
+CREATE TABLE IF NOT EXISTS posts (
+    id bigserial,
+    user_id bigint NOT NULL,
+    title text NOT NULL,
+    content text NOT NULL,
+    image_urls text,
+    like_count bigint DEFAULT 0,
+    comment_count bigint DEFAULT 0,
+    created_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_posts_user FOREIGN KEY (user_id) REFERENCES users(id)
+);
+CREATE INDEX IF NOT EXISTS idx_posts_user_id ON posts (user_id);
+
+CREATE TABLE IF NOT EXISTS likes (
+    id bigserial,
+    user_id bigint NOT NULL,
+    post_id bigint NOT NULL,
+    created_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_likes_post_id ON likes (post_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_post_like ON likes (user_id, post_id);
+
+CREATE TABLE IF NOT EXISTS comments (
+    id bigserial,
+    user_id bigint NOT NULL,
+    post_id bigint NOT NULL,
+    content text NOT NULL,
+    created_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_comments_user FOREIGN KEY (user_id) REFERENCES users(id)
+);
+CREATE INDEX IF NOT EXISTS idx_comments_post_id ON comments (post_id);
+CREATE INDEX IF NOT EXISTS idx_comments_user_id ON comments (user_id);
+
+CREATE TABLE IF NOT EXISTS messages (
+    id bigserial,
+    sender_id bigint NOT NULL,
+    receiver_id bigint NOT NULL,
+    content text NOT NULL,
+    read boolean DEFAULT false,
+    created_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_messages_sender FOREIGN KEY (sender_id) REFERENCES users(id),
+    CONSTRAINT fk_messages_receiver FOREIGN KEY (receiver_id) REFERENCES users(id)
+);
+CREATE INDEX IF NOT EXISTS idx_messages_receiver_id ON messages (receiver_id);
+CREATE INDEX IF NOT EXISTS idx_messages_sender_id ON messages (sender_id);
+
+CREATE TABLE IF NOT EXISTS questions (
+    id bigserial,
+    user_id bigint NOT NULL,
+    title varchar(500) NOT NULL,
+    body text NOT NULL,
+    tags text,
+    vote_count bigint DEFAULT 0,
+    answer_count bigint DEFAULT 0,
+    is_solved boolean DEFAULT false,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_questions_user FOREIGN KEY (user_id) REFERENCES users(id)
+);
+CREATE INDEX IF NOT EXISTS idx_questions_deleted_at ON questions (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_questions_user_id ON questions (user_id);
+
+CREATE TABLE IF NOT EXISTS question_votes (
+    id bigserial,
+    user_id bigint NOT NULL,
+    question_id bigint NOT NULL,
+    value bigint NOT NULL,
+    created_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_question_vote ON question_votes (user_id, question_id);
+
+CREATE TABLE IF NOT EXISTS answers (
+    id bigserial,
+    user_id bigint NOT NULL,
+    question_id bigint NOT NULL,
+    body text NOT NULL,
+    vote_count bigint DEFAULT 0,
+    is_best boolean DEFAULT false,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_answers_user FOREIGN KEY (user_id) REFERENCES users(id)
+);
+CREATE INDEX IF NOT EXISTS idx_answers_deleted_at ON answers (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_answers_question_id ON answers (question_id);
+CREATE INDEX IF NOT EXISTS idx_answers_user_id ON answers (user_id);
+
+CREATE TABLE IF NOT EXISTS answer_votes (
+    id bigserial,
+    user_id bigint NOT NULL,
+    answer_id bigint NOT NULL,
+    value bigint NOT NULL,
+    created_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_answer_vote ON answer_votes (user_id, answer_id);
+
+CREATE TABLE IF NOT EXISTS notifications (
+    id bigserial,
+    user_id bigint NOT NULL,
+    type text NOT NULL,
+    actor_id bigint NOT NULL,
+    post_id bigint,
+    question_id bigint,
+    badge_id varchar(50),
+    read boolean DEFAULT false,
+    created_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_notifications_question FOREIGN KEY (question_id) REFERENCES questions(id),
+    CONSTRAINT fk_notifications_user FOREIGN KEY (user_id) REFERENCES users(id),
+    CONSTRAINT fk_notifications_actor FOREIGN KEY (actor_id) REFERENCES users(id),
+    CONSTRAINT fk_notifications_post FOREIGN KEY (post_id) REFERENCES posts(id)
+);
+CREATE INDEX IF NOT EXISTS idx_notifications_question_id ON notifications (question_id);
+CREATE INDEX IF NOT EXISTS idx_notifications_post_id ON notifications (post_id);
+CREATE INDEX IF NOT EXISTS idx_notifications_user_id ON notifications (user_id);
+
+CREATE TABLE IF NOT EXISTS password_reset_tokens (
+    id bigserial,
+    user_id bigint NOT NULL,
+    token text NOT NULL,
+    expires_at timestamptz NOT NULL,
+    used boolean DEFAULT false,
+    created_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_password_reset_tokens_user FOREIGN KEY (user_id) REFERENCES users(id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_password_reset_tokens_token ON password_reset_tokens (token);
+CREATE INDEX IF NOT EXISTS idx_password_reset_tokens_user_id ON password_reset_tokens (user_id);
+
+CREATE TABLE IF NOT EXISTS zenn_articles (
+    id bigserial,
+    user_id bigint NOT NULL,
+    zenn_id bigint NOT NULL,
+    title text NOT NULL,
+    slug text NOT NULL,
+    emoji text,
+    article_type text,
+    liked_count bigint DEFAULT 0,
+    comments_count bigint DEFAULT 0,
+    published_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_zenn_articles_zenn_id ON zenn_articles (zenn_id);
+CREATE INDEX IF NOT EXISTS idx_zenn_articles_user_id ON zenn_articles (user_id);
+
+CREATE TABLE IF NOT EXISTS qiita_articles (
+    id bigserial,
+    user_id bigint NOT NULL,
+    qiita_id text NOT NULL,
+    title text NOT NULL,
+    url text NOT NULL,
+    likes_count bigint DEFAULT 0,
+    comments_count bigint DEFAULT 0,
+    tags text,
+    published_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_qiita_articles_qiita_id ON qiita_articles (qiita_id);
+CREATE INDEX IF NOT EXISTS idx_qiita_articles_user_id ON qiita_articles (user_id);
+
+CREATE TABLE IF NOT EXISTS learning_goals (
+    id bigserial,
+    user_id bigint NOT NULL,
+    title text NOT NULL,
+    description text,
+    category text DEFAULT 'other',
+    target_date timestamptz,
+    progress bigint DEFAULT 0,
+    status text DEFAULT 'active',
+    created_at timestamptz,
+    updated_at timestamptz,
+    completed_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_learning_goals_user_id ON learning_goals (user_id);
+
+CREATE TABLE IF NOT EXISTS projects (
+    id bigserial,
+    user_id bigint NOT NULL,
+    title varchar(200) NOT NULL,
+    description text,
+    tech_stack text,
+    demo_url varchar(500),
+    github_url varchar(500),
+    image_url varchar(500),
+    role varchar(100),
+    start_date timestamptz,
+    end_date timestamptz,
+    featured boolean DEFAULT false,
+    github_repo_id bigint,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_projects_user FOREIGN KEY (user_id) REFERENCES users(id),
+    CONSTRAINT fk_projects_github_repo FOREIGN KEY (github_repo_id) REFERENCES git_hub_repositories(id)
+);
+CREATE INDEX IF NOT EXISTS idx_projects_deleted_at ON projects (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_projects_user_id ON projects (user_id);
+
+CREATE TABLE IF NOT EXISTS learning_resources (
+    id bigserial,
+    user_id bigint NOT NULL,
+    title varchar(300) NOT NULL,
+    description text,
+    url varchar(500),
+    category varchar(50) NOT NULL,
+    difficulty varchar(50),
+    tags text,
+    image_url varchar(500),
+    is_public boolean DEFAULT true,
+    like_count bigint DEFAULT 0,
+    save_count bigint DEFAULT 0,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_learning_resources_user FOREIGN KEY (user_id) REFERENCES users(id)
+);
+CREATE INDEX IF NOT EXISTS idx_learning_resources_deleted_at ON learning_resources (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_learning_resources_user_id ON learning_resources (user_id);
+
+CREATE TABLE IF NOT EXISTS resource_likes (
+    id bigserial,
+    user_id bigint NOT NULL,
+    resource_id bigint NOT NULL,
+    created_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_resource_like ON resource_likes (user_id, resource_id);
+
+CREATE TABLE IF NOT EXISTS resource_saves (
+    id bigserial,
+    user_id bigint NOT NULL,
+    resource_id bigint NOT NULL,
+    created_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_resource_save ON resource_saves (user_id, resource_id);
+
+CREATE TABLE IF NOT EXISTS book_reviews (
+    id bigserial,
+    user_id bigint NOT NULL,
+    title varchar(300) NOT NULL,
+    author varchar(200),
+    isbn varchar(20),
+    rating bigint NOT NULL,
+    review text,
+    image_url varchar(500),
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_book_reviews_user FOREIGN KEY (user_id) REFERENCES users(id)
+);
+CREATE INDEX IF NOT EXISTS idx_book_reviews_deleted_at ON book_reviews (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_book_reviews_user_id ON book_reviews (user_id);
+
+CREATE TABLE IF NOT EXISTS roadmaps (
+    id bigserial,
+    user_id bigint NOT NULL,
+    title varchar(200) NOT NULL,
+    description text,
+    category text DEFAULT 'other',
+    is_public boolean DEFAULT false,
+    step_count bigint DEFAULT 0,
+    completed_step_count bigint DEFAULT 0,
+    progress bigint DEFAULT 0,
+    status text DEFAULT 'active',
+    created_at timestamptz,
+    updated_at timestamptz,
+    completed_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_roadmaps_user FOREIGN KEY (user_id) REFERENCES users(id)
+);
+CREATE INDEX IF NOT EXISTS idx_roadmaps_user_id ON roadmaps (user_id);
+CREATE INDEX IF NOT EXISTS idx_roadmaps_is_public ON roadmaps (is_public);
+
+CREATE TABLE IF NOT EXISTS roadmap_steps (
+    id bigserial,
+    roadmap_id bigint NOT NULL,
+    title varchar(200) NOT NULL,
+    description text,
+    order_index bigint NOT NULL DEFAULT 0,
+    is_completed boolean DEFAULT false,
+    completed_at timestamptz,
+    resource_url varchar(500),
+    created_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_roadmaps_steps FOREIGN KEY (roadmap_id) REFERENCES roadmaps(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_roadmap_steps_roadmap_id ON roadmap_steps (roadmap_id);
+
+CREATE TABLE IF NOT EXISTS chat_rooms (
+    id bigserial,
+    name varchar(100) NOT NULL,
+    description varchar(500),
+    owner_id bigint NOT NULL,
+    created_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_chat_rooms_owner FOREIGN KEY (owner_id) REFERENCES users(id)
+);
+CREATE INDEX IF NOT EXISTS idx_chat_rooms_owner_id ON chat_rooms (owner_id);
+
+CREATE TABLE IF NOT EXISTS chat_room_members (
+    id bigserial,
+    chat_room_id bigint NOT NULL,
+    user_id bigint NOT NULL,
+    joined_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_chat_room_members_chat_room FOREIGN KEY (chat_room_id) REFERENCES chat_rooms(id),
+    CONSTRAINT fk_chat_room_members_user FOREIGN KEY (user_id) REFERENCES users(id)
+);
+CREATE INDEX IF NOT EXISTS idx_chat_room_members_user_id ON chat_room_members (user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_room_user ON chat_room_members (chat_room_id, user_id);
+CREATE INDEX IF NOT EXISTS idx_chat_room_members_chat_room_id ON chat_room_members (chat_room_id);
+
+CREATE TABLE IF NOT EXISTS group_messages (
+    id bigserial,
+    chat_room_id bigint NOT NULL,
+    sender_id bigint NOT NULL,
+    content text NOT NULL,
+    created_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_group_messages_chat_room FOREIGN KEY (chat_room_id) REFERENCES chat_rooms(id),
+    CONSTRAINT fk_group_messages_sender FOREIGN KEY (sender_id) REFERENCES users(id)
+);
+CREATE INDEX IF NOT EXISTS idx_group_messages_sender_id ON group_messages (sender_id);
+CREATE INDEX IF NOT EXISTS idx_group_messages_chat_room_id ON group_messages (chat_room_id);
@@ -0,0 +1 @@
+-- Data fix-up; there is nothing to undo.
@@ -0,0 +1,3 @@
+-- Users who signed up before onboarding existed never went through it.
+-- This used to run on every boot from main.go.
+UPDATE users SET onboarding_completed = true WHERE onboarding_completed = false;
@@ -0,0 +1,24 @@
+CREATE TABLE jobs (
+    id bigserial PRIMARY KEY,
+    queue varchar(50) NOT NULL,
+    type varchar(100) NOT NULL,
+    payload jsonb NOT NULL DEFAULT '{}',
+    status varchar(20) NOT NULL,
+    attempts bigint NOT NULL DEFAULT 0,
+    max_attempts bigint NOT NULL,
+    run_at timestamptz NOT NULL,
+    unique_key varchar(200),
+    locked_at timestamptz,
+    locked_by varchar(100),
+    last_error text,
+    created_at timestamptz,
+    updated_at timestamptz,
+    finished_at timestamptz
+);
+
+-- Workers poll for the next runnable job per queue
+CREATE INDEX idx_jobs_poll ON jobs (queue, run_at, id) WHERE status = 'pending';
+CREATE INDEX idx_jobs_type ON jobs (type);
+CREATE INDEX idx_jobs_status_finished_at ON jobs (status, finished_at);
+-- Unique jobs: at most one pending or running job per key
+CREATE UNIQUE INDEX idx_jobs_unique_key ON jobs (unique_key) WHERE unique_key IS NOT NULL AND status IN ('pending', 'running');
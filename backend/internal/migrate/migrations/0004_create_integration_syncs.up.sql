@@ -0,0 +1,14 @@
+CREATE TABLE integration_syncs (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    provider varchar(20) NOT NULL,
+    last_synced_at timestamptz,
+    last_sync_error text,
+    last_attempt_at timestamptz,
+    consecutive_failures bigint NOT NULL DEFAULT 0,
+    next_sync_at timestamptz,
+    updated_at timestamptz
+);
+
+CREATE UNIQUE INDEX idx_integration_syncs_user_provider ON integration_syncs (user_id, provider);
+CREATE INDEX idx_integration_syncs_next_sync_at ON integration_syncs (next_sync_at);
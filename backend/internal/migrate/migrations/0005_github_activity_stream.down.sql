@@ -0,0 +1,2 @@
+ALTER TABLE users DROP COLUMN IF EXISTS git_hub_auto_post;
+DROP TABLE IF EXISTS git_hub_activities;
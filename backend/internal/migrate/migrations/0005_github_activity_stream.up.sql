@@ -0,0 +1,21 @@
+CREATE TABLE git_hub_activities (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    delivery_id varchar(64) NOT NULL,
+    event varchar(30) NOT NULL,
+    action varchar(30),
+    repo_full_name varchar(200) NOT NULL,
+    is_private boolean NOT NULL DEFAULT false,
+    title text,
+    url text,
+    ref varchar(200),
+    commit_count bigint NOT NULL DEFAULT 0,
+    occurred_at timestamptz NOT NULL,
+    created_at timestamptz
+);
+
+CREATE UNIQUE INDEX idx_git_hub_activities_delivery_id ON git_hub_activities (delivery_id);
+CREATE INDEX idx_git_hub_activities_user_occurred ON git_hub_activities (user_id, occurred_at);
+
+-- Opt-in: turn notable GitHub events into timeline posts
+ALTER TABLE users ADD COLUMN git_hub_auto_post boolean NOT NULL DEFAULT false;
@@ -0,0 +1,5 @@
+ALTER TABLE git_hub_repositories
+    DROP COLUMN IF EXISTS languages_synced_at,
+    DROP COLUMN IF EXISTS languages_e_tag;
+
+DROP TABLE IF EXISTS git_hub_repo_languages;
@@ -0,0 +1,13 @@
+-- Language bytes per repository, fetched incrementally with conditional requests
+CREATE TABLE git_hub_repo_languages (
+    id bigserial PRIMARY KEY,
+    repository_id bigint NOT NULL REFERENCES git_hub_repositories (id) ON DELETE CASCADE,
+    language text NOT NULL,
+    bytes bigint NOT NULL DEFAULT 0
+);
+
+CREATE UNIQUE INDEX idx_repo_lang ON git_hub_repo_languages (repository_id, language);
+
+ALTER TABLE git_hub_repositories
+    ADD COLUMN languages_e_tag text NOT NULL DEFAULT '',
+    ADD COLUMN languages_synced_at timestamptz;
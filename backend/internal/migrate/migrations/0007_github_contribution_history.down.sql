@@ -0,0 +1,2 @@
+ALTER TABLE users DROP COLUMN IF EXISTS git_hub_created_at;
+DROP TABLE IF EXISTS git_hub_contribution_years;
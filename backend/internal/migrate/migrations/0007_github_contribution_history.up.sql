@@ -0,0 +1,18 @@
+-- Per-year contribution summaries, backfilled to the GitHub account's creation
+CREATE TABLE git_hub_contribution_years (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    year bigint NOT NULL,
+    total bigint NOT NULL DEFAULT 0,
+    commits bigint NOT NULL DEFAULT 0,
+    issues bigint NOT NULL DEFAULT 0,
+    pull_requests bigint NOT NULL DEFAULT 0,
+    reviews bigint NOT NULL DEFAULT 0,
+    restricted bigint NOT NULL DEFAULT 0,
+    includes_private boolean NOT NULL DEFAULT false,
+    updated_at timestamptz
+);
+
+CREATE UNIQUE INDEX idx_user_year ON git_hub_contribution_years (user_id, year);
+
+ALTER TABLE users ADD COLUMN git_hub_created_at timestamptz;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS git_hub_reviews;
+DROP TABLE IF EXISTS git_hub_issues;
+DROP TABLE IF EXISTS git_hub_pull_requests;
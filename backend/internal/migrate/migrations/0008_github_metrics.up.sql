@@ -0,0 +1,54 @@
+-- Pull requests, issues and reviews authored by connected users
+CREATE TABLE git_hub_pull_requests (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    git_hub_id bigint NOT NULL,
+    repo_full_name text NOT NULL,
+    number bigint NOT NULL,
+    title text,
+    url text,
+    state varchar(20) NOT NULL,
+    is_private boolean NOT NULL DEFAULT false,
+    additions bigint NOT NULL DEFAULT 0,
+    deletions bigint NOT NULL DEFAULT 0,
+    opened_at timestamptz NOT NULL,
+    merged_at timestamptz,
+    closed_at timestamptz,
+    git_hub_updated_at timestamptz NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_git_hub_pull_requests_git_hub_id ON git_hub_pull_requests (git_hub_id);
+CREATE INDEX idx_git_hub_pull_requests_user_id ON git_hub_pull_requests (user_id);
+
+CREATE TABLE git_hub_issues (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    git_hub_id bigint NOT NULL,
+    repo_full_name text NOT NULL,
+    number bigint NOT NULL,
+    title text,
+    url text,
+    state varchar(20) NOT NULL,
+    is_private boolean NOT NULL DEFAULT false,
+    opened_at timestamptz NOT NULL,
+    closed_at timestamptz,
+    git_hub_updated_at timestamptz NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_git_hub_issues_git_hub_id ON git_hub_issues (git_hub_id);
+CREATE INDEX idx_git_hub_issues_user_id ON git_hub_issues (user_id);
+
+CREATE TABLE git_hub_reviews (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    git_hub_id bigint NOT NULL,
+    repo_full_name text NOT NULL,
+    pull_request_number bigint NOT NULL,
+    state varchar(20) NOT NULL,
+    url text,
+    is_private boolean NOT NULL DEFAULT false,
+    submitted_at timestamptz NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_git_hub_reviews_git_hub_id ON git_hub_reviews (git_hub_id);
+CREATE INDEX idx_git_hub_reviews_user_id ON git_hub_reviews (user_id);
@@ -0,0 +1,6 @@
+DROP VIEW IF EXISTS language_stats;
+DROP VIEW IF EXISTS contributions;
+DROP TABLE IF EXISTS code_host_contributions;
+DROP TABLE IF EXISTS code_host_language_stats;
+DROP TABLE IF EXISTS code_host_repositories;
+DROP TABLE IF EXISTS code_host_accounts;
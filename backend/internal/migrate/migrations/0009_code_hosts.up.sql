@@ -0,0 +1,77 @@
+-- GitLab and Bitbucket accounts and the data synced from them
+CREATE TABLE code_host_accounts (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    provider varchar(20) NOT NULL,
+    external_id text NOT NULL,
+    username text,
+    avatar_url text,
+    profile_url text,
+    access_token text,
+    refresh_token text,
+    token_expires_at timestamptz,
+    created_at timestamptz,
+    updated_at timestamptz
+);
+
+CREATE UNIQUE INDEX idx_code_host_accounts_user_provider ON code_host_accounts (user_id, provider);
+CREATE UNIQUE INDEX idx_code_host_accounts_provider_external ON code_host_accounts (provider, external_id);
+
+CREATE TABLE code_host_repositories (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    provider varchar(20) NOT NULL,
+    external_id text NOT NULL,
+    name text NOT NULL,
+    full_name text,
+    description text,
+    language text,
+    stars bigint,
+    forks bigint,
+    is_private boolean,
+    url text,
+    updated_at timestamptz
+);
+
+CREATE UNIQUE INDEX idx_code_host_repositories_user_repo ON code_host_repositories (user_id, provider, external_id);
+
+CREATE TABLE code_host_language_stats (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    provider varchar(20) NOT NULL,
+    language text NOT NULL,
+    bytes bigint NOT NULL DEFAULT 0,
+    repo_count bigint NOT NULL DEFAULT 0,
+    updated_at timestamptz
+);
+
+CREATE UNIQUE INDEX idx_code_host_language_stats_user_lang ON code_host_language_stats (user_id, provider, language);
+
+CREATE TABLE code_host_contributions (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    provider varchar(20) NOT NULL,
+    date timestamptz NOT NULL,
+    count bigint NOT NULL DEFAULT 0
+);
+
+CREATE UNIQUE INDEX idx_code_host_contributions_user_provider_date ON code_host_contributions (user_id, provider, date);
+
+-- Contributions and languages across GitHub and every connected code host
+CREATE VIEW contributions AS
+SELECT user_id, date, SUM(count)::bigint AS count
+FROM (
+    SELECT user_id, date, count FROM git_hub_contributions
+    UNION ALL
+    SELECT user_id, date, count FROM code_host_contributions
+) c
+GROUP BY user_id, date;
+
+CREATE VIEW language_stats AS
+SELECT user_id, language, SUM(bytes)::bigint AS bytes, SUM(repo_count)::bigint AS repo_count
+FROM (
+    SELECT user_id, language, bytes, repo_count FROM git_hub_language_stats
+    UNION ALL
+    SELECT user_id, language, bytes, repo_count FROM code_host_language_stats
+) l
+GROUP BY user_id, language;
@@ -0,0 +1,3 @@
+DROP VIEW IF EXISTS user_articles;
+DROP TABLE IF EXISTS external_articles;
+DROP TABLE IF EXISTS article_feeds;
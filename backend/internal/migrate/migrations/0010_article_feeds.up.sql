@@ -0,0 +1,51 @@
+-- RSS, Atom and JSON Feed subscriptions and the articles read from them
+CREATE TABLE article_feeds (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    url text NOT NULL,
+    title text,
+    site_url text,
+    format varchar(10),
+    e_tag text,
+    last_modified text,
+    last_fetched_at timestamptz,
+    last_error text,
+    created_at timestamptz
+);
+
+CREATE UNIQUE INDEX idx_article_feeds_user_url ON article_feeds (user_id, url);
+
+CREATE TABLE external_articles (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    feed_id bigint NOT NULL REFERENCES article_feeds (id) ON DELETE CASCADE,
+    guid text NOT NULL,
+    title text NOT NULL,
+    url text,
+    summary text,
+    tags text,
+    published_at timestamptz,
+    updated_at timestamptz
+);
+
+CREATE UNIQUE INDEX idx_external_articles_feed_guid ON external_articles (feed_id, guid);
+CREATE INDEX idx_external_articles_user_id ON external_articles (user_id);
+
+-- Articles from Zenn, Qiita and feeds in one listing
+CREATE VIEW user_articles AS
+SELECT z.user_id, 'zenn' AS source, 'Zenn' AS source_name, z.title,
+       'https://zenn.dev/' || COALESCE(u.zenn_username, '') || '/articles/' || z.slug AS url,
+       COALESCE(z.liked_count, 0) AS likes, COALESCE(z.comments_count, 0) AS comments,
+       '' AS tags, z.published_at
+FROM zenn_articles z
+JOIN users u ON u.id = z.user_id
+UNION ALL
+SELECT q.user_id, 'qiita', 'Qiita', q.title, q.url,
+       COALESCE(q.likes_count, 0), COALESCE(q.comments_count, 0),
+       COALESCE(q.tags, ''), q.published_at
+FROM qiita_articles q
+UNION ALL
+SELECT a.user_id, 'feed', COALESCE(NULLIF(f.title, ''), f.url), a.title, COALESCE(a.url, ''),
+       0, 0, COALESCE(a.tags, ''), a.published_at
+FROM external_articles a
+JOIN article_feeds f ON f.id = a.feed_id;
@@ -0,0 +1,76 @@
+CREATE TABLE zenn_articles (
+    id bigserial,
+    user_id bigint NOT NULL,
+    zenn_id bigint NOT NULL,
+    title text NOT NULL,
+    slug text NOT NULL,
+    emoji text,
+    article_type text,
+    liked_count bigint DEFAULT 0,
+    comments_count bigint DEFAULT 0,
+    published_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX idx_zenn_articles_zenn_id ON zenn_articles (zenn_id);
+CREATE INDEX idx_zenn_articles_user_id ON zenn_articles (user_id);
+
+CREATE TABLE qiita_articles (
+    id bigserial,
+    user_id bigint NOT NULL,
+    qiita_id text NOT NULL,
+    title text NOT NULL,
+    url text NOT NULL,
+    likes_count bigint DEFAULT 0,
+    comments_count bigint DEFAULT 0,
+    tags text,
+    published_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX idx_qiita_articles_qiita_id ON qiita_articles (qiita_id);
+CREATE INDEX idx_qiita_articles_user_id ON qiita_articles (user_id);
+
+INSERT INTO zenn_articles (user_id, zenn_id, title, slug, emoji, article_type, liked_count, comments_count, published_at, updated_at)
+SELECT user_id, external_id::bigint, title, regexp_replace(url, '^.*/articles/', ''), emoji, category, likes, comments, published_at, updated_at
+FROM external_articles
+WHERE source = 'zenn'
+ON CONFLICT DO NOTHING;
+
+INSERT INTO qiita_articles (user_id, qiita_id, title, url, likes_count, comments_count, tags, published_at, updated_at)
+SELECT user_id, external_id, title, COALESCE(url, ''), likes, comments, tags, published_at, updated_at
+FROM external_articles
+WHERE source = 'qiita'
+ON CONFLICT DO NOTHING;
+
+DELETE FROM external_articles WHERE source <> 'feed';
+
+DROP INDEX idx_external_articles_feed_id;
+DROP INDEX idx_external_articles_user_source_external;
+ALTER TABLE external_articles
+    DROP COLUMN source,
+    DROP COLUMN emoji,
+    DROP COLUMN category,
+    DROP COLUMN likes,
+    DROP COLUMN comments;
+ALTER TABLE external_articles ALTER COLUMN feed_id SET NOT NULL;
+ALTER TABLE external_articles RENAME COLUMN external_id TO guid;
+CREATE UNIQUE INDEX idx_external_articles_feed_guid ON external_articles (feed_id, guid);
+
+CREATE VIEW user_articles AS
+SELECT z.user_id, 'zenn' AS source, 'Zenn' AS source_name, z.title,
+       'https://zenn.dev/' || COALESCE(u.zenn_username, '') || '/articles/' || z.slug AS url,
+       COALESCE(z.liked_count, 0) AS likes, COALESCE(z.comments_count, 0) AS comments,
+       '' AS tags, z.published_at
+FROM zenn_articles z
+JOIN users u ON u.id = z.user_id
+UNION ALL
+SELECT q.user_id, 'qiita', 'Qiita', q.title, q.url,
+       COALESCE(q.likes_count, 0), COALESCE(q.comments_count, 0),
+       COALESCE(q.tags, ''), q.published_at
+FROM qiita_articles q
+UNION ALL
+SELECT a.user_id, 'feed', COALESCE(NULLIF(f.title, ''), f.url), a.title, COALESCE(a.url, ''),
+       0, 0, COALESCE(a.tags, ''), a.published_at
+FROM external_articles a
+JOIN article_feeds f ON f.id = a.feed_id;
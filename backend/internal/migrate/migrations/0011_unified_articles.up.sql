@@ -0,0 +1,40 @@
+-- Zenn and Qiita articles move into external_articles alongside feed entries,
+-- told apart by source
+DROP VIEW user_articles;
+
+ALTER TABLE external_articles RENAME COLUMN guid TO external_id;
+ALTER TABLE external_articles ALTER COLUMN feed_id DROP NOT NULL;
+ALTER TABLE external_articles
+    ADD COLUMN source varchar(20) NOT NULL DEFAULT 'feed',
+    ADD COLUMN emoji text,
+    ADD COLUMN category text,
+    ADD COLUMN likes bigint NOT NULL DEFAULT 0,
+    ADD COLUMN comments bigint NOT NULL DEFAULT 0;
+ALTER TABLE external_articles ALTER COLUMN source DROP DEFAULT;
+
+-- Entries are now unique per user and source rather than per feed
+DROP INDEX idx_external_articles_feed_guid;
+-- Only rows the new index would reject are dropped, keeping the first of each
+DELETE FROM external_articles a
+USING external_articles b
+WHERE a.user_id = b.user_id AND a.source = b.source AND a.external_id = b.external_id AND a.id > b.id;
+CREATE UNIQUE INDEX idx_external_articles_user_source_external ON external_articles (user_id, source, external_id);
+CREATE INDEX idx_external_articles_feed_id ON external_articles (feed_id);
+
+INSERT INTO external_articles (user_id, source, external_id, title, url, emoji, category, likes, comments, published_at, updated_at)
+SELECT z.user_id, 'zenn', z.zenn_id::text, z.title,
+       'https://zenn.dev/' || COALESCE(u.zenn_username, '') || '/articles/' || z.slug,
+       z.emoji, z.article_type, COALESCE(z.liked_count, 0), COALESCE(z.comments_count, 0),
+       z.published_at, z.updated_at
+FROM zenn_articles z
+JOIN users u ON u.id = z.user_id
+ON CONFLICT DO NOTHING;
+
+INSERT INTO external_articles (user_id, source, external_id, title, url, tags, likes, comments, published_at, updated_at)
+SELECT user_id, 'qiita', qiita_id, title, url, tags, COALESCE(likes_count, 0), COALESCE(comments_count, 0),
+       published_at, updated_at
+FROM qiita_articles
+ON CONFLICT DO NOTHING;
+
+DROP TABLE zenn_articles;
+DROP TABLE qiita_articles;
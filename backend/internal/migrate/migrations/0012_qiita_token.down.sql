@@ -0,0 +1 @@
+ALTER TABLE users DROP COLUMN qiita_token;
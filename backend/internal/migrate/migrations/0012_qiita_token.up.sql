@@ -0,0 +1 @@
+ALTER TABLE users ADD COLUMN qiita_token text;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS article_engagement_snapshots;
+ALTER TABLE external_articles DROP COLUMN stocks;
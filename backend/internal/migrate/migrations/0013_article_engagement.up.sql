@@ -0,0 +1,14 @@
+ALTER TABLE external_articles ADD COLUMN stocks bigint NOT NULL DEFAULT 0;
+
+-- One row per article per day, holding its counts as of that day's last sync
+CREATE TABLE IF NOT EXISTS article_engagement_snapshots (
+    article_id bigint NOT NULL REFERENCES external_articles (id) ON DELETE CASCADE,
+    date date NOT NULL,
+    user_id bigint NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    likes bigint NOT NULL DEFAULT 0,
+    comments bigint NOT NULL DEFAULT 0,
+    stocks bigint NOT NULL DEFAULT 0,
+    PRIMARY KEY (article_id, date)
+);
+CREATE INDEX IF NOT EXISTS idx_article_engagement_snapshots_user_date ON article_engagement_snapshots (user_id, date);
+CREATE INDEX IF NOT EXISTS idx_article_engagement_snapshots_date ON article_engagement_snapshots (date);
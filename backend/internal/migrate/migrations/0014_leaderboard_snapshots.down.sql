@@ -0,0 +1,4 @@
+ALTER TABLE notifications DROP COLUMN IF EXISTS rank_change;
+ALTER TABLE notifications DROP COLUMN IF EXISTS rank;
+DROP TABLE IF EXISTS leaderboard_entries;
+DROP TABLE IF EXISTS leaderboard_snapshots;
@@ -0,0 +1,25 @@
+-- One leaderboard per metric and period per day, holding the rankings as of
+-- that day's last snapshot run
+CREATE TABLE IF NOT EXISTS leaderboard_snapshots (
+    id bigserial PRIMARY KEY,
+    metric varchar(50) NOT NULL,
+    period varchar(20) NOT NULL,
+    date date NOT NULL,
+    total integer NOT NULL DEFAULT 0,
+    computed_at timestamptz NOT NULL DEFAULT now()
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_leaderboard_snapshots_metric_period_date ON leaderboard_snapshots (metric, period, date);
+
+CREATE TABLE IF NOT EXISTS leaderboard_entries (
+    snapshot_id bigint NOT NULL REFERENCES leaderboard_snapshots (id) ON DELETE CASCADE,
+    user_id bigint NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    rank integer NOT NULL,
+    score double precision NOT NULL,
+    breakdown jsonb,
+    PRIMARY KEY (snapshot_id, user_id)
+);
+CREATE INDEX IF NOT EXISTS idx_leaderboard_entries_snapshot_rank ON leaderboard_entries (snapshot_id, rank);
+CREATE INDEX IF NOT EXISTS idx_leaderboard_entries_user ON leaderboard_entries (user_id);
+
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS rank integer;
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS rank_change integer;
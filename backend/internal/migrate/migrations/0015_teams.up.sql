@@ -0,0 +1,20 @@
+-- Teams are groups of users, such as study groups, that compete on their own
+-- leaderboards
+CREATE TABLE IF NOT EXISTS teams (
+    id bigserial PRIMARY KEY,
+    name varchar(100) NOT NULL,
+    description varchar(500),
+    owner_id bigint NOT NULL REFERENCES users (id),
+    created_at timestamptz,
+    updated_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_teams_owner_id ON teams (owner_id);
+
+CREATE TABLE IF NOT EXISTS team_members (
+    id bigserial PRIMARY KEY,
+    team_id bigint NOT NULL REFERENCES teams (id) ON DELETE CASCADE,
+    user_id bigint NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    joined_at timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_team_user ON team_members (team_id, user_id);
+CREATE INDEX IF NOT EXISTS idx_team_members_user_id ON team_members (user_id);
@@ -0,0 +1,65 @@
+-- Badges are earned by reaching a threshold on a metric, such as 50
+-- contributions. Name and description are translation keys and icon names a
+-- lucide icon.
+CREATE TABLE IF NOT EXISTS badge_definitions (
+    id varchar(50) PRIMARY KEY,
+    name varchar(100) NOT NULL,
+    description varchar(100) NOT NULL,
+    category varchar(30) NOT NULL,
+    metric varchar(50) NOT NULL,
+    threshold integer NOT NULL,
+    tier varchar(20) NOT NULL,
+    icon varchar(50) NOT NULL,
+    sort_order integer NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS user_badges (
+    user_id bigint NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    badge_id varchar(50) NOT NULL REFERENCES badge_definitions (id) ON DELETE CASCADE,
+    awarded_at timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, badge_id)
+);
+CREATE INDEX IF NOT EXISTS idx_user_badges_badge_id ON user_badges (badge_id);
+
+INSERT INTO badge_definitions (id, name, description, category, metric, threshold, tier, icon, sort_order) VALUES
+    ('first-commit', 'badges.firstCommit', 'badges.firstCommitDesc', 'contribution', 'contributions', 1, 'bronze', 'sprout', 10),
+    ('contributor', 'badges.contributor', 'badges.contributorDesc', 'contribution', 'contributions', 50, 'silver', 'monitor', 20),
+    ('code-warrior', 'badges.codeWarrior', 'badges.codeWarriorDesc', 'contribution', 'contributions', 200, 'gold', 'swords', 30),
+    ('commit-master', 'badges.commitMaster', 'badges.commitMasterDesc', 'contribution', 'contributions', 500, 'gold', 'crown', 40),
+    ('legend', 'badges.legend', 'badges.legendDesc', 'contribution', 'contributions', 1000, 'platinum', 'trophy', 50),
+    ('week-streak', 'badges.weekStreak', 'badges.weekStreakDesc', 'streak', 'streak', 7, 'silver', 'flame', 60),
+    ('month-streak', 'badges.monthStreak', 'badges.monthStreakDesc', 'streak', 'streak', 30, 'gold', 'zap', 70),
+    ('first-post', 'badges.firstPost', 'badges.firstPostDesc', 'post', 'posts', 1, 'bronze', 'pen-line', 80),
+    ('blogger', 'badges.blogger', 'badges.bloggerDesc', 'post', 'posts', 10, 'silver', 'file-text', 90),
+    ('liked', 'badges.liked', 'badges.likedDesc', 'engagement', 'likes_received', 10, 'bronze', 'heart', 100),
+    ('popular', 'badges.popular', 'badges.popularDesc', 'engagement', 'likes_received', 50, 'silver', 'star', 110),
+    ('friendly', 'badges.friendly', 'badges.friendlyDesc', 'social', 'following', 5, 'bronze', 'handshake', 120),
+    ('influencer', 'badges.influencer', 'badges.influencerDesc', 'social', 'followers', 10, 'silver', 'megaphone', 130),
+    ('star', 'badges.star', 'badges.starDesc', 'social', 'followers', 50, 'gold', 'sparkles', 140),
+    ('qa-first-answer', 'badges.qaFirstAnswer', 'badges.qaFirstAnswerDesc', 'qa', 'answers', 1, 'bronze', 'message-circle-question', 150),
+    ('qa-helper', 'badges.qaHelper', 'badges.qaHelperDesc', 'qa', 'answers', 10, 'silver', 'help-circle', 160),
+    ('goal-achiever', 'badges.goalAchiever', 'badges.goalAchieverDesc', 'goal', 'goals_completed', 5, 'silver', 'award', 170),
+    ('goal-master', 'badges.goalMaster', 'badges.goalMasterDesc', 'goal', 'goals_completed', 20, 'gold', 'graduation-cap', 180),
+    ('first-article', 'badges.firstArticle', 'badges.firstArticleDesc', 'article', 'articles', 1, 'bronze', 'newspaper', 190),
+    ('tech-writer', 'badges.techWriter', 'badges.techWriterDesc', 'article', 'articles', 10, 'silver', 'book-open', 200),
+    ('well-read', 'badges.wellRead', 'badges.wellReadDesc', 'article', 'article_likes', 100, 'gold', 'glasses', 210)
+ON CONFLICT (id) DO NOTHING;
+
+-- Award the badges users have already earned, without notifying them. Streak
+-- badges depend on the day and are awarded by the first evaluation instead.
+INSERT INTO user_badges (user_id, badge_id, awarded_at)
+SELECT m.user_id, d.id, now()
+FROM badge_definitions d
+JOIN (
+    SELECT user_id, 'contributions' AS metric, SUM(count) AS value FROM contributions GROUP BY user_id
+    UNION ALL SELECT user_id, 'posts', COUNT(*) FROM posts GROUP BY user_id
+    UNION ALL SELECT user_id, 'likes_received', SUM(like_count) FROM posts GROUP BY user_id
+    UNION ALL SELECT followee_id, 'followers', COUNT(*) FROM follows GROUP BY followee_id
+    UNION ALL SELECT follower_id, 'following', COUNT(*) FROM follows GROUP BY follower_id
+    UNION ALL SELECT user_id, 'answers', COUNT(*) FROM answers WHERE deleted_at IS NULL GROUP BY user_id
+    UNION ALL SELECT user_id, 'goals_completed', COUNT(*) FROM learning_goals WHERE status = 'completed' GROUP BY user_id
+    UNION ALL SELECT user_id, 'articles', COUNT(*) FROM external_articles GROUP BY user_id
+    UNION ALL SELECT user_id, 'article_likes', SUM(likes) FROM external_articles GROUP BY user_id
+) m ON m.metric = d.metric AND m.value >= d.threshold
+JOIN users u ON u.id = m.user_id
+ON CONFLICT DO NOTHING;
@@ -0,0 +1 @@
+ALTER TABLE code_host_accounts DROP COLUMN tokens_encrypted;
@@ -0,0 +1,3 @@
+-- GitLab and Bitbucket tokens are now stored encrypted. Accounts connected
+-- before keep plaintext tokens until their next sync encrypts them.
+ALTER TABLE code_host_accounts ADD COLUMN tokens_encrypted boolean NOT NULL DEFAULT false;
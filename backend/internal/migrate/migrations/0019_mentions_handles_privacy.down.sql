@@ -0,0 +1,12 @@
+DROP TABLE IF EXISTS data_exports;
+DROP TABLE IF EXISTS privacy_settings;
+DROP TABLE IF EXISTS handle_histories;
+DROP TABLE IF EXISTS mentions;
+
+DROP INDEX IF EXISTS idx_notifications_chat_room_id;
+ALTER TABLE notifications DROP COLUMN IF EXISTS chat_room_id;
+
+DROP INDEX IF EXISTS idx_users_handle;
+DROP INDEX IF EXISTS idx_users_deletion_scheduled_at;
+ALTER TABLE users DROP COLUMN IF EXISTS deletion_scheduled_at;
+ALTER TABLE users DROP COLUMN IF EXISTS handle;
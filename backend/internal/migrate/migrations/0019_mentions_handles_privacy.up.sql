@@ -0,0 +1,77 @@
+-- Columns and tables added by AutoMigrate after the baseline but before
+-- versioned migrations existed. Databases created from the baseline schema
+-- lack all of them; databases AutoMigrate kept up to date already have them.
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS handle varchar(39);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deletion_scheduled_at timestamptz;
+CREATE INDEX IF NOT EXISTS idx_users_deletion_scheduled_at ON users (deletion_scheduled_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_handle ON users (handle) WHERE handle <> '';
+
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS chat_room_id bigint;
+CREATE INDEX IF NOT EXISTS idx_notifications_chat_room_id ON notifications (chat_room_id);
+
+CREATE TABLE IF NOT EXISTS mentions (
+    id bigserial,
+    user_id bigint NOT NULL,
+    actor_id bigint NOT NULL,
+    source_type varchar(20) NOT NULL,
+    source_id bigint NOT NULL,
+    post_id bigint,
+    question_id bigint,
+    chat_room_id bigint,
+    excerpt varchar(300),
+    created_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_mentions_actor FOREIGN KEY (actor_id) REFERENCES users(id),
+    CONSTRAINT fk_mentions_post FOREIGN KEY (post_id) REFERENCES posts(id),
+    CONSTRAINT fk_mentions_question FOREIGN KEY (question_id) REFERENCES questions(id)
+);
+CREATE INDEX IF NOT EXISTS idx_mentions_chat_room_id ON mentions (chat_room_id);
+CREATE INDEX IF NOT EXISTS idx_mentions_question_id ON mentions (question_id);
+CREATE INDEX IF NOT EXISTS idx_mentions_post_id ON mentions (post_id);
+CREATE INDEX IF NOT EXISTS idx_mentions_actor_id ON mentions (actor_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_mention_source_user ON mentions (user_id, source_type, source_id);
+CREATE INDEX IF NOT EXISTS idx_mentions_user_id ON mentions (user_id);
+
+CREATE TABLE IF NOT EXISTS handle_histories (
+    id bigserial,
+    user_id bigint NOT NULL,
+    handle varchar(39) NOT NULL,
+    changed_at timestamptz NOT NULL,
+    expires_at timestamptz NOT NULL,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_handle_histories_handle ON handle_histories (handle);
+CREATE INDEX IF NOT EXISTS idx_handle_histories_user_id ON handle_histories (user_id);
+
+CREATE TABLE IF NOT EXISTS privacy_settings (
+    id bigserial,
+    user_id bigint NOT NULL,
+    profile_public boolean NOT NULL,
+    show_badges boolean NOT NULL,
+    show_contributions boolean NOT NULL,
+    show_languages boolean NOT NULL,
+    show_projects boolean NOT NULL,
+    show_articles boolean NOT NULL,
+    show_roadmaps boolean NOT NULL,
+    updated_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_privacy_settings_user_id ON privacy_settings (user_id);
+
+CREATE TABLE IF NOT EXISTS data_exports (
+    id bigserial,
+    user_id bigint NOT NULL,
+    status varchar(20) NOT NULL,
+    token text NOT NULL,
+    file_path text,
+    size_bytes bigint,
+    error text,
+    expires_at timestamptz,
+    created_at timestamptz,
+    completed_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_data_exports_status ON data_exports (status);
+CREATE INDEX IF NOT EXISTS idx_data_exports_user_id ON data_exports (user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_data_exports_token ON data_exports (token);
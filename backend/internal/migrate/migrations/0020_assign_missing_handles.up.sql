@@ -0,0 +1,47 @@
+-- Users created before handles existed get one generated the way
+-- HandleService.Available does: from their GitHub login, name or email,
+-- with a numeric suffix when taken. This used to run on every boot from main.go.
+DO $$
+DECLARE
+    reserved text[] := ARRAY[
+        'about', 'admin', 'administrator', 'api', 'auth', 'badges', 'by-handle',
+        'chat-rooms', 'devsync', 'edit', 'explore', 'github', 'goals', 'health',
+        'help', 'login', 'logout', 'me', 'mentions', 'messages', 'moderator', 'new',
+        'notifications', 'null', 'posts', 'privacy', 'projects', 'public', 'qiita',
+        'questions', 'rankings', 'register', 'reports', 'resources', 'roadmaps',
+        'root', 'search', 'settings', 'signup', 'staff', 'support', 'system', 'terms',
+        'undefined', 'upload', 'uploads', 'user', 'users', 'webhooks', 'ws', 'www',
+        'zenn'
+    ];
+    u record;
+    base text;
+    candidate text;
+    suffix text;
+    n integer;
+BEGIN
+    FOR u IN SELECT id, git_hub_username, name, email FROM users WHERE handle IS NULL OR handle = '' ORDER BY id LOOP
+        base := 'user';
+        FOREACH candidate IN ARRAY ARRAY[u.git_hub_username, u.name, split_part(u.email, '@', 1)] LOOP
+            candidate := trim(BOTH '-' FROM regexp_replace(lower(COALESCE(candidate, '')), '[^a-z0-9]+', '-', 'g'));
+            candidate := rtrim(left(candidate, 39), '-');
+            IF length(candidate) >= 3 THEN
+                base := candidate;
+                EXIT;
+            END IF;
+        END LOOP;
+
+        candidate := base;
+        n := 2;
+        WHILE candidate = ANY (reserved)
+            OR EXISTS (SELECT 1 FROM users WHERE handle = candidate AND id <> u.id)
+            OR EXISTS (SELECT 1 FROM handle_histories WHERE handle = candidate AND user_id <> u.id AND expires_at > now())
+        LOOP
+            suffix := '-' || n;
+            candidate := rtrim(left(base, 39 - length(suffix)), '-') || suffix;
+            n := n + 1;
+        END LOOP;
+
+        UPDATE users SET handle = candidate WHERE id = u.id;
+    END LOOP;
+END
+$$;
@@ -0,0 +1 @@
+ALTER TABLE external_articles DROP COLUMN IF EXISTS private;
@@ -0,0 +1,8 @@
+-- Qiita limited-share items, only ever shown to their author.
+ALTER TABLE external_articles ADD COLUMN IF NOT EXISTS private boolean NOT NULL DEFAULT false;
+
+-- Qiita articles read with a token may include limited-share items that were
+-- stored as public. Hide them until the next sync, which reads every article
+-- again because of the old updated_at and marks the public ones as such.
+UPDATE external_articles SET private = true, updated_at = 'epoch'
+WHERE source = 'qiita' AND user_id IN (SELECT id FROM users WHERE qiita_token <> '');
@@ -12,18 +12,22 @@ const (
 
 // ActivityReport represents a user's activity report for a period
 type ActivityReport struct {
-	Period            ReportPeriod `json:"period"`
-	StartDate         time.Time    `json:"start_date"`
-	EndDate           time.Time    `json:"end_date"`
-	UserID            uint         `json:"user_id"`
-	TotalContributions int         `json:"total_contributions"`
-	PostsCreated      int          `json:"posts_created"`
-	CommentsCreated   int          `json:"comments_created"`
-	LikesReceived     int          `json:"likes_received"`
-	GoalsCompleted    int          `json:"goals_completed"`
-	GoalsProgress     int          `json:"goals_progress"` // Average progress of active goals
-	NewFollowers      int          `json:"new_followers"`
-	MessagesExchanged int          `json:"messages_exchanged"`
+	Period             ReportPeriod `json:"period"`
+	StartDate          time.Time    `json:"start_date"`
+	EndDate            time.Time    `json:"end_date"`
+	UserID             uint         `json:"user_id"`
+	TotalContributions int          `json:"total_contributions"`
+	PostsCreated       int          `json:"posts_created"`
+	CommentsCreated    int          `json:"comments_created"`
+	LikesReceived      int          `json:"likes_received"`
+	GoalsCompleted     int          `json:"goals_completed"`
+	GoalsProgress      int          `json:"goals_progress"` // Average progress of active goals
+	NewFollowers       int          `json:"new_followers"`
+	MessagesExchanged  int          `json:"messages_exchanged"`
+	// Articles published on Zenn, Qiita and feeds
+	ArticlesPublished int `json:"articles_published"`
+	// Pull requests, reviews and issues on GitHub
+	GitHubMetrics
 	// Daily breakdown for charts
 	DailyContributions []DailyActivity `json:"daily_contributions"`
 	// Top languages used
@@ -47,9 +51,12 @@ type LanguageActivity struct {
 
 // ReportComparison shows comparison with previous period
 type ReportComparison struct {
-	ContributionsDiff int     `json:"contributions_diff"`
-	PostsDiff         int     `json:"posts_diff"`
-	FollowersDiff     int     `json:"followers_diff"`
-	GoalsDiff         int     `json:"goals_diff"`
-	TrendPercentage   float64 `json:"trend_percentage"` // Overall activity trend
+	ContributionsDiff      int     `json:"contributions_diff"`
+	PostsDiff              int     `json:"posts_diff"`
+	FollowersDiff          int     `json:"followers_diff"`
+	GoalsDiff              int     `json:"goals_diff"`
+	PullRequestsMergedDiff int     `json:"pull_requests_merged_diff"`
+	ReviewsDiff            int     `json:"reviews_diff"`
+	ArticlesDiff           int     `json:"articles_diff"`
+	TrendPercentage        float64 `json:"trend_percentage"` // Overall activity trend
 }
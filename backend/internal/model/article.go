@@ -0,0 +1,101 @@
+package model
+
+import "time"
+
+// Feed formats
+const (
+	FeedFormatRSS  = "rss"
+	FeedFormatAtom = "atom"
+	FeedFormatJSON = "json"
+)
+
+// ArticleFeed is an RSS, Atom or JSON Feed a user publishes articles
+// through, such as a dev.to, Hashnode or Medium blog or a personal site.
+type ArticleFeed struct {
+	ID      uint   `json:"id" gorm:"primaryKey"`
+	UserID  uint   `json:"user_id" gorm:"not null;uniqueIndex:idx_article_feeds_user_url"`
+	URL     string `json:"url" gorm:"not null;uniqueIndex:idx_article_feeds_user_url"`
+	Title   string `json:"title"`
+	SiteURL string `json:"site_url"`
+	Format  string `json:"format"`
+	// Validators from the last fetch, for conditional requests
+	ETag          string     `json:"-"`
+	LastModified  string     `json:"-"`
+	LastFetchedAt *time.Time `json:"last_fetched_at"`
+	LastError     string     `json:"last_error,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// ExternalArticle is an article a user published elsewhere - on Zenn, on
+// Qiita or through one of their feeds.
+type ExternalArticle struct {
+	ID     uint                `json:"id" gorm:"primaryKey"`
+	UserID uint                `json:"user_id" gorm:"not null;uniqueIndex:idx_external_articles_user_source_external"`
+	Source IntegrationProvider `json:"source" gorm:"not null;uniqueIndex:idx_external_articles_user_source_external"`
+	// ExternalID identifies the article within its source: the Zenn or Qiita
+	// article ID or the feed entry's GUID
+	ExternalID string `json:"external_id" gorm:"not null;uniqueIndex:idx_external_articles_user_source_external"`
+	// FeedID is set for articles read from an ArticleFeed
+	FeedID      *uint     `json:"feed_id,omitempty" gorm:"index"`
+	Title       string    `json:"title" gorm:"not null"`
+	URL         string    `json:"url"`
+	Summary     string    `json:"summary,omitempty"`
+	Emoji       string    `json:"emoji,omitempty"`
+	Category    string    `json:"category,omitempty"` // Zenn: tech or idea
+	Likes       int       `json:"likes" gorm:"default:0"`
+	Comments    int       `json:"comments" gorm:"default:0"`
+	Stocks      int       `json:"stocks" gorm:"default:0"` // Qiita stocks, Zenn bookmarks
+	Tags        string    `json:"tags"`                    // comma-separated tag names
+	PublishedAt time.Time `json:"published_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	// Private marks a Qiita limited-share item. Its URL is a secret, so it
+	// is only ever shown to its author.
+	Private bool `json:"private,omitempty" gorm:"not null;default:false"`
+}
+
+// ArticleStats represents aggregated article statistics for a user, overall
+// and per source
+type ArticleStats struct {
+	TotalArticles int                  `json:"total_articles"`
+	TotalLikes    int                  `json:"total_likes"`
+	TotalComments int                  `json:"total_comments"`
+	Sources       []ArticleSourceStats `json:"sources"`
+}
+
+type ArticleSourceStats struct {
+	Source   IntegrationProvider `json:"source"`
+	Articles int                 `json:"articles"`
+	Likes    int                 `json:"likes"`
+	Comments int                 `json:"comments"`
+}
+
+// ArticleEngagementSnapshot holds an article's counts on one day, so their
+// growth can be followed over time.
+type ArticleEngagementSnapshot struct {
+	ArticleID uint      `json:"article_id" gorm:"primaryKey"`
+	Date      time.Time `json:"date" gorm:"primaryKey;type:date"`
+	UserID    uint      `json:"user_id" gorm:"not null;index"`
+	Likes     int       `json:"likes"`
+	Comments  int       `json:"comments"`
+	Stocks    int       `json:"stocks"`
+}
+
+// ArticleEngagement is one day of an engagement time series, for a single
+// article or summed over a user's articles
+type ArticleEngagement struct {
+	Date     time.Time `json:"date"`
+	Likes    int       `json:"likes"`
+	Comments int       `json:"comments"`
+	Stocks   int       `json:"stocks"`
+}
+
+// TrendingArticle is an article that gained the most likes over a recent
+// period, with its author
+type TrendingArticle struct {
+	ExternalArticle
+	AuthorName      string `json:"author_name"`
+	AuthorAvatarURL string `json:"author_avatar_url"`
+	LikesGained     int    `json:"likes_gained"`
+	CommentsGained  int    `json:"comments_gained"`
+	StocksGained    int    `json:"stocks_gained"`
+}
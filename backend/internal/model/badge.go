@@ -0,0 +1,36 @@
+package model
+
+import "time"
+
+// BadgeTier ranks how hard a badge is to earn
+type BadgeTier string
+
+const (
+	BadgeTierBronze   BadgeTier = "bronze"
+	BadgeTierSilver   BadgeTier = "silver"
+	BadgeTierGold     BadgeTier = "gold"
+	BadgeTierPlatinum BadgeTier = "platinum"
+)
+
+// BadgeDefinition is a badge earned by reaching Threshold on Metric. Name and
+// Description are translation keys, and Icon names the icon shown for it.
+type BadgeDefinition struct {
+	ID          string    `json:"id" gorm:"primaryKey;size:50"`
+	Name        string    `json:"name" gorm:"size:100;not null"`
+	Description string    `json:"description" gorm:"size:100;not null"`
+	Category    string    `json:"category" gorm:"size:30;not null"`
+	Metric      string    `json:"metric" gorm:"size:50;not null"`
+	Threshold   int       `json:"threshold" gorm:"not null"`
+	Tier        BadgeTier `json:"tier" gorm:"size:20;not null"`
+	Icon        string    `json:"icon" gorm:"size:50;not null"`
+	SortOrder   int       `json:"sort_order" gorm:"not null;default:0"`
+}
+
+// UserBadge records a badge awarded to a user. Awards are kept even if the
+// user later falls below the threshold.
+type UserBadge struct {
+	UserID    uint             `json:"user_id" gorm:"primaryKey"`
+	BadgeID   string           `json:"badge_id" gorm:"primaryKey;size:50"`
+	Badge     *BadgeDefinition `json:"badge,omitempty" gorm:"foreignKey:BadgeID"`
+	AwardedAt time.Time        `json:"awarded_at"`
+}
@@ -0,0 +1,71 @@
+package model
+
+import "time"
+
+// CodeHostAccount is a user's connected GitLab or Bitbucket account. GitHub
+// accounts live on User, as they can also be used to sign in.
+type CodeHostAccount struct {
+	ID         uint                `json:"-" gorm:"primaryKey"`
+	UserID     uint                `json:"user_id" gorm:"not null;uniqueIndex:idx_code_host_accounts_user_provider"`
+	Provider   IntegrationProvider `json:"provider" gorm:"size:20;not null;uniqueIndex:idx_code_host_accounts_user_provider;uniqueIndex:idx_code_host_accounts_provider_external"`
+	ExternalID string              `json:"-" gorm:"not null;uniqueIndex:idx_code_host_accounts_provider_external"`
+	Username   string              `json:"username"`
+	AvatarURL  string              `json:"avatar_url"`
+	ProfileURL string              `json:"profile_url"`
+	// OAuth tokens, encrypted; access tokens expire and are refreshed before
+	// syncing
+	AccessToken    string     `json:"-"`
+	RefreshToken   string     `json:"-"`
+	TokenExpiresAt *time.Time `json:"-"`
+	// TokensEncrypted is false for accounts connected before tokens were
+	// encrypted, until their next sync
+	TokensEncrypted bool      `json:"-" gorm:"not null;default:false"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// CodeHostRepository is a repository on a connected code host.
+type CodeHostRepository struct {
+	ID          uint                `json:"id" gorm:"primaryKey"`
+	UserID      uint                `json:"user_id" gorm:"not null;uniqueIndex:idx_code_host_repositories_user_repo"`
+	Provider    IntegrationProvider `json:"provider" gorm:"size:20;not null;uniqueIndex:idx_code_host_repositories_user_repo"`
+	ExternalID  string              `json:"-" gorm:"not null;uniqueIndex:idx_code_host_repositories_user_repo"`
+	Name        string              `json:"name" gorm:"not null"`
+	FullName    string              `json:"full_name"`
+	Description string              `json:"description"`
+	Language    string              `json:"language"`
+	Stars       int                 `json:"stars"`
+	Forks       int                 `json:"forks"`
+	IsPrivate   bool                `json:"is_private"`
+	URL         string              `json:"url"`
+	UpdatedAt   time.Time           `json:"updated_at"`
+}
+
+// CodeHostLanguageStat is the size of one language across a user's
+// repositories on a code host.
+type CodeHostLanguageStat struct {
+	ID        uint                `json:"-" gorm:"primaryKey"`
+	UserID    uint                `json:"user_id" gorm:"not null;uniqueIndex:idx_code_host_language_stats_user_lang"`
+	Provider  IntegrationProvider `json:"provider" gorm:"size:20;not null;uniqueIndex:idx_code_host_language_stats_user_lang"`
+	Language  string              `json:"language" gorm:"not null;uniqueIndex:idx_code_host_language_stats_user_lang"`
+	Bytes     int64               `json:"bytes" gorm:"not null;default:0"`
+	RepoCount int                 `json:"repo_count" gorm:"not null;default:0"`
+	UpdatedAt time.Time           `json:"updated_at"`
+}
+
+// CodeHostContribution is a user's contribution count for one day on a code host.
+type CodeHostContribution struct {
+	ID       uint                `json:"-" gorm:"primaryKey"`
+	UserID   uint                `json:"user_id" gorm:"not null;uniqueIndex:idx_code_host_contributions_user_provider_date"`
+	Provider IntegrationProvider `json:"provider" gorm:"size:20;not null;uniqueIndex:idx_code_host_contributions_user_provider_date"`
+	Date     time.Time           `json:"date" gorm:"not null;uniqueIndex:idx_code_host_contributions_user_provider_date"`
+	Count    int                 `json:"count" gorm:"not null;default:0"`
+}
+
+// Contribution is a user's daily contribution count summed over GitHub and
+// every connected code host, read from the contributions view.
+type Contribution struct {
+	UserID uint      `json:"user_id"`
+	Date   time.Time `json:"date"`
+	Count  int       `json:"count"`
+}
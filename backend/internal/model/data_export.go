@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+// DataExportStatus represents the state of a personal data export job
+type DataExportStatus string
+
+const (
+	DataExportStatusPending    DataExportStatus = "pending"
+	DataExportStatusProcessing DataExportStatus = "processing"
+	DataExportStatusCompleted  DataExportStatus = "completed"
+	DataExportStatusFailed     DataExportStatus = "failed"
+	DataExportStatusExpired    DataExportStatus = "expired"
+)
+
+// DataExport is a user's request for a ZIP of all their personal data.
+type DataExport struct {
+	ID          uint             `json:"id" gorm:"primaryKey"`
+	UserID      uint             `json:"user_id" gorm:"not null;index"`
+	Status      DataExportStatus `json:"status" gorm:"size:20;not null;index"`
+	Token       string           `json:"-" gorm:"uniqueIndex;not null"`
+	FilePath    string           `json:"-"`
+	SizeBytes   int64            `json:"size_bytes"`
+	Error       string           `json:"error,omitempty" gorm:"type:text"`
+	ExpiresAt   *time.Time       `json:"expires_at"`
+	CreatedAt   time.Time        `json:"created_at"`
+	CompletedAt *time.Time       `json:"completed_at"`
+}
+
+func (e *DataExport) IsExpired() bool {
+	return e.ExpiresAt != nil && time.Now().After(*e.ExpiresAt)
+}
+
+// IsDownloadable reports whether the export file can still be fetched.
+func (e *DataExport) IsDownloadable() bool {
+	return e.Status == DataExportStatusCompleted && !e.IsExpired()
+}
@@ -11,6 +11,24 @@ type GitHubContribution struct {
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// GitHubContributionYear summarizes a user's contributions in one calendar year.
+type GitHubContributionYear struct {
+	ID           uint `json:"-" gorm:"primaryKey"`
+	UserID       uint `json:"user_id" gorm:"not null;uniqueIndex:idx_user_year"`
+	Year         int  `json:"year" gorm:"not null;uniqueIndex:idx_user_year"`
+	Total        int  `json:"total" gorm:"not null;default:0"`
+	Commits      int  `json:"commits" gorm:"not null;default:0"`
+	Issues       int  `json:"issues" gorm:"not null;default:0"`
+	PullRequests int  `json:"pull_requests" gorm:"not null;default:0"`
+	Reviews      int  `json:"reviews" gorm:"not null;default:0"`
+	// Restricted counts private contributions GitHub only reports as a number
+	Restricted int `json:"restricted" gorm:"not null;default:0"`
+	// IncludesPrivate is set when the token could read private repositories,
+	// so their contributions are part of the totals and the calendar
+	IncludesPrivate bool      `json:"includes_private" gorm:"not null"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
 type GitHubLanguageStat struct {
 	ID        uint      `json:"id" gorm:"primaryKey"`
 	UserID    uint      `json:"user_id" gorm:"not null;uniqueIndex:idx_user_lang"`
@@ -21,15 +39,27 @@ type GitHubLanguageStat struct {
 }
 
 type GitHubRepository struct {
-	ID           uint      `json:"id" gorm:"primaryKey"`
-	UserID       uint      `json:"user_id" gorm:"not null;index"`
-	GitHubRepoID int64     `json:"github_repo_id" gorm:"not null;uniqueIndex"`
-	Name         string    `json:"name" gorm:"not null"`
-	FullName     string    `json:"full_name"`
-	Description  string    `json:"description"`
-	Language     string    `json:"language"`
-	Stars        int       `json:"stars"`
-	Forks        int       `json:"forks"`
-	IsPrivate    bool      `json:"is_private"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID           uint   `json:"id" gorm:"primaryKey"`
+	UserID       uint   `json:"user_id" gorm:"not null;index"`
+	GitHubRepoID int64  `json:"github_repo_id" gorm:"not null;uniqueIndex"`
+	Name         string `json:"name" gorm:"not null"`
+	FullName     string `json:"full_name"`
+	Description  string `json:"description"`
+	Language     string `json:"language"`
+	Stars        int    `json:"stars"`
+	Forks        int    `json:"forks"`
+	IsPrivate    bool   `json:"is_private"`
+	// LanguagesETag is the ETag of the last /languages response, sent back
+	// with If-None-Match so unchanged repositories aren't fetched again
+	LanguagesETag     string     `json:"-"`
+	LanguagesSyncedAt *time.Time `json:"-"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+// GitHubRepoLanguage is the number of bytes of one language in a repository.
+type GitHubRepoLanguage struct {
+	ID           uint   `json:"id" gorm:"primaryKey"`
+	RepositoryID uint   `json:"repository_id" gorm:"not null;uniqueIndex:idx_repo_lang"`
+	Language     string `json:"language" gorm:"not null;uniqueIndex:idx_repo_lang"`
+	Bytes        int64  `json:"bytes" gorm:"not null;default:0"`
 }
@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// GitHubActivity is one event from a connected user's repository, received
+// via the GitHub webhook.
+type GitHubActivity struct {
+	ID     uint `json:"id" gorm:"primaryKey"`
+	UserID uint `json:"user_id" gorm:"not null;index:idx_git_hub_activities_user_occurred,priority:1"`
+	// DeliveryID is GitHub's X-GitHub-Delivery, so redelivered events are ignored
+	DeliveryID   string    `json:"-" gorm:"size:64;not null;uniqueIndex"`
+	Event        string    `json:"event" gorm:"size:30;not null"`
+	Action       string    `json:"action,omitempty" gorm:"size:30"`
+	RepoFullName string    `json:"repo_full_name" gorm:"size:200;not null"`
+	IsPrivate    bool      `json:"is_private" gorm:"not null"`
+	Title        string    `json:"title"`
+	URL          string    `json:"url"`
+	Ref          string    `json:"ref,omitempty" gorm:"size:200"`
+	CommitCount  int       `json:"commit_count,omitempty" gorm:"not null"`
+	OccurredAt   time.Time `json:"occurred_at" gorm:"not null;index:idx_git_hub_activities_user_occurred,priority:2"`
+	CreatedAt    time.Time `json:"created_at"`
+}
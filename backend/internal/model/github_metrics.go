@@ -0,0 +1,74 @@
+package model
+
+import "time"
+
+// GitHubPullRequest is a pull request authored by a user.
+type GitHubPullRequest struct {
+	ID           uint       `json:"id" gorm:"primaryKey"`
+	UserID       uint       `json:"user_id" gorm:"not null;index"`
+	GitHubID     int64      `json:"github_id" gorm:"not null;uniqueIndex"`
+	RepoFullName string     `json:"repo_full_name" gorm:"not null"`
+	Number       int        `json:"number" gorm:"not null"`
+	Title        string     `json:"title"`
+	URL          string     `json:"url"`
+	State        string     `json:"state" gorm:"size:20;not null"` // open, closed or merged
+	IsPrivate    bool       `json:"is_private" gorm:"not null"`
+	Additions    int        `json:"additions" gorm:"not null;default:0"`
+	Deletions    int        `json:"deletions" gorm:"not null;default:0"`
+	OpenedAt     time.Time  `json:"opened_at" gorm:"not null"`
+	MergedAt     *time.Time `json:"merged_at"`
+	ClosedAt     *time.Time `json:"closed_at"`
+	// GitHubUpdatedAt is when the pull request last changed on GitHub; syncs
+	// stop paging once they reach pull requests that haven't changed
+	GitHubUpdatedAt time.Time `json:"-" gorm:"not null"`
+}
+
+// GitHubIssue is an issue opened by a user.
+type GitHubIssue struct {
+	ID              uint       `json:"id" gorm:"primaryKey"`
+	UserID          uint       `json:"user_id" gorm:"not null;index"`
+	GitHubID        int64      `json:"github_id" gorm:"not null;uniqueIndex"`
+	RepoFullName    string     `json:"repo_full_name" gorm:"not null"`
+	Number          int        `json:"number" gorm:"not null"`
+	Title           string     `json:"title"`
+	URL             string     `json:"url"`
+	State           string     `json:"state" gorm:"size:20;not null"` // open or closed
+	IsPrivate       bool       `json:"is_private" gorm:"not null"`
+	OpenedAt        time.Time  `json:"opened_at" gorm:"not null"`
+	ClosedAt        *time.Time `json:"closed_at"`
+	GitHubUpdatedAt time.Time  `json:"-" gorm:"not null"`
+}
+
+// GitHubReview is a pull request review submitted by a user.
+type GitHubReview struct {
+	ID                uint      `json:"id" gorm:"primaryKey"`
+	UserID            uint      `json:"user_id" gorm:"not null;index"`
+	GitHubID          int64     `json:"github_id" gorm:"not null;uniqueIndex"`
+	RepoFullName      string    `json:"repo_full_name" gorm:"not null"`
+	PullRequestNumber int       `json:"pull_request_number" gorm:"not null"`
+	State             string    `json:"state" gorm:"size:20;not null"` // approved, changes_requested, commented or dismissed
+	URL               string    `json:"url"`
+	IsPrivate         bool      `json:"is_private" gorm:"not null"`
+	SubmittedAt       time.Time `json:"submitted_at" gorm:"not null"`
+}
+
+// GitHubMetrics counts a user's pull requests, reviews and issues in a period.
+type GitHubMetrics struct {
+	PullRequestsOpened int `json:"pull_requests_opened"`
+	PullRequestsMerged int `json:"pull_requests_merged"`
+	// PullRequestsClosed counts pull requests closed without being merged
+	PullRequestsClosed int `json:"pull_requests_closed"`
+	// Additions and Deletions are the lines changed by merged pull requests
+	Additions    int `json:"additions"`
+	Deletions    int `json:"deletions"`
+	ReviewsGiven int `json:"reviews_given"`
+	IssuesOpened int `json:"issues_opened"`
+	IssuesClosed int `json:"issues_closed"`
+}
+
+// GitHubMetricsPeriod is one period of a metrics breakdown.
+type GitHubMetricsPeriod struct {
+	StartDate time.Time `json:"start_date"`
+	EndDate   time.Time `json:"end_date"`
+	GitHubMetrics
+}
@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// HandleHistory records a handle a user gave up. Until ExpiresAt the old
+// handle redirects to the user and cannot be claimed by anyone else.
+type HandleHistory struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	UserID    uint      `json:"user_id" gorm:"not null;index"`
+	Handle    string    `json:"handle" gorm:"size:39;not null;index"`
+	ChangedAt time.Time `json:"changed_at" gorm:"not null"`
+	ExpiresAt time.Time `json:"expires_at" gorm:"not null"`
+}
+
+func (h *HandleHistory) IsExpired() bool {
+	return time.Now().After(h.ExpiresAt)
+}
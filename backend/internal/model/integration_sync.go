@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+// IntegrationProvider identifies an external account a user can connect
+type IntegrationProvider string
+
+const (
+	IntegrationGitHub IntegrationProvider = "github"
+	IntegrationZenn   IntegrationProvider = "zenn"
+	IntegrationQiita  IntegrationProvider = "qiita"
+	// Code hosts connected through CodeHostAccount
+	IntegrationGitLab    IntegrationProvider = "gitlab"
+	IntegrationBitbucket IntegrationProvider = "bitbucket"
+	// RSS, Atom and JSON Feeds registered through ArticleFeed
+	IntegrationFeed IntegrationProvider = "feed"
+)
+
+// IntegrationSync tracks the sync state of one connected account.
+type IntegrationSync struct {
+	ID            uint                `json:"-" gorm:"primaryKey"`
+	UserID        uint                `json:"-" gorm:"not null;uniqueIndex:idx_integration_syncs_user_provider"`
+	Provider      IntegrationProvider `json:"provider" gorm:"size:20;not null;uniqueIndex:idx_integration_syncs_user_provider"`
+	LastSyncedAt  *time.Time          `json:"last_synced_at"`
+	LastSyncError string              `json:"last_sync_error,omitempty" gorm:"type:text"`
+	LastAttemptAt *time.Time          `json:"last_attempt_at,omitempty"`
+	// ConsecutiveFailures backs off scheduled syncs of a broken integration
+	ConsecutiveFailures int        `json:"-" gorm:"not null"`
+	NextSyncAt          *time.Time `json:"next_sync_at,omitempty" gorm:"index"`
+	UpdatedAt           time.Time  `json:"-"`
+}
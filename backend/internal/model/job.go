@@ -0,0 +1,34 @@
+package model
+
+import "time"
+
+// JobStatus represents the state of a background job
+type JobStatus string
+
+const (
+	JobStatusPending   JobStatus = "pending"
+	JobStatusRunning   JobStatus = "running"
+	JobStatusSucceeded JobStatus = "succeeded"
+	// JobStatusDead marks jobs that used up their attempts (the dead-letter state)
+	JobStatusDead JobStatus = "dead"
+)
+
+// Job is a unit of background work stored in Postgres so it survives restarts.
+type Job struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	Queue       string    `json:"queue" gorm:"size:50;not null"`
+	Type        string    `json:"type" gorm:"size:100;not null;index"`
+	Payload     string    `json:"payload" gorm:"type:jsonb;not null"`
+	Status      JobStatus `json:"status" gorm:"size:20;not null"`
+	Attempts    int       `json:"attempts" gorm:"not null"`
+	MaxAttempts int       `json:"max_attempts" gorm:"not null"`
+	RunAt       time.Time `json:"run_at" gorm:"not null"`
+	// UniqueKey, when set, prevents enqueueing a duplicate while one is pending or running
+	UniqueKey  *string    `json:"unique_key,omitempty" gorm:"size:200"`
+	LockedAt   *time.Time `json:"locked_at,omitempty"`
+	LockedBy   string     `json:"locked_by,omitempty" gorm:"size:100"`
+	LastError  string     `json:"last_error,omitempty" gorm:"type:text"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+// MentionSourceType identifies the kind of content a mention was written in
+type MentionSourceType string
+
+const (
+	MentionSourcePost         MentionSourceType = "post"
+	MentionSourceComment      MentionSourceType = "comment"
+	MentionSourceQuestion     MentionSourceType = "question"
+	MentionSourceAnswer       MentionSourceType = "answer"
+	MentionSourceMessage      MentionSourceType = "message"
+	MentionSourceGroupMessage MentionSourceType = "group_message"
+)
+
+// Mention records that a user was @mentioned in a piece of content
+type Mention struct {
+	ID         uint              `json:"id" gorm:"primaryKey"`
+	UserID     uint              `json:"user_id" gorm:"not null;index;uniqueIndex:idx_mention_source_user"`
+	ActorID    uint              `json:"actor_id" gorm:"not null;index"`
+	Actor      User              `json:"actor" gorm:"foreignKey:ActorID"`
+	SourceType MentionSourceType `json:"source_type" gorm:"size:20;not null;uniqueIndex:idx_mention_source_user"`
+	SourceID   uint              `json:"source_id" gorm:"not null;uniqueIndex:idx_mention_source_user"`
+	PostID     *uint             `json:"post_id" gorm:"index"`
+	Post       *Post             `json:"post,omitempty" gorm:"foreignKey:PostID"`
+	QuestionID *uint             `json:"question_id" gorm:"index"`
+	Question   *Question         `json:"question,omitempty" gorm:"foreignKey:QuestionID"`
+	ChatRoomID *uint             `json:"chat_room_id,omitempty" gorm:"index"`
+	Excerpt    string            `json:"excerpt" gorm:"size:300"`
+	CreatedAt  time.Time         `json:"created_at"`
+}
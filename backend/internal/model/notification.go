@@ -12,6 +12,8 @@ const (
 	NotificationTypeFollow  NotificationType = "follow"
 	NotificationTypeAnswer  NotificationType = "answer"
 	NotificationTypeBadge   NotificationType = "badge"
+	NotificationTypeMention NotificationType = "mention"
+	NotificationTypeRank    NotificationType = "rank"
 )
 
 type Notification struct {
@@ -25,7 +27,11 @@ type Notification struct {
 	Post       *Post            `json:"post,omitempty" gorm:"foreignKey:PostID"`
 	QuestionID *uint            `json:"question_id" gorm:"index"`
 	Question   *Question        `json:"question,omitempty" gorm:"foreignKey:QuestionID"`
+	ChatRoomID *uint            `json:"chat_room_id,omitempty" gorm:"index"`
 	BadgeID    *string          `json:"badge_id,omitempty" gorm:"size:50"`
+	// Rank and RankChange describe a move up the weekly leaderboard
+	Rank       *int             `json:"rank,omitempty"`
+	RankChange *int             `json:"rank_change,omitempty"`
 	Read       bool             `json:"read" gorm:"default:false"`
 	CreatedAt time.Time        `json:"created_at"`
 }
@@ -0,0 +1,64 @@
+package model
+
+import "time"
+
+// PrivacySettings controls what a user's public (unauthenticated) profile shows.
+// The profile itself is opt-in; once public, each section can be hidden.
+// Columns have no gorm defaults so that false values survive Create.
+type PrivacySettings struct {
+	ID                uint      `json:"-" gorm:"primaryKey"`
+	UserID            uint      `json:"user_id" gorm:"not null;uniqueIndex"`
+	ProfilePublic     bool      `json:"profile_public" gorm:"not null"`
+	ShowBadges        bool      `json:"show_badges" gorm:"not null"`
+	ShowContributions bool      `json:"show_contributions" gorm:"not null"`
+	ShowLanguages     bool      `json:"show_languages" gorm:"not null"`
+	ShowProjects      bool      `json:"show_projects" gorm:"not null"`
+	ShowArticles      bool      `json:"show_articles" gorm:"not null"`
+	ShowRoadmaps      bool      `json:"show_roadmaps" gorm:"not null"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// DefaultPrivacySettings returns the settings used for users who never saved any
+func DefaultPrivacySettings(userID uint) *PrivacySettings {
+	return &PrivacySettings{
+		UserID:            userID,
+		ProfilePublic:     false,
+		ShowBadges:        true,
+		ShowContributions: true,
+		ShowLanguages:     true,
+		ShowProjects:      true,
+		ShowArticles:      true,
+		ShowRoadmaps:      true,
+	}
+}
+
+// PublicUser is the subset of User that is safe to show to anonymous visitors
+type PublicUser struct {
+	ID               uint      `json:"id"`
+	Name             string    `json:"name"`
+	Handle           string    `json:"handle"`
+	AvatarURL        string    `json:"avatar_url"`
+	Bio              string    `json:"bio"`
+	GitHubUsername   string    `json:"github_username"`
+	ZennUsername     string    `json:"zenn_username"`
+	QiitaUsername    string    `json:"qiita_username"`
+	SkillsLanguages  string    `json:"skills_languages"`
+	SkillsFrameworks string    `json:"skills_frameworks"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+func NewPublicUser(u *User) PublicUser {
+	return PublicUser{
+		ID:               u.ID,
+		Name:             u.Name,
+		Handle:           u.Handle,
+		AvatarURL:        u.AvatarURL,
+		Bio:              u.Bio,
+		GitHubUsername:   u.GitHubUsername,
+		ZennUsername:     u.ZennUsername,
+		QiitaUsername:    u.QiitaUsername,
+		SkillsLanguages:  u.SkillsLanguages,
+		SkillsFrameworks: u.SkillsFrameworks,
+		CreatedAt:        u.CreatedAt,
+	}
+}
@@ -0,0 +1,108 @@
+package model
+
+import "time"
+
+// RankingPeriod is the window of activity a ranking covers, ending now
+type RankingPeriod string
+
+const (
+	RankingPeriodWeekly  RankingPeriod = "weekly"
+	RankingPeriodMonthly RankingPeriod = "monthly"
+	RankingPeriodYearly  RankingPeriod = "yearly"
+	RankingPeriodAllTime RankingPeriod = "all"
+)
+
+// Valid reports whether p is a known period.
+func (p RankingPeriod) Valid() bool {
+	switch p {
+	case RankingPeriodWeekly, RankingPeriodMonthly, RankingPeriodYearly, RankingPeriodAllTime:
+		return true
+	}
+	return false
+}
+
+// Since returns when the period starts, or the zero time for all time.
+func (p RankingPeriod) Since(now time.Time) time.Time {
+	switch p {
+	case RankingPeriodWeekly:
+		return now.AddDate(0, 0, -7)
+	case RankingPeriodMonthly:
+		return now.AddDate(0, 0, -30)
+	case RankingPeriodYearly:
+		return now.AddDate(-1, 0, 0)
+	}
+	return time.Time{}
+}
+
+// Ranking metrics
+const (
+	RankingMetricContributions     = "contributions"
+	RankingMetricPosts             = "posts"
+	RankingMetricLikesReceived     = "likes_received"
+	RankingMetricAcceptedAnswers   = "accepted_answers"
+	RankingMetricGoalsCompleted    = "goals_completed"
+	RankingMetricRoadmapsCompleted = "roadmaps_completed"
+	RankingMetricArticleLikes      = "article_likes"
+)
+
+// RankingScope limits a ranking to a group of users. The zero value ranks
+// everyone.
+type RankingScope struct {
+	// FollowerID limits the ranking to the users they follow, and themselves
+	FollowerID uint `json:"follower_id,omitempty"`
+	// ChatRoomID limits the ranking to the chat room's members
+	ChatRoomID uint `json:"chat_room_id,omitempty"`
+	// TeamID limits the ranking to the team's members
+	TeamID uint `json:"team_id,omitempty"`
+}
+
+// RankingEntry is one user's place in a ranking. Breakdown holds the raw
+// value of each metric that went into a composite score.
+type RankingEntry struct {
+	Rank      int              `json:"rank"`
+	UserID    uint             `json:"user_id"`
+	Name      string           `json:"name"`
+	AvatarURL string           `json:"avatar_url"`
+	Score     float64          `json:"score"`
+	Breakdown map[string]int64 `json:"breakdown,omitempty"`
+}
+
+// LeaderboardSnapshot is a ranking of everyone as materialized on one day by
+// the snapshot job. Later runs on the same day replace it.
+type LeaderboardSnapshot struct {
+	ID         uint          `json:"id" gorm:"primaryKey"`
+	Metric     string        `json:"metric" gorm:"size:50;not null"`
+	Period     RankingPeriod `json:"period" gorm:"size:20;not null"`
+	Date       time.Time     `json:"date" gorm:"type:date;not null"`
+	Total      int           `json:"total"`
+	ComputedAt time.Time     `json:"computed_at"`
+}
+
+// LeaderboardEntry is one user's place in a snapshot.
+type LeaderboardEntry struct {
+	SnapshotID uint                 `json:"snapshot_id" gorm:"primaryKey"`
+	Snapshot   *LeaderboardSnapshot `json:"snapshot,omitempty" gorm:"foreignKey:SnapshotID"`
+	UserID     uint                 `json:"user_id" gorm:"primaryKey"`
+	Rank       int                  `json:"rank"`
+	Score      float64              `json:"score"`
+	Breakdown  map[string]int64     `json:"breakdown,omitempty" gorm:"type:jsonb;serializer:json"`
+}
+
+// RankingPosition is where a user stands in a ranking. Rank is 0 when they
+// have no score. Percentile is the share of ranked users at or below their
+// rank.
+type RankingPosition struct {
+	Rank       int        `json:"rank"`
+	Score      float64    `json:"score"`
+	Total      int        `json:"total"`
+	Percentile float64    `json:"percentile"`
+	ComputedAt *time.Time `json:"computed_at,omitempty"`
+}
+
+// RankHistoryPoint is a user's place on one day's leaderboard
+type RankHistoryPoint struct {
+	Date  time.Time `json:"date"`
+	Rank  int       `json:"rank"`
+	Score float64   `json:"score"`
+	Total int       `json:"total"`
+}
@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// Team is a group of users, such as a study group, with its own leaderboards
+type Team struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	Name        string    `json:"name" gorm:"size:100;not null"`
+	Description string    `json:"description" gorm:"size:500"`
+	OwnerID     uint      `json:"owner_id" gorm:"not null;index"`
+	Owner       *User     `json:"owner,omitempty" gorm:"foreignKey:OwnerID"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type TeamMember struct {
+	ID       uint      `json:"id" gorm:"primaryKey"`
+	TeamID   uint      `json:"team_id" gorm:"not null;index;uniqueIndex:idx_team_user"`
+	Team     *Team     `json:"-" gorm:"foreignKey:TeamID"`
+	UserID   uint      `json:"user_id" gorm:"not null;index;uniqueIndex:idx_team_user"`
+	User     *User     `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	JoinedAt time.Time `json:"joined_at"`
+}
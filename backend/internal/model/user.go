@@ -5,6 +5,7 @@ import "time"
 type User struct {
 	ID              uint      `json:"id" gorm:"primaryKey"`
 	Name            string    `json:"name" gorm:"not null"`
+	Handle          string    `json:"handle" gorm:"size:39;uniqueIndex:idx_users_handle,where:handle <> ''"`
 	Email           string    `json:"email" gorm:"uniqueIndex;not null"`
 	Password        string    `json:"-"`
 	AvatarURL       string    `json:"avatar_url"`
@@ -13,11 +14,20 @@ type User struct {
 	GitHubUsername  string    `json:"github_username"`
 	GitHubToken     string    `json:"-"`
 	GitHubConnected  bool      `json:"github_connected" gorm:"default:false"`
+	// GitHubCreatedAt is when the GitHub account was created; contribution
+	// history is backfilled back to it
+	GitHubCreatedAt  *time.Time `json:"github_created_at,omitempty"`
 	ZennUsername     string    `json:"zenn_username"`
 	QiitaUsername    string    `json:"qiita_username"`
+	// QiitaToken is the user's encrypted Qiita access token, if they gave one
+	QiitaToken       string    `json:"-"`
 	SkillsLanguages  string    `json:"skills_languages"`
 	SkillsFrameworks    string    `json:"skills_frameworks"`
 	OnboardingCompleted bool      `json:"onboarding_completed" gorm:"default:false"`
+	// GitHubAutoPost turns notable GitHub activity into timeline posts
+	GitHubAutoPost      bool       `json:"github_auto_post" gorm:"not null"`
+	// DeletionScheduledAt is set while the account is in its deletion grace period
+	DeletionScheduledAt *time.Time `json:"deletion_scheduled_at,omitempty" gorm:"index"`
 	CreatedAt           time.Time `json:"created_at"`
 	UpdatedAt        time.Time `json:"updated_at"`
 }
@@ -0,0 +1,143 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronEntry enqueues a job whenever its schedule matches the current minute.
+type cronEntry struct {
+	name     string
+	jobType  string
+	payload  interface{}
+	schedule *cronSchedule
+}
+
+// Schedule enqueues jobType with payload on a standard five-field cron spec
+// ("minute hour day-of-month month day-of-week", evaluated in UTC). Each run
+// is enqueued as a unique job keyed by the minute, so running several server
+// instances doesn't run the job several times.
+func (q *Queue) Schedule(name, spec, jobType string, payload interface{}) error {
+	schedule, err := parseCron(spec)
+	if err != nil {
+		return fmt.Errorf("queue: schedule %s: %w", name, err)
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.schedule = append(q.schedule, &cronEntry{name: name, jobType: jobType, payload: payload, schedule: schedule})
+	return nil
+}
+
+func (q *Queue) runScheduler(ctx context.Context) {
+	for {
+		now := time.Now().UTC()
+		next := now.Truncate(time.Minute).Add(time.Minute)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(next.Sub(now)):
+		}
+
+		q.mu.RLock()
+		entries := append([]*cronEntry(nil), q.schedule...)
+		q.mu.RUnlock()
+		for _, e := range entries {
+			if !e.schedule.matches(next) {
+				continue
+			}
+			key := fmt.Sprintf("cron:%s:%s", e.name, next.Format("200601021504"))
+			if err := q.EnqueueWith(e.jobType, e.payload, Options{RunAt: next, UniqueKey: key}); err != nil {
+				log.Printf("queue: failed to enqueue scheduled job %s: %v", e.name, err)
+			}
+		}
+	}
+}
+
+// cronSchedule holds the allowed values of each field.
+type cronSchedule struct {
+	minute, hour, dom, month, dow map[int]bool
+	// domAny and dowAny record "*" fields: when both day fields are
+	// restricted, a time matches if either does (as in standard cron).
+	domAny, dowAny bool
+}
+
+func parseCron(spec string) (*cronSchedule, error) {
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("expected 5 fields, got %d", len(fields))
+	}
+	bounds := [5][2]int{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 6}}
+	var sets [5]map[int]bool
+	for i, f := range fields {
+		set, err := parseCronField(f, bounds[i][0], bounds[i][1])
+		if err != nil {
+			return nil, fmt.Errorf("field %q: %w", f, err)
+		}
+		sets[i] = set
+	}
+	return &cronSchedule{
+		minute: sets[0], hour: sets[1], dom: sets[2], month: sets[3], dow: sets[4],
+		domAny: fields[2] == "*", dowAny: fields[4] == "*",
+	}, nil
+}
+
+// parseCronField accepts "*", "n", "a-b", any of those with "/step", and
+// comma-separated lists of them.
+func parseCronField(field string, min, max int) (map[int]bool, error) {
+	set := make(map[int]bool)
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			s, err := strconv.Atoi(part[i+1:])
+			if err != nil || s <= 0 {
+				return nil, fmt.Errorf("invalid step")
+			}
+			step = s
+			part = part[:i]
+		}
+
+		lo, hi := min, max
+		switch {
+		case part == "*":
+		case strings.Contains(part, "-"):
+			bounds := strings.SplitN(part, "-", 2)
+			a, err1 := strconv.Atoi(bounds[0])
+			b, err2 := strconv.Atoi(bounds[1])
+			if err1 != nil || err2 != nil {
+				return nil, fmt.Errorf("invalid range")
+			}
+			lo, hi = a, b
+		default:
+			n, err := strconv.Atoi(part)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value")
+			}
+			lo, hi = n, n
+			if step > 1 {
+				hi = max
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return nil, fmt.Errorf("out of range")
+		}
+		for v := lo; v <= hi; v += step {
+			set[v] = true
+		}
+	}
+	return set, nil
+}
+
+func (s *cronSchedule) matches(t time.Time) bool {
+	if !s.minute[t.Minute()] || !s.hour[t.Hour()] || !s.month[int(t.Month())] {
+		return false
+	}
+	dom, dow := s.dom[t.Day()], s.dow[int(t.Weekday())]
+	if s.domAny || s.dowAny {
+		return dom && dow
+	}
+	return dom || dow
+}
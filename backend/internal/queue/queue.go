@@ -0,0 +1,297 @@
+// Package queue is a durable, Postgres-backed background job queue with
+// retries, exponential backoff, dead-lettering, per-queue concurrency limits,
+// unique jobs and a cron-style scheduler.
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/norman6464/devsync/backend/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// DefaultQueue is used by job types that don't name a queue.
+	DefaultQueue = "default"
+
+	defaultMaxAttempts = 5
+	pollInterval       = time.Second
+	// lockTimeout is how long a job may stay running before it is assumed its
+	// worker died and it is made runnable again.
+	lockTimeout = 15 * time.Minute
+	maxBackoff  = time.Hour
+)
+
+// Handler runs a job. Returning an error schedules a retry.
+type Handler func(ctx context.Context, payload json.RawMessage) error
+
+// JobType describes a kind of job and how it runs.
+type JobType struct {
+	Name    string
+	Queue   string
+	Handler Handler
+	// MaxAttempts before the job is dead-lettered (default 5)
+	MaxAttempts int
+	// Timeout per attempt (default and maximum lockTimeout)
+	Timeout time.Duration
+}
+
+// Options customize a single enqueue.
+type Options struct {
+	// RunAt delays the job; zero means now.
+	RunAt time.Time
+	// UniqueKey skips the enqueue if a job with the same key is pending or running.
+	UniqueKey string
+}
+
+type Queue struct {
+	db          *gorm.DB
+	workerID    string
+	concurrency map[string]int
+
+	mu       sync.RWMutex
+	types    map[string]JobType
+	schedule []*cronEntry
+}
+
+// New creates a queue. concurrency maps queue names to their number of
+// workers; queues not listed get one worker.
+func New(db *gorm.DB, concurrency map[string]int) *Queue {
+	host, _ := os.Hostname()
+	return &Queue{
+		db:          db,
+		workerID:    fmt.Sprintf("%s-%d", host, os.Getpid()),
+		concurrency: concurrency,
+		types:       make(map[string]JobType),
+	}
+}
+
+// Register adds a job type. It must be called before Start.
+func (q *Queue) Register(t JobType) {
+	if t.Queue == "" {
+		t.Queue = DefaultQueue
+	}
+	if t.MaxAttempts <= 0 {
+		t.MaxAttempts = defaultMaxAttempts
+	}
+	if t.Timeout <= 0 || t.Timeout > lockTimeout {
+		t.Timeout = lockTimeout
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.types[t.Name] = t
+}
+
+func (q *Queue) jobType(name string) (JobType, bool) {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	t, ok := q.types[name]
+	return t, ok
+}
+
+// Enqueue stores a job to run as soon as a worker is free.
+func (q *Queue) Enqueue(jobType string, payload interface{}) error {
+	return q.EnqueueWith(jobType, payload, Options{})
+}
+
+// EnqueueWith stores a job with the given options.
+func (q *Queue) EnqueueWith(jobType string, payload interface{}, opts Options) error {
+	t, ok := q.jobType(jobType)
+	if !ok {
+		return fmt.Errorf("queue: unknown job type %q", jobType)
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	job := &model.Job{
+		Queue:       t.Queue,
+		Type:        t.Name,
+		Payload:     string(data),
+		Status:      model.JobStatusPending,
+		MaxAttempts: t.MaxAttempts,
+		RunAt:       opts.RunAt,
+	}
+	if job.RunAt.IsZero() {
+		job.RunAt = time.Now()
+	}
+	if opts.UniqueKey != "" {
+		job.UniqueKey = &opts.UniqueKey
+	}
+	// A conflict on the partial unique index means an identical job is queued
+	return q.db.Clauses(clause.OnConflict{DoNothing: true}).Create(job).Error
+}
+
+// Start launches the workers and the scheduler. They stop when ctx is done.
+func (q *Queue) Start(ctx context.Context) {
+	queues := make(map[string]bool)
+	q.mu.RLock()
+	for _, t := range q.types {
+		queues[t.Queue] = true
+	}
+	q.mu.RUnlock()
+
+	for name := range queues {
+		workers := q.concurrency[name]
+		if workers <= 0 {
+			workers = 1
+		}
+		for i := 0; i < workers; i++ {
+			go q.work(ctx, name)
+		}
+	}
+	go q.runScheduler(ctx)
+	go q.maintain(ctx)
+}
+
+func (q *Queue) work(ctx context.Context, queueName string) {
+	for {
+		job, err := q.claim(queueName)
+		if err != nil {
+			log.Printf("queue %s: claim failed: %v", queueName, err)
+		}
+		if job == nil {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(pollInterval):
+			}
+			continue
+		}
+		q.run(ctx, job)
+	}
+}
+
+// claim locks the next runnable job in the queue. SKIP LOCKED lets several
+// workers and server instances poll the same table without blocking.
+func (q *Queue) claim(queueName string) (*model.Job, error) {
+	var jobs []model.Job
+	err := q.db.Raw(`UPDATE jobs SET status = ?, locked_at = NOW(), locked_by = ?, attempts = attempts + 1, updated_at = NOW()
+		WHERE id = (
+			SELECT id FROM jobs
+			WHERE queue = ? AND status = ? AND run_at <= NOW()
+			ORDER BY run_at, id
+			FOR UPDATE SKIP LOCKED
+			LIMIT 1
+		)
+		RETURNING *`,
+		model.JobStatusRunning, q.workerID, queueName, model.JobStatusPending).Scan(&jobs).Error
+	if err != nil || len(jobs) == 0 {
+		return nil, err
+	}
+	return &jobs[0], nil
+}
+
+func (q *Queue) run(ctx context.Context, job *model.Job) {
+	t, ok := q.jobType(job.Type)
+	if !ok {
+		q.fail(job, fmt.Errorf("no handler registered for %q", job.Type))
+		return
+	}
+
+	runCtx, cancel := context.WithTimeout(context.WithValue(ctx, jobContextKey{}, job), t.Timeout)
+	defer cancel()
+
+	err := func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("panic: %v\n%s", r, debug.Stack())
+			}
+		}()
+		return t.Handler(runCtx, json.RawMessage(job.Payload))
+	}()
+
+	if err != nil {
+		q.fail(job, err)
+		return
+	}
+
+	now := time.Now()
+	q.db.Model(job).Updates(map[string]interface{}{
+		"status":      model.JobStatusSucceeded,
+		"finished_at": now,
+		"locked_at":   nil,
+		"last_error":  "",
+	})
+}
+
+// fail schedules a retry with exponential backoff, or dead-letters the job
+// once it has used all its attempts.
+func (q *Queue) fail(job *model.Job, err error) {
+	updates := map[string]interface{}{
+		"last_error": err.Error(),
+		"locked_at":  nil,
+	}
+	if job.Attempts >= job.MaxAttempts {
+		log.Printf("queue: job %d (%s) dead after %d attempts: %v", job.ID, job.Type, job.Attempts, err)
+		updates["status"] = model.JobStatusDead
+		updates["finished_at"] = time.Now()
+	} else {
+		log.Printf("queue: job %d (%s) attempt %d failed: %v", job.ID, job.Type, job.Attempts, err)
+		updates["status"] = model.JobStatusPending
+		updates["run_at"] = time.Now().Add(backoff(job.Attempts))
+	}
+	q.db.Model(job).Updates(updates)
+}
+
+// backoff returns 10s, 20s, 40s, ... up to maxBackoff, with ±20% jitter so
+// retries of jobs that failed together don't stampede.
+func backoff(attempt int) time.Duration {
+	d := 10 * time.Second << uint(attempt-1)
+	if d <= 0 || d > maxBackoff {
+		d = maxBackoff
+	}
+	jitter := time.Duration(rand.Int63n(int64(d)/5*2+1)) - d/5
+	return d + jitter
+}
+
+// maintain periodically rescues jobs abandoned by crashed workers and prunes
+// old finished jobs. Dead jobs are kept for inspection.
+func (q *Queue) maintain(ctx context.Context) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for {
+		if err := q.db.Model(&model.Job{}).
+			Where("status = ? AND locked_at < ?", model.JobStatusRunning, time.Now().Add(-lockTimeout)).
+			Updates(map[string]interface{}{"status": model.JobStatusPending, "locked_at": nil, "last_error": "worker lost"}).Error; err != nil {
+			log.Printf("queue: failed to rescue stale jobs: %v", err)
+		}
+		if err := q.db.Where("status = ? AND finished_at < ?", model.JobStatusSucceeded, time.Now().Add(-7*24*time.Hour)).
+			Delete(&model.Job{}).Error; err != nil {
+			log.Printf("queue: failed to prune jobs: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+type jobContextKey struct{}
+
+// IsFinalAttempt reports whether the job running with ctx will be
+// dead-lettered if it fails, so handlers can record a permanent failure.
+func IsFinalAttempt(ctx context.Context) bool {
+	job, ok := ctx.Value(jobContextKey{}).(*model.Job)
+	return !ok || job.Attempts >= job.MaxAttempts
+}
+
+// Decode unmarshals a job payload into v.
+func Decode(payload json.RawMessage, v interface{}) error {
+	if err := json.Unmarshal(payload, v); err != nil {
+		return fmt.Errorf("decode payload: %w", err)
+	}
+	return nil
+}
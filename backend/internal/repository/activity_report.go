@@ -45,7 +45,7 @@ func (r *ActivityReportRepository) generateReport(userID uint, period model.Repo
 
 	// Get GitHub contributions in the period
 	var totalContributions int64
-	r.db.Model(&model.GitHubContribution{}).
+	r.db.Model(&model.Contribution{}).
 		Where("user_id = ? AND date >= ? AND date < ?", userID, startDate, endDate).
 		Select("COALESCE(SUM(count), 0)").
 		Scan(&totalContributions)
@@ -106,6 +106,18 @@ func (r *ActivityReportRepository) generateReport(userID uint, period model.Repo
 		Count(&messagesReceived)
 	report.MessagesExchanged = int(messagesSent + messagesReceived)
 
+	// Get articles published
+	var articlesPublished int64
+	r.db.Model(&model.ExternalArticle{}).
+		Where("user_id = ? AND published_at >= ? AND published_at < ?", userID, startDate, endDate).
+		Count(&articlesPublished)
+	report.ArticlesPublished = int(articlesPublished)
+
+	// Get pull requests, reviews and issues
+	if metrics, err := githubMetrics(r.db, userID, []time.Time{startDate, endDate}); err == nil {
+		report.GitHubMetrics = metrics[0]
+	}
+
 	// Get daily contributions
 	report.DailyContributions = r.getDailyActivity(userID, startDate, endDate)
 
@@ -129,7 +141,7 @@ func (r *ActivityReportRepository) getDailyActivity(userID uint, startDate, endD
 
 		// Get contributions for this day
 		var contributions int64
-		r.db.Model(&model.GitHubContribution{}).
+		r.db.Model(&model.Contribution{}).
 			Where("user_id = ? AND date = ?", userID, dateStr).
 			Select("COALESCE(SUM(count), 0)").
 			Scan(&contributions)
@@ -158,7 +170,7 @@ func (r *ActivityReportRepository) getDailyActivity(userID uint, startDate, endD
 func (r *ActivityReportRepository) getTopLanguages(userID uint) []model.LanguageActivity {
 	var languages []model.LanguageActivity
 
-	r.db.Model(&model.GitHubLanguageStat{}).
+	r.db.Table("language_stats").
 		Where("user_id = ?", userID).
 		Select("language, bytes, repo_count as repos").
 		Order("bytes DESC").
@@ -190,15 +202,18 @@ func (r *ActivityReportRepository) GetComparison(userID uint, period model.Repor
 	prevReport, _ := r.generateReport(userID, period, prevStart, prevEnd)
 
 	comparison := &model.ReportComparison{
-		ContributionsDiff: currentReport.TotalContributions - prevReport.TotalContributions,
-		PostsDiff:         currentReport.PostsCreated - prevReport.PostsCreated,
-		FollowersDiff:     currentReport.NewFollowers - prevReport.NewFollowers,
-		GoalsDiff:         currentReport.GoalsCompleted - prevReport.GoalsCompleted,
+		ContributionsDiff:      currentReport.TotalContributions - prevReport.TotalContributions,
+		PostsDiff:              currentReport.PostsCreated - prevReport.PostsCreated,
+		FollowersDiff:          currentReport.NewFollowers - prevReport.NewFollowers,
+		GoalsDiff:              currentReport.GoalsCompleted - prevReport.GoalsCompleted,
+		PullRequestsMergedDiff: currentReport.PullRequestsMerged - prevReport.PullRequestsMerged,
+		ReviewsDiff:            currentReport.ReviewsGiven - prevReport.ReviewsGiven,
+		ArticlesDiff:           currentReport.ArticlesPublished - prevReport.ArticlesPublished,
 	}
 
 	// Calculate trend percentage
-	prevTotal := float64(prevReport.TotalContributions + prevReport.PostsCreated*10 + prevReport.GoalsCompleted*20)
-	currTotal := float64(currentReport.TotalContributions + currentReport.PostsCreated*10 + currentReport.GoalsCompleted*20)
+	prevTotal := float64(prevReport.TotalContributions + prevReport.PostsCreated*10 + prevReport.ArticlesPublished*10 + prevReport.GoalsCompleted*20)
+	currTotal := float64(currentReport.TotalContributions + currentReport.PostsCreated*10 + currentReport.ArticlesPublished*10 + currentReport.GoalsCompleted*20)
 	if prevTotal > 0 {
 		comparison.TrendPercentage = ((currTotal - prevTotal) / prevTotal) * 100
 	} else if currTotal > 0 {
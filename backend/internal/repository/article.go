@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/norman6464/devsync/backend/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ArticleRepository stores the articles users publish on every source -
+// Zenn, Qiita and feeds - in one table.
+type ArticleRepository struct {
+	db *gorm.DB
+}
+
+func NewArticleRepository(db *gorm.DB) *ArticleRepository {
+	return &ArticleRepository{db: db}
+}
+
+// UpsertArticles inserts or updates the user's articles from source
+func (r *ArticleRepository) UpsertArticles(userID uint, source model.IntegrationProvider, articles []model.ExternalArticle) error {
+	if len(articles) == 0 {
+		return nil
+	}
+
+	for i := range articles {
+		articles[i].UserID = userID
+		articles[i].Source = source
+	}
+
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "source"}, {Name: "external_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"feed_id", "title", "url", "summary", "emoji", "category", "likes", "comments", "stocks", "tags", "published_at", "updated_at", "private"}),
+	}).Create(&articles).Error
+}
+
+// GetArticles returns the user's articles, newest first, optionally only
+// those from source. Private articles are left out unless includePrivate.
+func (r *ArticleRepository) GetArticles(userID uint, source model.IntegrationProvider, includePrivate bool, page, limit int) ([]model.ExternalArticle, error) {
+	var articles []model.ExternalArticle
+	err := r.visible(r.scope(userID, source), includePrivate).Order("published_at DESC").Offset((page - 1) * limit).Limit(limit).Find(&articles).Error
+	return articles, err
+}
+
+func (r *ArticleRepository) FindByID(id uint) (*model.ExternalArticle, error) {
+	var article model.ExternalArticle
+	if err := r.db.First(&article, id).Error; err != nil {
+		return nil, err
+	}
+	return &article, nil
+}
+
+// GetTopArticles returns the user's most liked public articles across all
+// sources
+func (r *ArticleRepository) GetTopArticles(userID uint, limit int) ([]model.ExternalArticle, error) {
+	var articles []model.ExternalArticle
+	err := r.db.Where("user_id = ? AND NOT private", userID).Order("likes DESC, published_at DESC").Limit(limit).Find(&articles).Error
+	return articles, err
+}
+
+// GetStats totals the user's articles, likes and comments per source,
+// optionally only for source. Private articles count only if includePrivate.
+func (r *ArticleRepository) GetStats(userID uint, source model.IntegrationProvider, includePrivate bool) (*model.ArticleStats, error) {
+	stats := &model.ArticleStats{Sources: []model.ArticleSourceStats{}}
+	err := r.visible(r.scope(userID, source), includePrivate).Model(&model.ExternalArticle{}).
+		Select("source, COUNT(*) AS articles, COALESCE(SUM(likes), 0) AS likes, COALESCE(SUM(comments), 0) AS comments").
+		Group("source").
+		Order("source ASC").
+		Scan(&stats.Sources).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, s := range stats.Sources {
+		stats.TotalArticles += s.Articles
+		stats.TotalLikes += s.Likes
+		stats.TotalComments += s.Comments
+	}
+	return stats, nil
+}
+
+// KnownArticles returns when each of the user's articles from source was
+// last synced, by external ID.
+func (r *ArticleRepository) KnownArticles(userID uint, source model.IntegrationProvider) (map[string]time.Time, error) {
+	var rows []struct {
+		ExternalID string
+		UpdatedAt  time.Time
+	}
+	err := r.scope(userID, source).Model(&model.ExternalArticle{}).Select("external_id, updated_at").Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[string]time.Time, len(rows))
+	for _, row := range rows {
+		known[row.ExternalID] = row.UpdatedAt
+	}
+	return known, nil
+}
+
+// RecordEngagement snapshots the counts of the user's articles from source
+// with the given external IDs as of date. Only articles just read from the
+// source should be passed, since the stored counts of the others may be days
+// old. A later sync on the same day overwrites the snapshot.
+func (r *ArticleRepository) RecordEngagement(userID uint, source model.IntegrationProvider, externalIDs []string, date time.Time) error {
+	if len(externalIDs) == 0 {
+		return nil
+	}
+	return r.db.Exec(`
+		INSERT INTO article_engagement_snapshots (article_id, date, user_id, likes, comments, stocks)
+		SELECT id, ?, user_id, likes, comments, stocks
+		FROM external_articles
+		WHERE user_id = ? AND source = ? AND external_id IN ?
+		ON CONFLICT (article_id, date) DO UPDATE
+		SET likes = EXCLUDED.likes, comments = EXCLUDED.comments, stocks = EXCLUDED.stocks
+	`, date, userID, source, externalIDs).Error
+}
+
+// GetArticleEngagement returns an article's daily counts since the given
+// date, oldest first.
+func (r *ArticleRepository) GetArticleEngagement(articleID uint, since time.Time) ([]model.ArticleEngagement, error) {
+	series := []model.ArticleEngagement{}
+	err := r.db.Model(&model.ArticleEngagementSnapshot{}).
+		Select("date, likes, comments, stocks").
+		Where("article_id = ? AND date >= ?", articleID, since).
+		Order("date ASC").
+		Scan(&series).Error
+	return series, err
+}
+
+// GetUserEngagement returns the daily counts summed over the user's articles
+// since the given date, oldest first, optionally only from source. Private
+// articles count only if includePrivate. Syncs only snapshot the articles
+// they read, so each day sums every article's latest snapshot up to it.
+func (r *ArticleRepository) GetUserEngagement(userID uint, source model.IntegrationProvider, includePrivate bool, since time.Time) ([]model.ArticleEngagement, error) {
+	series := []model.ArticleEngagement{}
+	query := r.db.Table("generate_series(?::date, CURRENT_DATE, interval '1 day') AS d(date)", since).
+		Select("d.date::date AS date, SUM(s.likes) AS likes, SUM(s.comments) AS comments, SUM(s.stocks) AS stocks").
+		Joins("JOIN external_articles a ON a.user_id = ?", userID).
+		Joins(`JOIN LATERAL (
+			SELECT likes, comments, stocks FROM article_engagement_snapshots
+			WHERE article_id = a.id AND date <= d.date
+			ORDER BY date DESC LIMIT 1
+		) s ON true`)
+	if source != "" {
+		query = query.Where("a.source = ?", source)
+	}
+	if !includePrivate {
+		query = query.Where("NOT a.private")
+	}
+	err := query.Group("d.date").Order("d.date ASC").Scan(&series).Error
+	return series, err
+}
+
+// GetTrending returns the public articles across all users that gained the most
+// likes since the given date. Growth is measured from each article's last
+// snapshot on or before that date, or from zero for articles published
+// since; articles with neither are left out.
+func (r *ArticleRepository) GetTrending(since time.Time, limit int) ([]model.TrendingArticle, error) {
+	articles := []model.TrendingArticle{}
+	err := r.db.Raw(`
+		SELECT a.*, u.name AS author_name, u.avatar_url AS author_avatar_url,
+		       a.likes - COALESCE(s.likes, 0) AS likes_gained,
+		       a.comments - COALESCE(s.comments, 0) AS comments_gained,
+		       a.stocks - COALESCE(s.stocks, 0) AS stocks_gained
+		FROM external_articles a
+		JOIN users u ON u.id = a.user_id AND u.deletion_scheduled_at IS NULL
+		LEFT JOIN LATERAL (
+			SELECT likes, comments, stocks
+			FROM article_engagement_snapshots
+			WHERE article_id = a.id AND date <= ?
+			ORDER BY date DESC
+			LIMIT 1
+		) s ON true
+		WHERE NOT a.private
+		  AND (s.likes IS NOT NULL OR a.published_at >= ?)
+		  AND a.likes - COALESCE(s.likes, 0) > 0
+		ORDER BY likes_gained DESC, a.published_at DESC
+		LIMIT ?
+	`, since, since, limit).Scan(&articles).Error
+	return articles, err
+}
+
+// DeleteBySource removes the user's articles from source
+func (r *ArticleRepository) DeleteBySource(userID uint, source model.IntegrationProvider) error {
+	return r.db.Where("user_id = ? AND source = ?", userID, source).Delete(&model.ExternalArticle{}).Error
+}
+
+func (r *ArticleRepository) scope(userID uint, source model.IntegrationProvider) *gorm.DB {
+	query := r.db.Where("user_id = ?", userID)
+	if source != "" {
+		query = query.Where("source = ?", source)
+	}
+	return query
+}
+
+// visible leaves private articles out of query unless includePrivate
+func (r *ArticleRepository) visible(query *gorm.DB, includePrivate bool) *gorm.DB {
+	if includePrivate {
+		return query
+	}
+	return query.Where("NOT private")
+}
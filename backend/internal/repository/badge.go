@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/norman6464/devsync/backend/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type BadgeRepository struct {
+	db *gorm.DB
+}
+
+func NewBadgeRepository(db *gorm.DB) *BadgeRepository {
+	return &BadgeRepository{db: db}
+}
+
+// Definitions returns every badge in display order.
+func (r *BadgeRepository) Definitions() ([]model.BadgeDefinition, error) {
+	var badges []model.BadgeDefinition
+	err := r.db.Order("sort_order ASC, id ASC").Find(&badges).Error
+	return badges, err
+}
+
+// FindByUserID returns the badges awarded to the user.
+func (r *BadgeRepository) FindByUserID(userID uint) ([]model.UserBadge, error) {
+	var awards []model.UserBadge
+	err := r.db.Where("user_id = ?", userID).Order("awarded_at ASC").Find(&awards).Error
+	return awards, err
+}
+
+// Award gives the user a badge at the given time. It reports false if they
+// already had it.
+func (r *BadgeRepository) Award(userID uint, badgeID string, at time.Time) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&model.UserBadge{UserID: userID, BadgeID: badgeID, AwardedAt: at})
+	return result.RowsAffected > 0, result.Error
+}
+
+// HolderCounts returns how many users hold each badge that has been awarded.
+func (r *BadgeRepository) HolderCounts() (map[string]int, error) {
+	var rows []struct {
+		BadgeID string
+		Holders int
+	}
+	err := r.db.Model(&model.UserBadge{}).
+		Select("badge_id, COUNT(*) AS holders").
+		Group("badge_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int, len(rows))
+	for _, row := range rows {
+		counts[row.BadgeID] = row.Holders
+	}
+	return counts, nil
+}
@@ -1,8 +1,6 @@
 package repository
 
 import (
-	"time"
-
 	"github.com/norman6464/devsync/backend/internal/model"
 	"gorm.io/gorm"
 )
@@ -15,8 +13,18 @@ func NewChatRoomRepository(db *gorm.DB) *ChatRoomRepository {
 	return &ChatRoomRepository{db: db}
 }
 
-func (r *ChatRoomRepository) Create(room *model.ChatRoom) error {
-	return r.db.Create(room).Error
+// Create stores the room with its owner and the given users as members
+func (r *ChatRoomRepository) Create(room *model.ChatRoom, memberIDs []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(room).Error; err != nil {
+			return err
+		}
+		return chatRoomMembers.add(tx, room.ID, append([]uint{room.OwnerID}, memberIDs...)...)
+	})
+}
+
+func (r *ChatRoomRepository) FindOwnerID(roomID uint) (uint, error) {
+	return chatRoomMembers.ownerID(r.db, roomID)
 }
 
 func (r *ChatRoomRepository) FindByID(id uint) (*model.ChatRoom, error) {
@@ -51,18 +59,13 @@ func (r *ChatRoomRepository) Delete(roomID uint) error {
 	})
 }
 
+// AddMember returns ErrAlreadyMember if the user is already a member
 func (r *ChatRoomRepository) AddMember(roomID, userID uint) error {
-	member := model.ChatRoomMember{
-		ChatRoomID: roomID,
-		UserID:     userID,
-		JoinedAt:   time.Now(),
-	}
-	return r.db.Create(&member).Error
+	return chatRoomMembers.add(r.db, roomID, userID)
 }
 
 func (r *ChatRoomRepository) RemoveMember(roomID, userID uint) error {
-	return r.db.Where("chat_room_id = ? AND user_id = ?", roomID, userID).
-		Delete(&model.ChatRoomMember{}).Error
+	return chatRoomMembers.remove(r.db, roomID, userID)
 }
 
 func (r *ChatRoomRepository) GetMembers(roomID uint) ([]model.ChatRoomMember, error) {
@@ -72,9 +75,5 @@ func (r *ChatRoomRepository) GetMembers(roomID uint) ([]model.ChatRoomMember, er
 }
 
 func (r *ChatRoomRepository) IsMember(roomID, userID uint) (bool, error) {
-	var count int64
-	err := r.db.Model(&model.ChatRoomMember{}).
-		Where("chat_room_id = ? AND user_id = ?", roomID, userID).
-		Count(&count).Error
-	return count > 0, err
+	return chatRoomMembers.isMember(r.db, roomID, userID)
 }
@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/norman6464/devsync/backend/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CodeHostRepository stores GitLab and Bitbucket accounts and the
+// repositories, languages and contributions synced from them.
+type CodeHostRepository struct {
+	db *gorm.DB
+}
+
+func NewCodeHostRepository(db *gorm.DB) *CodeHostRepository {
+	return &CodeHostRepository{db: db}
+}
+
+func (r *CodeHostRepository) FindAccount(userID uint, provider model.IntegrationProvider) (*model.CodeHostAccount, error) {
+	var account model.CodeHostAccount
+	if err := r.db.Where("user_id = ? AND provider = ?", userID, provider).First(&account).Error; err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+func (r *CodeHostRepository) FindAccountByExternalID(provider model.IntegrationProvider, externalID string) (*model.CodeHostAccount, error) {
+	var account model.CodeHostAccount
+	if err := r.db.Where("provider = ? AND external_id = ?", provider, externalID).First(&account).Error; err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+func (r *CodeHostRepository) FindAccountsByUserID(userID uint) ([]model.CodeHostAccount, error) {
+	var accounts []model.CodeHostAccount
+	err := r.db.Where("user_id = ?", userID).Order("provider ASC").Find(&accounts).Error
+	return accounts, err
+}
+
+// SaveAccount creates or replaces the user's account on the provider.
+func (r *CodeHostRepository) SaveAccount(account *model.CodeHostAccount) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}, {Name: "provider"}},
+		DoUpdates: clause.AssignmentColumns([]string{"external_id", "username", "avatar_url", "profile_url",
+			"access_token", "refresh_token", "token_expires_at", "tokens_encrypted", "updated_at"}),
+	}).Create(account).Error
+}
+
+// UpdateTokens stores the account's encrypted tokens.
+func (r *CodeHostRepository) UpdateTokens(accountID uint, accessToken, refreshToken string, expiresAt *time.Time) error {
+	return r.db.Model(&model.CodeHostAccount{}).Where("id = ?", accountID).Updates(map[string]interface{}{
+		"access_token":     accessToken,
+		"refresh_token":    refreshToken,
+		"token_expires_at": expiresAt,
+		"tokens_encrypted": true,
+	}).Error
+}
+
+// ReplaceRepos stores the user's repositories on the provider, removing
+// those no longer listed.
+func (r *CodeHostRepository) ReplaceRepos(userID uint, provider model.IntegrationProvider, repos []model.CodeHostRepository) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		ids := make([]string, 0, len(repos))
+		for _, repo := range repos {
+			ids = append(ids, repo.ExternalID)
+		}
+		query := tx.Where("user_id = ? AND provider = ?", userID, provider)
+		if len(ids) > 0 {
+			query = query.Where("external_id NOT IN ?", ids)
+		}
+		if err := query.Delete(&model.CodeHostRepository{}).Error; err != nil {
+			return err
+		}
+		if len(repos) == 0 {
+			return nil
+		}
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "provider"}, {Name: "external_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"name", "full_name", "description", "language", "stars", "forks", "is_private", "url", "updated_at"}),
+		}).Create(&repos).Error
+	})
+}
+
+func (r *CodeHostRepository) GetRepos(userID uint, provider model.IntegrationProvider) ([]model.CodeHostRepository, error) {
+	var repos []model.CodeHostRepository
+	err := r.db.Where("user_id = ? AND provider = ?", userID, provider).Order("stars DESC, name ASC").Find(&repos).Error
+	return repos, err
+}
+
+func (r *CodeHostRepository) ReplaceLanguageStats(userID uint, provider model.IntegrationProvider, stats []model.CodeHostLanguageStat) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ? AND provider = ?", userID, provider).Delete(&model.CodeHostLanguageStat{}).Error; err != nil {
+			return err
+		}
+		if len(stats) == 0 {
+			return nil
+		}
+		return tx.Create(&stats).Error
+	})
+}
+
+func (r *CodeHostRepository) GetLanguageStats(userID uint, provider model.IntegrationProvider) ([]model.CodeHostLanguageStat, error) {
+	var stats []model.CodeHostLanguageStat
+	err := r.db.Where("user_id = ? AND provider = ?", userID, provider).Order("bytes DESC, repo_count DESC").Find(&stats).Error
+	return stats, err
+}
+
+// LatestContributionDate returns the last day the user contributed on the
+// provider, or nil if none are stored.
+func (r *CodeHostRepository) LatestContributionDate(userID uint, provider model.IntegrationProvider) (*time.Time, error) {
+	var row struct {
+		Latest *time.Time
+	}
+	err := r.db.Model(&model.CodeHostContribution{}).
+		Where("user_id = ? AND provider = ?", userID, provider).
+		Select("MAX(date) AS latest").Scan(&row).Error
+	return row.Latest, err
+}
+
+// ReplaceContributions replaces the user's daily counts on the provider from
+// the day from onwards.
+func (r *CodeHostRepository) ReplaceContributions(userID uint, provider model.IntegrationProvider, from time.Time, contributions []model.CodeHostContribution) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ? AND provider = ? AND date >= ?", userID, provider, from).
+			Delete(&model.CodeHostContribution{}).Error; err != nil {
+			return err
+		}
+		if len(contributions) == 0 {
+			return nil
+		}
+		return tx.Create(&contributions).Error
+	})
+}
+
+// DeleteUserData removes the user's account on the provider and everything
+// synced from it.
+func (r *CodeHostRepository) DeleteUserData(userID uint, provider model.IntegrationProvider) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, m := range []interface{}{
+			&model.CodeHostContribution{},
+			&model.CodeHostLanguageStat{},
+			&model.CodeHostRepository{},
+			&model.CodeHostAccount{},
+		} {
+			if err := tx.Where("user_id = ? AND provider = ?", userID, provider).Delete(m).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/norman6464/devsync/backend/internal/model"
+	"gorm.io/gorm"
+)
+
+// ContributionRepository reads contribution counts merged across GitHub and
+// connected code hosts.
+type ContributionRepository struct {
+	db *gorm.DB
+}
+
+func NewContributionRepository(db *gorm.DB) *ContributionRepository {
+	return &ContributionRepository{db: db}
+}
+
+// GetContributions returns the user's daily contributions from from up to and
+// including to.
+func (r *ContributionRepository) GetContributions(userID uint, from, to time.Time) ([]model.Contribution, error) {
+	var contributions []model.Contribution
+	err := r.db.Where("user_id = ? AND date >= ? AND date <= ?", userID, from, to).Order("date ASC").Find(&contributions).Error
+	return contributions, err
+}
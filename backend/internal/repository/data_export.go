@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/norman6464/devsync/backend/internal/model"
+	"gorm.io/gorm"
+)
+
+type DataExportRepository struct {
+	db *gorm.DB
+}
+
+func NewDataExportRepository(db *gorm.DB) *DataExportRepository {
+	return &DataExportRepository{db: db}
+}
+
+func (r *DataExportRepository) Create(export *model.DataExport) error {
+	return r.db.Create(export).Error
+}
+
+func (r *DataExportRepository) Update(export *model.DataExport) error {
+	return r.db.Save(export).Error
+}
+
+func (r *DataExportRepository) FindByID(id uint) (*model.DataExport, error) {
+	var export model.DataExport
+	if err := r.db.First(&export, id).Error; err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+func (r *DataExportRepository) FindByToken(token string) (*model.DataExport, error) {
+	var export model.DataExport
+	if err := r.db.Where("token = ?", token).First(&export).Error; err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+func (r *DataExportRepository) FindByUserID(userID uint) ([]model.DataExport, error) {
+	var exports []model.DataExport
+	err := r.db.Where("user_id = ?", userID).Order("created_at DESC").Limit(10).Find(&exports).Error
+	return exports, err
+}
+
+// FindActiveByUserID returns the user's export that is still queued or running, if any
+func (r *DataExportRepository) FindActiveByUserID(userID uint) (*model.DataExport, error) {
+	var export model.DataExport
+	err := r.db.Where("user_id = ? AND status IN ?", userID,
+		[]model.DataExportStatus{model.DataExportStatusPending, model.DataExportStatusProcessing}).
+		First(&export).Error
+	if err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+// FindExpired returns completed exports whose download link has lapsed
+func (r *DataExportRepository) FindExpired(now time.Time) ([]model.DataExport, error) {
+	var exports []model.DataExport
+	err := r.db.Where("status = ? AND expires_at < ?", model.DataExportStatusCompleted, now).Find(&exports).Error
+	return exports, err
+}
+
+func (r *DataExportRepository) DeleteByUserID(userID uint) error {
+	return r.db.Where("user_id = ?", userID).Delete(&model.DataExport{}).Error
+}
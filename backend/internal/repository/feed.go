@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"github.com/norman6464/devsync/backend/internal/model"
+	"gorm.io/gorm"
+)
+
+// FeedRepository stores users' article feeds. Their entries are stored by
+// ArticleRepository.
+type FeedRepository struct {
+	db *gorm.DB
+}
+
+func NewFeedRepository(db *gorm.DB) *FeedRepository {
+	return &FeedRepository{db: db}
+}
+
+func (r *FeedRepository) FindByUserID(userID uint) ([]model.ArticleFeed, error) {
+	var feeds []model.ArticleFeed
+	err := r.db.Where("user_id = ?", userID).Order("created_at ASC").Find(&feeds).Error
+	return feeds, err
+}
+
+func (r *FeedRepository) FindByID(id uint) (*model.ArticleFeed, error) {
+	var feed model.ArticleFeed
+	if err := r.db.First(&feed, id).Error; err != nil {
+		return nil, err
+	}
+	return &feed, nil
+}
+
+func (r *FeedRepository) CountByUserID(userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.ArticleFeed{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+func (r *FeedRepository) Create(feed *model.ArticleFeed) error {
+	return r.db.Create(feed).Error
+}
+
+// SaveFetchResult stores the feed's metadata and validators after a fetch.
+func (r *FeedRepository) SaveFetchResult(feed *model.ArticleFeed) error {
+	return r.db.Model(feed).Select("title", "site_url", "format", "e_tag", "last_modified", "last_fetched_at", "last_error").
+		Updates(feed).Error
+}
+
+// Delete removes a feed and its entries
+func (r *FeedRepository) Delete(feed *model.ArticleFeed) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("feed_id = ?", feed.ID).Delete(&model.ExternalArticle{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(feed).Error
+	})
+}
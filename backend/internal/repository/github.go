@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/norman6464/devsync/backend/internal/model"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
@@ -24,20 +26,33 @@ func (r *GitHubRepository) UpsertContributions(contributions []model.GitHubContr
 	}).Create(&contributions).Error
 }
 
-func (r *GitHubRepository) GetContributions(userID uint) ([]model.GitHubContribution, error) {
+// GetContributions returns the user's daily contributions from from up to and
+// including to.
+func (r *GitHubRepository) GetContributions(userID uint, from, to time.Time) ([]model.GitHubContribution, error) {
 	var contributions []model.GitHubContribution
-	err := r.db.Where("user_id = ?", userID).Order("date ASC").Find(&contributions).Error
+	err := r.db.Where("user_id = ? AND date >= ? AND date <= ?", userID, from, to).Order("date ASC").Find(&contributions).Error
 	return contributions, err
 }
 
-func (r *GitHubRepository) UpsertLanguageStats(stats []model.GitHubLanguageStat) error {
-	if len(stats) == 0 {
-		return nil
-	}
+func (r *GitHubRepository) UpsertContributionYear(year *model.GitHubContributionYear) error {
 	return r.db.Clauses(clause.OnConflict{
-		Columns:   []clause.Column{{Name: "user_id"}, {Name: "language"}},
-		DoUpdates: clause.AssignmentColumns([]string{"bytes", "repo_count", "updated_at"}),
-	}).Create(&stats).Error
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "year"}},
+		DoUpdates: clause.AssignmentColumns([]string{"total", "commits", "issues", "pull_requests", "reviews", "restricted", "includes_private", "updated_at"}),
+	}).Create(year).Error
+}
+
+// GetContributionYears returns the user's yearly summaries, latest first.
+func (r *GitHubRepository) GetContributionYears(userID uint) ([]model.GitHubContributionYear, error) {
+	var years []model.GitHubContributionYear
+	err := r.db.Where("user_id = ?", userID).Order("year DESC").Find(&years).Error
+	return years, err
+}
+
+// FindSyncedYears returns the years the user has a summary for.
+func (r *GitHubRepository) FindSyncedYears(userID uint) ([]int, error) {
+	var years []int
+	err := r.db.Model(&model.GitHubContributionYear{}).Where("user_id = ?", userID).Pluck("year", &years).Error
+	return years, err
 }
 
 func (r *GitHubRepository) GetLanguageStats(userID uint) ([]model.GitHubLanguageStat, error) {
@@ -62,9 +77,123 @@ func (r *GitHubRepository) GetRepos(userID uint) ([]model.GitHubRepository, erro
 	return repos, err
 }
 
+// DeleteReposExcept removes the user's repositories that are no longer on
+// GitHub, keeping those in githubRepoIDs.
+func (r *GitHubRepository) DeleteReposExcept(userID uint, githubRepoIDs []int64) error {
+	query := r.db.Where("user_id = ?", userID)
+	if len(githubRepoIDs) > 0 {
+		query = query.Where("git_hub_repo_id NOT IN ?", githubRepoIDs)
+	}
+	return query.Delete(&model.GitHubRepository{}).Error
+}
+
+// FindReposForLanguageSync returns the user's repositories whose languages
+// were fetched longest ago, never-fetched ones first.
+func (r *GitHubRepository) FindReposForLanguageSync(userID uint, limit int) ([]model.GitHubRepository, error) {
+	var repos []model.GitHubRepository
+	err := r.db.Where("user_id = ?", userID).
+		Order("languages_synced_at ASC NULLS FIRST, id ASC").
+		Limit(limit).
+		Find(&repos).Error
+	return repos, err
+}
+
+// SaveRepoLanguages replaces a repository's language bytes and records the
+// ETag they were fetched with.
+func (r *GitHubRepository) SaveRepoLanguages(repoID uint, etag string, languages map[string]int64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("repository_id = ?", repoID).Delete(&model.GitHubRepoLanguage{}).Error; err != nil {
+			return err
+		}
+		rows := make([]model.GitHubRepoLanguage, 0, len(languages))
+		for lang, bytes := range languages {
+			rows = append(rows, model.GitHubRepoLanguage{RepositoryID: repoID, Language: lang, Bytes: bytes})
+		}
+		if len(rows) > 0 {
+			if err := tx.Create(&rows).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&model.GitHubRepository{}).Where("id = ?", repoID).
+			Updates(map[string]interface{}{"languages_e_tag": etag, "languages_synced_at": time.Now()}).Error
+	})
+}
+
+// TouchRepoLanguages marks a repository's languages as checked and unchanged.
+func (r *GitHubRepository) TouchRepoLanguages(repoID uint) error {
+	return r.db.Model(&model.GitHubRepository{}).Where("id = ?", repoID).
+		Update("languages_synced_at", time.Now()).Error
+}
+
+// RecomputeLanguageStats rebuilds the user's language stats from the bytes
+// stored per repository, counting each repository under its primary language.
+func (r *GitHubRepository) RecomputeLanguageStats(userID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var stats []model.GitHubLanguageStat
+		err := tx.Raw(`
+			SELECT ? AS user_id, language, SUM(bytes)::bigint AS bytes, SUM(repo_count)::bigint AS repo_count, NOW() AS updated_at
+			FROM (
+				SELECT l.language, l.bytes, 0 AS repo_count
+				FROM git_hub_repo_languages l
+				JOIN git_hub_repositories r ON r.id = l.repository_id
+				WHERE r.user_id = ?
+				UNION ALL
+				SELECT language, 0, 1
+				FROM git_hub_repositories
+				WHERE user_id = ? AND language <> ''
+			) t
+			GROUP BY language`, userID, userID, userID).Scan(&stats).Error
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Where("user_id = ?", userID).Delete(&model.GitHubLanguageStat{}).Error; err != nil {
+			return err
+		}
+		if len(stats) == 0 {
+			return nil
+		}
+		return tx.Create(&stats).Error
+	})
+}
+
 func (r *GitHubRepository) DeleteUserData(userID uint) error {
+	r.db.Where("repository_id IN (?)", r.db.Model(&model.GitHubRepository{}).Select("id").Where("user_id = ?", userID)).
+		Delete(&model.GitHubRepoLanguage{})
 	r.db.Where("user_id = ?", userID).Delete(&model.GitHubContribution{})
+	r.db.Where("user_id = ?", userID).Delete(&model.GitHubContributionYear{})
 	r.db.Where("user_id = ?", userID).Delete(&model.GitHubLanguageStat{})
 	r.db.Where("user_id = ?", userID).Delete(&model.GitHubRepository{})
+	r.db.Where("user_id = ?", userID).Delete(&model.GitHubActivity{})
+	r.db.Where("user_id = ?", userID).Delete(&model.GitHubPullRequest{})
+	r.db.Where("user_id = ?", userID).Delete(&model.GitHubIssue{})
+	r.db.Where("user_id = ?", userID).Delete(&model.GitHubReview{})
 	return nil
 }
+
+func (r *GitHubRepository) FindRepoByGitHubID(githubRepoID int64) (*model.GitHubRepository, error) {
+	var repo model.GitHubRepository
+	if err := r.db.Where("git_hub_repo_id = ?", githubRepoID).First(&repo).Error; err != nil {
+		return nil, err
+	}
+	return &repo, nil
+}
+
+// UpdateRepoCounts sets a repository's star and fork counts
+func (r *GitHubRepository) UpdateRepoCounts(githubRepoID int64, stars, forks int) error {
+	return r.db.Model(&model.GitHubRepository{}).
+		Where("git_hub_repo_id = ?", githubRepoID).
+		Updates(map[string]interface{}{"stars": stars, "forks": forks}).Error
+}
+
+// incrementContribution adds n to the user's contribution count for a day
+func incrementContribution(db *gorm.DB, userID uint, date time.Time, n int) error {
+	contribution := model.GitHubContribution{UserID: userID, Date: date, Count: n}
+	return db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}, {Name: "date"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"count":      gorm.Expr("git_hub_contributions.count + ?", n),
+			"updated_at": gorm.Expr("NOW()"),
+		}),
+	}).Create(&contribution).Error
+}
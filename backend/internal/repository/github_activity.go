@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/norman6464/devsync/backend/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type GitHubActivityRepository struct {
+	db *gorm.DB
+}
+
+func NewGitHubActivityRepository(db *gorm.DB) *GitHubActivityRepository {
+	return &GitHubActivityRepository{db: db}
+}
+
+// CreateWithContributions stores an activity and adds the contributions it
+// made on its day in one transaction, so a delivery is never counted twice
+// nor recorded without being counted. It reports false if the delivery was
+// already recorded.
+func (r *GitHubActivityRepository) CreateWithContributions(activity *model.GitHubActivity, contributions int) (bool, error) {
+	created := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(activity)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		created = true
+		if contributions <= 0 {
+			return nil
+		}
+		day := activity.OccurredAt.UTC().Truncate(24 * time.Hour)
+		return incrementContribution(tx, activity.UserID, day, contributions)
+	})
+	return created && err == nil, err
+}
+
+// FindByUserID returns a user's most recent activity, newest first
+func (r *GitHubActivityRepository) FindByUserID(userID uint, includePrivate bool, page, limit int) ([]model.GitHubActivity, error) {
+	var activities []model.GitHubActivity
+	query := r.db.Where("user_id = ?", userID)
+	if !includePrivate {
+		query = query.Where("is_private = ?", false)
+	}
+	err := query.Order("occurred_at DESC").
+		Offset((page - 1) * limit).Limit(limit).
+		Find(&activities).Error
+	return activities, err
+}
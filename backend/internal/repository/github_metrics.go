@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/norman6464/devsync/backend/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (r *GitHubRepository) UpsertPullRequests(prs []model.GitHubPullRequest) error {
+	if len(prs) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "git_hub_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"repo_full_name", "title", "url", "state", "is_private",
+			"additions", "deletions", "merged_at", "closed_at", "git_hub_updated_at"}),
+	}).Create(&prs).Error
+}
+
+func (r *GitHubRepository) UpsertIssues(issues []model.GitHubIssue) error {
+	if len(issues) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "git_hub_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"repo_full_name", "title", "url", "state", "is_private", "closed_at", "git_hub_updated_at"}),
+	}).Create(&issues).Error
+}
+
+func (r *GitHubRepository) UpsertReviews(reviews []model.GitHubReview) error {
+	if len(reviews) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "git_hub_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"repo_full_name", "state", "url", "is_private"}),
+	}).Create(&reviews).Error
+}
+
+// LatestPullRequestUpdate returns when the user's most recently changed pull
+// request was updated, or nil if none are stored.
+func (r *GitHubRepository) LatestPullRequestUpdate(userID uint) (*time.Time, error) {
+	return latest(r.db.Model(&model.GitHubPullRequest{}), userID, "git_hub_updated_at")
+}
+
+func (r *GitHubRepository) LatestIssueUpdate(userID uint) (*time.Time, error) {
+	return latest(r.db.Model(&model.GitHubIssue{}), userID, "git_hub_updated_at")
+}
+
+func (r *GitHubRepository) LatestReviewSubmitted(userID uint) (*time.Time, error) {
+	return latest(r.db.Model(&model.GitHubReview{}), userID, "submitted_at")
+}
+
+func latest(query *gorm.DB, userID uint, column string) (*time.Time, error) {
+	var row struct {
+		Latest *time.Time
+	}
+	err := query.Where("user_id = ?", userID).Select("MAX(" + column + ") AS latest").Scan(&row).Error
+	return row.Latest, err
+}
+
+// GetMetricsBreakdown returns the user's metrics for the last count weekly
+// or monthly periods, oldest first. Weeks start on Sunday, as in activity
+// reports.
+func (r *GitHubRepository) GetMetricsBreakdown(userID uint, period model.ReportPeriod, count int) ([]model.GitHubMetricsPeriod, error) {
+	now := time.Now()
+	var start time.Time
+	step := func(t time.Time) time.Time { return t.AddDate(0, 0, 7) }
+	if period == model.ReportPeriodMonthly {
+		start = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()).AddDate(0, -(count - 1), 0)
+		step = func(t time.Time) time.Time { return t.AddDate(0, 1, 0) }
+	} else {
+		start = now.AddDate(0, 0, -int(now.Weekday())-7*(count-1))
+		start = time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, now.Location())
+	}
+
+	bounds := []time.Time{start}
+	for i := 0; i < count; i++ {
+		bounds = append(bounds, step(bounds[i]))
+	}
+	metrics, err := githubMetrics(r.db, userID, bounds)
+	if err != nil {
+		return nil, err
+	}
+
+	periods := make([]model.GitHubMetricsPeriod, count)
+	for i := range periods {
+		periods[i] = model.GitHubMetricsPeriod{StartDate: bounds[i], EndDate: bounds[i+1], GitHubMetrics: metrics[i]}
+	}
+	return periods, nil
+}
+
+// githubMetrics counts the user's pull requests, reviews and issues in each
+// of the consecutive periods [bounds[i], bounds[i+1]).
+func githubMetrics(db *gorm.DB, userID uint, bounds []time.Time) ([]model.GitHubMetrics, error) {
+	metrics := make([]model.GitHubMetrics, len(bounds)-1)
+	from, to := bounds[0], bounds[len(bounds)-1]
+	// period returns the index of the period t falls in, or -1
+	period := func(t *time.Time) int {
+		if t == nil || t.Before(from) || !t.Before(to) {
+			return -1
+		}
+		for i := 1; i < len(bounds); i++ {
+			if t.Before(bounds[i]) {
+				return i - 1
+			}
+		}
+		return -1
+	}
+
+	var prs []model.GitHubPullRequest
+	err := db.Select("opened_at, merged_at, closed_at, additions, deletions").
+		Where("user_id = ?", userID).
+		Where("(opened_at >= ? AND opened_at < ?) OR (closed_at >= ? AND closed_at < ?)", from, to, from, to).
+		Find(&prs).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, pr := range prs {
+		if i := period(&pr.OpenedAt); i >= 0 {
+			metrics[i].PullRequestsOpened++
+		}
+		if i := period(pr.MergedAt); i >= 0 {
+			metrics[i].PullRequestsMerged++
+			metrics[i].Additions += pr.Additions
+			metrics[i].Deletions += pr.Deletions
+		} else if i := period(pr.ClosedAt); i >= 0 && pr.MergedAt == nil {
+			metrics[i].PullRequestsClosed++
+		}
+	}
+
+	var issues []model.GitHubIssue
+	err = db.Select("opened_at, closed_at").
+		Where("user_id = ?", userID).
+		Where("(opened_at >= ? AND opened_at < ?) OR (closed_at >= ? AND closed_at < ?)", from, to, from, to).
+		Find(&issues).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, issue := range issues {
+		if i := period(&issue.OpenedAt); i >= 0 {
+			metrics[i].IssuesOpened++
+		}
+		if i := period(issue.ClosedAt); i >= 0 {
+			metrics[i].IssuesClosed++
+		}
+	}
+
+	var reviews []model.GitHubReview
+	err = db.Select("submitted_at").
+		Where("user_id = ? AND submitted_at >= ? AND submitted_at < ?", userID, from, to).
+		Find(&reviews).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, review := range reviews {
+		if i := period(&review.SubmittedAt); i >= 0 {
+			metrics[i].ReviewsGiven++
+		}
+	}
+
+	return metrics, nil
+}
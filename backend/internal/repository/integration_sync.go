@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/norman6464/devsync/backend/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// connectedConditions select the users who have connected each provider
+var connectedConditions = map[model.IntegrationProvider]string{
+	model.IntegrationGitHub:    "users.git_hub_connected = true AND users.git_hub_token <> ''",
+	model.IntegrationZenn:      "users.zenn_username <> ''",
+	model.IntegrationQiita:     "users.qiita_username <> ''",
+	model.IntegrationGitLab:    "EXISTS (SELECT 1 FROM code_host_accounts WHERE code_host_accounts.user_id = users.id AND code_host_accounts.provider = 'gitlab')",
+	model.IntegrationBitbucket: "EXISTS (SELECT 1 FROM code_host_accounts WHERE code_host_accounts.user_id = users.id AND code_host_accounts.provider = 'bitbucket')",
+	model.IntegrationFeed:      "EXISTS (SELECT 1 FROM article_feeds WHERE article_feeds.user_id = users.id)",
+}
+
+type IntegrationSyncRepository struct {
+	db *gorm.DB
+}
+
+func NewIntegrationSyncRepository(db *gorm.DB) *IntegrationSyncRepository {
+	return &IntegrationSyncRepository{db: db}
+}
+
+func (r *IntegrationSyncRepository) FindByUserID(userID uint) ([]model.IntegrationSync, error) {
+	var syncs []model.IntegrationSync
+	err := r.db.Where("user_id = ?", userID).Order("provider ASC").Find(&syncs).Error
+	return syncs, err
+}
+
+// FindOrNew returns the sync state of an integration, or a fresh one if it has never synced
+func (r *IntegrationSyncRepository) FindOrNew(userID uint, provider model.IntegrationProvider) (*model.IntegrationSync, error) {
+	sync := model.IntegrationSync{UserID: userID, Provider: provider}
+	err := r.db.Where("user_id = ? AND provider = ?", userID, provider).Limit(1).Find(&sync).Error
+	return &sync, err
+}
+
+// Save inserts or updates the sync state for the user and provider
+func (r *IntegrationSyncRepository) Save(sync *model.IntegrationSync) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "provider"}},
+		DoUpdates: clause.AssignmentColumns([]string{"last_synced_at", "last_sync_error", "last_attempt_at", "consecutive_failures", "next_sync_at", "updated_at"}),
+	}).Create(sync).Error
+}
+
+// SetNextSyncAt records when the integration should next be synced
+func (r *IntegrationSyncRepository) SetNextSyncAt(userID uint, provider model.IntegrationProvider, at time.Time) error {
+	sync := &model.IntegrationSync{UserID: userID, Provider: provider, NextSyncAt: &at}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "provider"}},
+		DoUpdates: clause.AssignmentColumns([]string{"next_sync_at", "updated_at"}),
+	}).Create(sync).Error
+}
+
+func (r *IntegrationSyncRepository) Delete(userID uint, provider model.IntegrationProvider) error {
+	return r.db.Where("user_id = ? AND provider = ?", userID, provider).Delete(&model.IntegrationSync{}).Error
+}
+
+// FindDueUserIDs returns users with the provider connected whose next sync is
+// due, never-synced accounts first
+func (r *IntegrationSyncRepository) FindDueUserIDs(provider model.IntegrationProvider, now time.Time, limit int) ([]uint, error) {
+	var userIDs []uint
+	err := r.db.Table("users").
+		Select("users.id").
+		Joins("LEFT JOIN integration_syncs ON integration_syncs.user_id = users.id AND integration_syncs.provider = ?", provider).
+		Where(connectedConditions[provider]).
+		Where("users.deletion_scheduled_at IS NULL").
+		Where("integration_syncs.next_sync_at IS NULL OR integration_syncs.next_sync_at <= ?", now).
+		Order("integration_syncs.next_sync_at ASC NULLS FIRST").
+		Limit(limit).
+		Scan(&userIDs).Error
+	return userIDs, err
+}
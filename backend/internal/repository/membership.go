@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrAlreadyMember is returned when adding a user to a group they are
+	// already in
+	ErrAlreadyMember = errors.New("already a member")
+	// ErrUnknownMember is returned when adding a user that does not exist
+	ErrUnknownMember = errors.New("user not found")
+)
+
+// memberTable stores the members of groups users join, such as chat rooms
+// and teams. Each row references its group through column and is unique per
+// group and user.
+type memberTable struct {
+	groups string // the group table
+	table  string
+	column string
+}
+
+var (
+	chatRoomMembers = memberTable{groups: "chat_rooms", table: "chat_room_members", column: "chat_room_id"}
+	teamMembers     = memberTable{groups: "teams", table: "team_members", column: "team_id"}
+)
+
+// add makes the users members of the group, all or none of them.
+func (m memberTable) add(db *gorm.DB, groupID uint, userIDs ...uint) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	now := time.Now()
+	rows := make([]map[string]interface{}, len(userIDs))
+	for i, userID := range userIDs {
+		rows[i] = map[string]interface{}{m.column: groupID, "user_id": userID, "joined_at": now}
+	}
+	err := db.Table(m.table).Create(rows).Error
+	switch {
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return ErrAlreadyMember
+	case errors.Is(err, gorm.ErrForeignKeyViolated):
+		return ErrUnknownMember
+	}
+	return err
+}
+
+func (m memberTable) remove(db *gorm.DB, groupID, userID uint) error {
+	return db.Exec("DELETE FROM "+m.table+" WHERE "+m.column+" = ? AND user_id = ?", groupID, userID).Error
+}
+
+func (m memberTable) isMember(db *gorm.DB, groupID, userID uint) (bool, error) {
+	var count int64
+	err := db.Table(m.table).Where(m.column+" = ? AND user_id = ?", groupID, userID).Count(&count).Error
+	return count > 0, err
+}
+
+func (m memberTable) ownerID(db *gorm.DB, groupID uint) (uint, error) {
+	var ownerID uint
+	err := db.Table(m.groups).Select("owner_id").Where("id = ?", groupID).Row().Scan(&ownerID)
+	return ownerID, err
+}
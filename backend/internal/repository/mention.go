@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"github.com/norman6464/devsync/backend/internal/model"
+	"gorm.io/gorm"
+)
+
+type MentionRepository struct {
+	db *gorm.DB
+}
+
+func NewMentionRepository(db *gorm.DB) *MentionRepository {
+	return &MentionRepository{db: db}
+}
+
+func (r *MentionRepository) CreateBatch(mentions []*model.Mention) error {
+	if len(mentions) == 0 {
+		return nil
+	}
+	return r.db.Create(&mentions).Error
+}
+
+// FindUserIDsBySource returns the IDs of users already mentioned in a piece of content
+func (r *MentionRepository) FindUserIDsBySource(sourceType model.MentionSourceType, sourceID uint) ([]uint, error) {
+	var userIDs []uint
+	err := r.db.Model(&model.Mention{}).
+		Where("source_type = ? AND source_id = ?", sourceType, sourceID).
+		Pluck("user_id", &userIDs).Error
+	return userIDs, err
+}
+
+func (r *MentionRepository) DeleteBySourceUsers(sourceType model.MentionSourceType, sourceID uint, userIDs []uint) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	return r.db.Where("source_type = ? AND source_id = ? AND user_id IN ?", sourceType, sourceID, userIDs).
+		Delete(&model.Mention{}).Error
+}
+
+func (r *MentionRepository) DeleteBySource(sourceType model.MentionSourceType, sourceID uint) error {
+	return r.db.Where("source_type = ? AND source_id = ?", sourceType, sourceID).Delete(&model.Mention{}).Error
+}
+
+// FindByUserID returns the mentions of the given user, newest first
+func (r *MentionRepository) FindByUserID(userID uint, page, limit int) ([]model.Mention, error) {
+	var mentions []model.Mention
+	offset := (page - 1) * limit
+	err := r.db.Preload("Actor").Preload("Post").Preload("Question").
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Offset(offset).Limit(limit).
+		Find(&mentions).Error
+	return mentions, err
+}
+
+func (r *MentionRepository) CountByUserID(userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.Mention{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
@@ -82,3 +82,12 @@ func (r *NotificationRepository) GetFollowerIDs(userID uint) ([]uint, error) {
 		Pluck("follower_id", &followerIDs).Error
 	return followerIDs, err
 }
+
+// FindUserIDsByPost returns the users already notified of the given type about a post
+func (r *NotificationRepository) FindUserIDsByPost(notificationType model.NotificationType, postID uint) ([]uint, error) {
+	var userIDs []uint
+	err := r.db.Model(&model.Notification{}).
+		Where("type = ? AND post_id = ?", notificationType, postID).
+		Pluck("user_id", &userIDs).Error
+	return userIDs, err
+}
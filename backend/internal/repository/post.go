@@ -51,8 +51,15 @@ func (r *PostRepository) Update(post *model.Post) error {
 	return r.db.Save(post).Error
 }
 
+// Delete removes the post along with the mentions in it and its comments,
+// which reference it.
 func (r *PostRepository) Delete(id uint) error {
-	return r.db.Delete(&model.Post{}, id).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("post_id = ?", id).Delete(&model.Mention{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.Post{}, id).Error
+	})
 }
 
 // Like
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/norman6464/devsync/backend/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PrivacyRepository struct {
+	db *gorm.DB
+}
+
+func NewPrivacyRepository(db *gorm.DB) *PrivacyRepository {
+	return &PrivacyRepository{db: db}
+}
+
+// FindByUserID returns the user's privacy settings, or the defaults if none were saved
+func (r *PrivacyRepository) FindByUserID(userID uint) (*model.PrivacySettings, error) {
+	var settings model.PrivacySettings
+	err := r.db.Where("user_id = ?", userID).First(&settings).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.DefaultPrivacySettings(userID), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+func (r *PrivacyRepository) Upsert(settings *model.PrivacySettings) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"profile_public", "show_badges", "show_contributions", "show_languages",
+			"show_projects", "show_articles", "show_roadmaps", "updated_at",
+		}),
+	}).Create(settings).Error
+}
+
+func (r *PrivacyRepository) DeleteByUserID(userID uint) error {
+	return r.db.Where("user_id = ?", userID).Delete(&model.PrivacySettings{}).Error
+}
@@ -1,6 +1,13 @@
 package repository
 
-import "gorm.io/gorm"
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/norman6464/devsync/backend/internal/model"
+	"gorm.io/gorm"
+)
 
 type RankingRepository struct {
 	db *gorm.DB
@@ -10,48 +17,299 @@ func NewRankingRepository(db *gorm.DB) *RankingRepository {
 	return &RankingRepository{db: db}
 }
 
-type RankingEntry struct {
-	UserID    uint   `json:"user_id"`
-	Name      string `json:"name"`
-	AvatarURL string `json:"avatar_url"`
-	Score     int64  `json:"score"`
+// rankingMetrics holds the query behind each metric. Each selects user_id
+// and score for activity at or after @since.
+var rankingMetrics = map[string]string{
+	model.RankingMetricContributions: `
+		SELECT user_id, SUM(count) AS score FROM contributions
+		WHERE date >= @since GROUP BY user_id`,
+	model.RankingMetricPosts: `
+		SELECT user_id, COUNT(*) AS score FROM posts
+		WHERE created_at >= @since GROUP BY user_id`,
+	model.RankingMetricLikesReceived: `
+		SELECT p.user_id, COUNT(*) AS score FROM likes l
+		JOIN posts p ON p.id = l.post_id
+		WHERE l.created_at >= @since AND l.user_id <> p.user_id GROUP BY p.user_id`,
+	model.RankingMetricAcceptedAnswers: `
+		SELECT user_id, COUNT(*) AS score FROM answers
+		WHERE is_best AND deleted_at IS NULL AND created_at >= @since GROUP BY user_id`,
+	model.RankingMetricGoalsCompleted: `
+		SELECT user_id, COUNT(*) AS score FROM learning_goals
+		WHERE status = 'completed' AND completed_at >= @since GROUP BY user_id`,
+	model.RankingMetricRoadmapsCompleted: `
+		SELECT user_id, COUNT(*) AS score FROM roadmaps
+		WHERE status = 'completed' AND completed_at >= @since GROUP BY user_id`,
+	// Likes gained since the start of the period: measured from each
+	// article's last snapshot before it, or from zero for articles published
+	// since
+	model.RankingMetricArticleLikes: `
+		SELECT a.user_id, SUM(a.likes - COALESCE(s.likes, 0)) AS score
+		FROM external_articles a
+		LEFT JOIN LATERAL (
+			SELECT likes FROM article_engagement_snapshots
+			WHERE article_id = a.id AND date <= @since
+			ORDER BY date DESC LIMIT 1
+		) s ON true
+		WHERE NOT a.private AND (s.likes IS NOT NULL OR a.published_at >= @since)
+		GROUP BY a.user_id`,
 }
 
-func (r *RankingRepository) ContributionRanking(period string) ([]RankingEntry, error) {
-	interval := "7 days"
-	if period == "monthly" {
-		interval = "30 days"
+// RankingMetrics returns the names of the metrics users can be ranked by.
+func RankingMetrics() []string {
+	names := make([]string, 0, len(rankingMetrics))
+	for name := range rankingMetrics {
+		names = append(names, name)
 	}
+	sort.Strings(names)
+	return names
+}
 
-	var entries []RankingEntry
-	err := r.db.Raw(`
-		SELECT u.id as user_id, u.name, u.avatar_url, COALESCE(SUM(gc.count), 0) as score
-		FROM users u
-		JOIN git_hub_contributions gc ON gc.user_id = u.id
-		WHERE gc.date >= NOW() - INTERVAL '`+interval+`'
-		GROUP BY u.id
-		HAVING SUM(gc.count) > 0
-		ORDER BY score DESC
-		LIMIT 50
-	`).Scan(&entries).Error
-	return entries, err
-}
-
-func (r *RankingRepository) LanguageRanking(language, period string) ([]RankingEntry, error) {
-	var entries []RankingEntry
+// MetricScores returns each user's score on metric for activity since the
+// given time, for the users in scope.
+func (r *RankingRepository) MetricScores(metric string, since time.Time, scope model.RankingScope) (map[uint]int64, error) {
+	return r.scores(rankingMetrics[metric], map[string]interface{}{"since": since}, scope)
+}
+
+// LanguageScores scores users on a language. Over all time that is the bytes
+// they have written in it. For a period, when only current byte counts are
+// known, it is their contributions in the period weighted by the language's
+// share of their code.
+func (r *RankingRepository) LanguageScores(language string, since time.Time, scope model.RankingScope) (map[uint]int64, error) {
+	args := map[string]interface{}{"since": since, "language": language}
+	if since.IsZero() {
+		return r.scores(`SELECT user_id, bytes AS score FROM language_stats WHERE language = @language`, args, scope)
+	}
+	return r.scores(`
+		SELECT l.user_id, (c.total * l.bytes / t.bytes)::bigint AS score
+		FROM language_stats l
+		JOIN (SELECT user_id, SUM(bytes) AS bytes FROM language_stats GROUP BY user_id) t
+			ON t.user_id = l.user_id AND t.bytes > 0
+		JOIN (SELECT user_id, SUM(count) AS total FROM contributions WHERE date >= @since GROUP BY user_id) c
+			ON c.user_id = l.user_id
+		WHERE l.language = @language`, args, scope)
+}
+
+// scores runs a metric query, keeping the users in scope whose accounts
+// aren't being deleted.
+func (r *RankingRepository) scores(query string, args map[string]interface{}, scope model.RankingScope) (map[uint]int64, error) {
+	sql := `SELECT m.user_id, m.score FROM (` + query + `) m
+		JOIN users u ON u.id = m.user_id AND u.deletion_scheduled_at IS NULL
+		WHERE m.score > 0` + scopeFilter("m.user_id", scope, args)
+
+	var rows []struct {
+		UserID uint
+		Score  int64
+	}
+	if err := r.db.Raw(sql, args).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	scores := make(map[uint]int64, len(rows))
+	for _, row := range rows {
+		scores[row.UserID] = row.Score
+	}
+	return scores, nil
+}
+
+// scopeFilter returns the conditions limiting column to the users in scope,
+// adding their arguments to args.
+func scopeFilter(column string, scope model.RankingScope, args map[string]interface{}) string {
+	var sql string
+	if scope.FollowerID != 0 {
+		sql += ` AND (` + column + ` = @follower OR ` + column + ` IN (SELECT followee_id FROM follows WHERE follower_id = @follower))`
+		args["follower"] = scope.FollowerID
+	}
+	if scope.ChatRoomID != 0 {
+		sql += ` AND ` + column + ` IN (SELECT user_id FROM chat_room_members WHERE chat_room_id = @room)`
+		args["room"] = scope.ChatRoomID
+	}
+	if scope.TeamID != 0 {
+		sql += ` AND ` + column + ` IN (SELECT user_id FROM team_members WHERE team_id = @team)`
+		args["team"] = scope.TeamID
+	}
+	return sql
+}
+
+// SaveSnapshot stores the day's leaderboard for snapshot's metric and period,
+// replacing one already taken that day. It reports whether the day had no
+// snapshot before.
+func (r *RankingRepository) SaveSnapshot(snapshot *model.LeaderboardSnapshot, entries []model.RankingEntry) (bool, error) {
+	created := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var existing model.LeaderboardSnapshot
+		err := tx.Where("metric = ? AND period = ? AND date = ?", snapshot.Metric, snapshot.Period, snapshot.Date).
+			First(&existing).Error
+		switch {
+		case err == nil:
+			snapshot.ID = existing.ID
+			if err := tx.Where("snapshot_id = ?", existing.ID).Delete(&model.LeaderboardEntry{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Save(snapshot).Error; err != nil {
+				return err
+			}
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			created = true
+			if err := tx.Create(snapshot).Error; err != nil {
+				return err
+			}
+		default:
+			return err
+		}
+
+		if len(entries) == 0 {
+			return nil
+		}
+		rows := make([]model.LeaderboardEntry, len(entries))
+		for i, e := range entries {
+			rows[i] = model.LeaderboardEntry{
+				SnapshotID: snapshot.ID,
+				UserID:     e.UserID,
+				Rank:       e.Rank,
+				Score:      e.Score,
+				Breakdown:  e.Breakdown,
+			}
+		}
+		return tx.CreateInBatches(rows, 1000).Error
+	})
+	return created, err
+}
+
+// LatestSnapshot returns the most recent leaderboard for metric and period.
+func (r *RankingRepository) LatestSnapshot(metric string, period model.RankingPeriod) (*model.LeaderboardSnapshot, error) {
+	var snapshot model.LeaderboardSnapshot
+	err := r.db.Where("metric = ? AND period = ?", metric, period).
+		Order("date DESC").
+		First(&snapshot).Error
+	if err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// SnapshotBefore returns the last leaderboard for metric and period from
+// before the given date.
+func (r *RankingRepository) SnapshotBefore(metric string, period model.RankingPeriod, date time.Time) (*model.LeaderboardSnapshot, error) {
+	var snapshot model.LeaderboardSnapshot
+	err := r.db.Where("metric = ? AND period = ? AND date < ?", metric, period, date).
+		Order("date DESC").
+		First(&snapshot).Error
+	if err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// rankedSnapshot ranks a snapshot's entries among the users in scope whose
+// accounts aren't being deleted. Narrowing the scope only ever removes users,
+// so reranking the stored scores gives the scoped ranking.
+func rankedSnapshot(snapshotID uint, scope model.RankingScope, args map[string]interface{}) string {
+	args["snapshot"] = snapshotID
+	return `SELECT e.user_id, e.score, e.breakdown, RANK() OVER (ORDER BY e.score DESC) AS rank
+		FROM leaderboard_entries e
+		JOIN users u ON u.id = e.user_id AND u.deletion_scheduled_at IS NULL
+		WHERE e.snapshot_id = @snapshot` + scopeFilter("e.user_id", scope, args)
+}
+
+// SnapshotEntries returns the top limit users of a snapshot within scope,
+// best first.
+func (r *RankingRepository) SnapshotEntries(snapshotID uint, scope model.RankingScope, limit int) ([]model.RankingEntry, error) {
+	args := map[string]interface{}{"limit": limit}
+	sql := `SELECT * FROM (` + rankedSnapshot(snapshotID, scope, args) + `) r
+		ORDER BY r.rank, r.user_id LIMIT @limit`
+
+	var rows []model.LeaderboardEntry
+	if err := r.db.Raw(sql, args).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	entries := make([]model.RankingEntry, len(rows))
+	for i, row := range rows {
+		entries[i] = model.RankingEntry{Rank: row.Rank, UserID: row.UserID, Score: row.Score, Breakdown: row.Breakdown}
+	}
+	return entries, nil
+}
+
+// SnapshotPosition returns where the user stands in a snapshot within scope.
+func (r *RankingRepository) SnapshotPosition(snapshotID, userID uint, scope model.RankingScope) (*model.RankingPosition, error) {
+	args := map[string]interface{}{"user": userID}
+	sql := `WITH ranked AS (` + rankedSnapshot(snapshotID, scope, args) + `)
+		SELECT (SELECT COUNT(*) FROM ranked) AS total, COALESCE(r.rank, 0) AS rank, COALESCE(r.score, 0) AS score
+		FROM (SELECT 1) one
+		LEFT JOIN ranked r ON r.user_id = @user`
+
+	var position model.RankingPosition
+	if err := r.db.Raw(sql, args).Scan(&position).Error; err != nil {
+		return nil, err
+	}
+	return &position, nil
+}
+
+// RankChange is a user's move between two snapshots of a leaderboard
+type RankChange struct {
+	UserID uint
+	Rank   int
+	// Change is the number of places gained
+	Change int
+}
+
+// RankGains returns the users who climbed at least minGain places between
+// the previous snapshot and the current one. Users new to the leaderboard
+// aren't included.
+func (r *RankingRepository) RankGains(currentID, previousID uint, minGain int) ([]RankChange, error) {
+	var changes []RankChange
 	err := r.db.Raw(`
-		SELECT u.id as user_id, u.name, u.avatar_url, gls.bytes as score
-		FROM users u
-		JOIN git_hub_language_stats gls ON gls.user_id = u.id
-		WHERE gls.language = ?
-		ORDER BY gls.bytes DESC
-		LIMIT 50
-	`, language).Scan(&entries).Error
-	return entries, err
+		SELECT c.user_id, c.rank, p.rank - c.rank AS change
+		FROM leaderboard_entries c
+		JOIN leaderboard_entries p ON p.user_id = c.user_id AND p.snapshot_id = ?
+		WHERE c.snapshot_id = ? AND p.rank - c.rank >= ?
+	`, previousID, currentID, minGain).Scan(&changes).Error
+	return changes, err
+}
+
+// RankHistory returns the user's daily places on a leaderboard since the
+// given date, oldest first.
+func (r *RankingRepository) RankHistory(userID uint, metric string, period model.RankingPeriod, since time.Time) ([]model.RankHistoryPoint, error) {
+	history := []model.RankHistoryPoint{}
+	err := r.db.Table("leaderboard_entries e").
+		Select("s.date, e.rank, e.score, s.total").
+		Joins("JOIN leaderboard_snapshots s ON s.id = e.snapshot_id").
+		Where("e.user_id = ? AND s.metric = ? AND s.period = ? AND s.date >= ?", userID, metric, period, since).
+		Order("s.date ASC").
+		Scan(&history).Error
+	return history, err
+}
+
+// PruneSnapshots deletes leaderboards from before the given date, with their
+// entries.
+func (r *RankingRepository) PruneSnapshots(before time.Time) error {
+	return r.db.Where("date < ?", before).Delete(&model.LeaderboardSnapshot{}).Error
+}
+
+// FillUsers sets the name and avatar of each entry's user.
+func (r *RankingRepository) FillUsers(entries []model.RankingEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	ids := make([]uint, len(entries))
+	for i, e := range entries {
+		ids[i] = e.UserID
+	}
+	var users []model.User
+	if err := r.db.Select("id", "name", "avatar_url").Where("id IN ?", ids).Find(&users).Error; err != nil {
+		return err
+	}
+	byID := make(map[uint]model.User, len(users))
+	for _, u := range users {
+		byID[u.ID] = u
+	}
+	for i := range entries {
+		entries[i].Name = byID[entries[i].UserID].Name
+		entries[i].AvatarURL = byID[entries[i].UserID].AvatarURL
+	}
+	return nil
 }
 
 func (r *RankingRepository) AvailableLanguages() ([]string, error) {
 	var languages []string
-	err := r.db.Raw(`SELECT DISTINCT language FROM git_hub_language_stats ORDER BY language`).Scan(&languages).Error
+	err := r.db.Raw(`SELECT DISTINCT language FROM language_stats ORDER BY language`).Scan(&languages).Error
 	return languages, err
 }
@@ -53,6 +53,15 @@ func (r *RoadmapRepository) GetByUserID(userID uint) ([]model.Roadmap, error) {
 	return roadmaps, err
 }
 
+// GetCompletedPublicByUserID gets a user's completed roadmaps that are public
+func (r *RoadmapRepository) GetCompletedPublicByUserID(userID uint) ([]model.Roadmap, error) {
+	var roadmaps []model.Roadmap
+	err := r.db.Where("user_id = ? AND status = ? AND is_public = ?", userID, model.RoadmapStatusCompleted, true).
+		Order("completed_at DESC").
+		Find(&roadmaps).Error
+	return roadmaps, err
+}
+
 // GetPublicRoadmaps gets all public roadmaps with pagination
 func (r *RoadmapRepository) GetPublicRoadmaps(limit, offset int) ([]model.Roadmap, int64, error) {
 	var roadmaps []model.Roadmap
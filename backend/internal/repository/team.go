@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"github.com/norman6464/devsync/backend/internal/model"
+	"gorm.io/gorm"
+)
+
+type TeamRepository struct {
+	db *gorm.DB
+}
+
+func NewTeamRepository(db *gorm.DB) *TeamRepository {
+	return &TeamRepository{db: db}
+}
+
+// Create stores the team with its owner and the given users as members
+func (r *TeamRepository) Create(team *model.Team, memberIDs []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(team).Error; err != nil {
+			return err
+		}
+		return teamMembers.add(tx, team.ID, append([]uint{team.OwnerID}, memberIDs...)...)
+	})
+}
+
+func (r *TeamRepository) FindOwnerID(teamID uint) (uint, error) {
+	return teamMembers.ownerID(r.db, teamID)
+}
+
+func (r *TeamRepository) FindByID(id uint) (*model.Team, error) {
+	var team model.Team
+	err := r.db.Preload("Owner").First(&team, id).Error
+	return &team, err
+}
+
+func (r *TeamRepository) FindByUserID(userID uint) ([]model.Team, error) {
+	var teams []model.Team
+	err := r.db.Joins("JOIN team_members ON team_members.team_id = teams.id").
+		Where("team_members.user_id = ?", userID).
+		Preload("Owner").
+		Order("teams.name ASC").
+		Find(&teams).Error
+	return teams, err
+}
+
+func (r *TeamRepository) Update(team *model.Team) error {
+	return r.db.Save(team).Error
+}
+
+func (r *TeamRepository) Delete(teamID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("team_id = ?", teamID).Delete(&model.TeamMember{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.Team{}, teamID).Error
+	})
+}
+
+// AddMember returns ErrAlreadyMember if the user is already a member
+func (r *TeamRepository) AddMember(teamID, userID uint) error {
+	return teamMembers.add(r.db, teamID, userID)
+}
+
+func (r *TeamRepository) RemoveMember(teamID, userID uint) error {
+	return teamMembers.remove(r.db, teamID, userID)
+}
+
+func (r *TeamRepository) GetMembers(teamID uint) ([]model.TeamMember, error) {
+	var members []model.TeamMember
+	err := r.db.Preload("User").Where("team_id = ?", teamID).Order("joined_at ASC").Find(&members).Error
+	return members, err
+}
+
+func (r *TeamRepository) IsMember(teamID, userID uint) (bool, error) {
+	return teamMembers.isMember(r.db, teamID, userID)
+}
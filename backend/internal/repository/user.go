@@ -1,10 +1,19 @@
 package repository
 
 import (
+	"errors"
+	"strings"
+	"time"
+
 	"github.com/norman6464/devsync/backend/internal/model"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// ErrHandleTaken is returned by ChangeHandle when another user claimed the
+// handle first
+var ErrHandleTaken = errors.New("handle is already taken")
+
 type UserRepository struct {
 	db *gorm.DB
 }
@@ -15,7 +24,7 @@ func NewUserRepository(db *gorm.DB) *UserRepository {
 
 func (r *UserRepository) FindAll() ([]model.User, error) {
 	var users []model.User
-	result := r.db.Find(&users)
+	result := r.db.Where("deletion_scheduled_at IS NULL").Find(&users)
 	return users, result.Error
 }
 
@@ -37,91 +46,141 @@ func (r *UserRepository) FindByEmail(email string) (*model.User, error) {
 	return &user, nil
 }
 
+// Search matches users by handle, name or email. Exact and prefix handle
+// matches rank first, followed by name prefix matches.
 func (r *UserRepository) Search(query string) ([]model.User, error) {
 	var users []model.User
-	result := r.db.Where("name ILIKE ? OR email ILIKE ?", "%"+query+"%", "%"+query+"%").Limit(50).Find(&users)
+	handle := strings.ToLower(strings.TrimPrefix(query, "@"))
+	result := r.db.
+		Where("deletion_scheduled_at IS NULL").
+		Where("handle LIKE ? OR name ILIKE ? OR email ILIKE ?", handle+"%", "%"+query+"%", "%"+query+"%").
+		Order(clause.OrderBy{Expression: clause.Expr{
+			SQL:                "CASE WHEN handle = ? THEN 0 WHEN handle LIKE ? THEN 1 WHEN name ILIKE ? THEN 2 ELSE 3 END, LENGTH(handle), name",
+			Vars:               []interface{}{handle, handle + "%", query + "%"},
+			WithoutParentheses: true,
+		}}).
+		Limit(50).Find(&users)
 	return users, result.Error
 }
 
-func (r *UserRepository) FindByGitHubID(githubID int64) (*model.User, error) {
+func (r *UserRepository) FindByHandle(handle string) (*model.User, error) {
 	var user model.User
-	result := r.db.Where("git_hub_id = ?", githubID).First(&user)
+	result := r.db.Where("handle = ?", strings.ToLower(handle)).First(&user)
 	if result.Error != nil {
 		return nil, result.Error
 	}
 	return &user, nil
 }
 
-func (r *UserRepository) Create(user *model.User) error {
-	return r.db.Create(user).Error
+// FindByHandles returns the users owning any of the given lowercase handles
+func (r *UserRepository) FindByHandles(handles []string) ([]model.User, error) {
+	var users []model.User
+	if len(handles) == 0 {
+		return users, nil
+	}
+	result := r.db.Where("handle IN ? AND deletion_scheduled_at IS NULL", handles).Find(&users)
+	return users, result.Error
 }
 
-func (r *UserRepository) Update(user *model.User) error {
-	return r.db.Save(user).Error
+// HandleTaken reports whether a handle is in use by another user, or still
+// reserved for another user who recently renamed away from it
+func (r *UserRepository) HandleTaken(handle string, excludeUserID uint) (bool, error) {
+	var count int64
+	if err := r.db.Model(&model.User{}).Where("handle = ? AND id <> ?", handle, excludeUserID).Count(&count).Error; err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return true, nil
+	}
+	err := r.db.Model(&model.HandleHistory{}).
+		Where("handle = ? AND user_id <> ? AND expires_at > ?", handle, excludeUserID, time.Now()).
+		Count(&count).Error
+	return count > 0, err
 }
 
-func (r *UserRepository) Delete(id uint) error {
-	return r.db.Delete(&model.User{}, id).Error
+// FindHandleRedirect returns the unexpired rename record for an old handle
+func (r *UserRepository) FindHandleRedirect(handle string) (*model.HandleHistory, error) {
+	var history model.HandleHistory
+	result := r.db.Where("handle = ? AND expires_at > ?", strings.ToLower(handle), time.Now()).
+		Order("changed_at DESC").First(&history)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &history, nil
 }
 
-// DeleteWithRelatedData deletes a user and all their related data
-func (r *UserRepository) DeleteWithRelatedData(id uint) error {
+// ChangeHandle sets a new handle and records the previous one in the rename
+// history so it keeps redirecting until expiresAt. It returns ErrHandleTaken
+// if another user holds the handle.
+func (r *UserRepository) ChangeHandle(user *model.User, handle string, expiresAt time.Time) error {
 	return r.db.Transaction(func(tx *gorm.DB) error {
-		// Delete notifications (where user is recipient or actor)
-		if err := tx.Where("user_id = ? OR actor_id = ?", id, id).Delete(&model.Notification{}).Error; err != nil {
-			return err
-		}
-
-		// Delete messages (sent or received)
-		if err := tx.Where("sender_id = ? OR receiver_id = ?", id, id).Delete(&model.Message{}).Error; err != nil {
-			return err
+		if user.Handle != "" {
+			history := &model.HandleHistory{
+				UserID:    user.ID,
+				Handle:    user.Handle,
+				ChangedAt: time.Now(),
+				ExpiresAt: expiresAt,
+			}
+			if err := tx.Create(history).Error; err != nil {
+				return err
+			}
 		}
 
-		// Delete comments
-		if err := tx.Where("user_id = ?", id).Delete(&model.Comment{}).Error; err != nil {
+		// Reclaiming one of your own old handles ends its redirect
+		if err := tx.Where("user_id = ? AND handle = ?", user.ID, handle).Delete(&model.HandleHistory{}).Error; err != nil {
 			return err
 		}
 
-		// Delete likes
-		if err := tx.Where("user_id = ?", id).Delete(&model.Like{}).Error; err != nil {
+		err := tx.Model(&model.User{}).Where("id = ?", user.ID).Update("handle", handle).Error
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			// Claimed by someone else since HandleTaken was checked
+			return ErrHandleTaken
+		} else if err != nil {
 			return err
 		}
+		user.Handle = handle
+		return nil
+	})
+}
 
-		// Delete posts
-		if err := tx.Where("user_id = ?", id).Delete(&model.Post{}).Error; err != nil {
-			return err
-		}
+func (r *UserRepository) GetHandleHistory(userID uint) ([]model.HandleHistory, error) {
+	var history []model.HandleHistory
+	err := r.db.Where("user_id = ?", userID).Order("changed_at DESC").Find(&history).Error
+	return history, err
+}
 
-		// Delete follows (both directions)
-		if err := tx.Where("follower_id = ? OR followee_id = ?", id, id).Delete(&model.Follow{}).Error; err != nil {
-			return err
-		}
+func (r *UserRepository) FindByGitHubID(githubID int64) (*model.User, error) {
+	var user model.User
+	result := r.db.Where("git_hub_id = ?", githubID).First(&user)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &user, nil
+}
 
-		// Delete GitHub data
-		if err := tx.Where("user_id = ?", id).Delete(&model.GitHubContribution{}).Error; err != nil {
-			return err
-		}
-		if err := tx.Where("user_id = ?", id).Delete(&model.GitHubLanguageStat{}).Error; err != nil {
-			return err
-		}
-		if err := tx.Where("user_id = ?", id).Delete(&model.GitHubRepository{}).Error; err != nil {
-			return err
-		}
+func (r *UserRepository) Create(user *model.User) error {
+	return r.db.Create(user).Error
+}
 
-		// Delete password reset tokens
-		if err := tx.Where("user_id = ?", id).Delete(&model.PasswordResetToken{}).Error; err != nil {
-			return err
-		}
+func (r *UserRepository) Update(user *model.User) error {
+	return r.db.Save(user).Error
+}
 
-		// Finally delete the user
-		if err := tx.Delete(&model.User{}, id).Error; err != nil {
-			return err
-		}
+func (r *UserRepository) Delete(id uint) error {
+	return r.db.Delete(&model.User{}, id).Error
+}
 
-		return nil
-	})
+// FindDueForDeletion returns users whose deletion grace period has ended
+func (r *UserRepository) FindDueForDeletion(now time.Time) ([]model.User, error) {
+	var users []model.User
+	result := r.db.Where("deletion_scheduled_at <= ?", now).Find(&users)
+	return users, result.Error
 }
 
 func (r *UserRepository) UpdatePassword(userID uint, hashedPassword string) error {
 	return r.db.Model(&model.User{}).Where("id = ?", userID).Update("password", hashedPassword).Error
 }
+
+func (r *UserRepository) UpdateGitHubCreatedAt(userID uint, createdAt time.Time) error {
+	return r.db.Model(&model.User{}).Where("id = ?", userID).Update("git_hub_created_at", createdAt).Error
+}
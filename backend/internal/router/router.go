@@ -2,18 +2,20 @@ package router
 
 import (
 	"strings"
+	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/norman6464/devsync/backend/internal/config"
 	"github.com/norman6464/devsync/backend/internal/handler"
 	"github.com/norman6464/devsync/backend/internal/middleware"
+	"github.com/norman6464/devsync/backend/internal/queue"
 	"github.com/norman6464/devsync/backend/internal/repository"
 	"github.com/norman6464/devsync/backend/internal/service"
 	"gorm.io/gorm"
 )
 
-func Setup(db *gorm.DB, cfg *config.Config, hub *service.Hub) *gin.Engine {
+func Setup(db *gorm.DB, cfg *config.Config, hub *service.Hub, jobs *queue.Queue) *gin.Engine {
 	r := gin.Default()
 
 	origins := strings.Split(cfg.CORSOrigins, ",")
@@ -33,8 +35,6 @@ func Setup(db *gorm.DB, cfg *config.Config, hub *service.Hub) *gin.Engine {
 	rankingRepo := repository.NewRankingRepository(db)
 	notificationRepo := repository.NewNotificationRepository(db)
 	passwordResetRepo := repository.NewPasswordResetRepository(db)
-	zennRepo := repository.NewZennRepository(db)
-	qiitaRepo := repository.NewQiitaRepository(db)
 	learningGoalRepo := repository.NewLearningGoalRepository(db)
 	activityReportRepo := repository.NewActivityReportRepository(db)
 	projectRepo := repository.NewProjectRepository(db)
@@ -44,43 +44,82 @@ func Setup(db *gorm.DB, cfg *config.Config, hub *service.Hub) *gin.Engine {
 	answerRepo := repository.NewAnswerRepository(db)
 	roadmapRepo := repository.NewRoadmapRepository(db)
 	chatRoomRepo := repository.NewChatRoomRepository(db)
+	teamRepo := repository.NewTeamRepository(db)
 	groupMessageRepo := repository.NewGroupMessageRepository(db)
+	mentionRepo := repository.NewMentionRepository(db)
+	privacyRepo := repository.NewPrivacyRepository(db)
+	dataExportRepo := repository.NewDataExportRepository(db)
+	integrationSyncRepo := repository.NewIntegrationSyncRepository(db)
+	githubActivityRepo := repository.NewGitHubActivityRepository(db)
+	codeHostRepo := repository.NewCodeHostRepository(db)
+	contributionRepo := repository.NewContributionRepository(db)
+	feedRepo := repository.NewFeedRepository(db)
+	articleRepo := repository.NewArticleRepository(db)
+	badgeRepo := repository.NewBadgeRepository(db)
 
 	// Services
-	authService := service.NewAuthService(userRepo, cfg.JWTSecret)
-	githubService := service.NewGitHubService(cfg, userRepo, githubRepo)
-	zennService := service.NewZennService()
-	qiitaService := service.NewQiitaService()
+	handleService := service.NewHandleService(userRepo)
+	authService := service.NewAuthService(userRepo, handleService, cfg.JWTSecret)
+	githubService := service.NewGitHubService(cfg, userRepo, githubRepo, service.NewHTTPClient(30*time.Second, 2))
+	tokenKey := cfg.TokenEncryptionKey
+	if tokenKey == "" {
+		tokenKey = cfg.JWTSecret
+	}
+	tokenCipher := service.NewTokenCipher(tokenKey)
+	articleService := service.NewArticleService(articleRepo, userRepo, tokenCipher,
+		service.NewZennService(), service.NewQiitaService(service.NewHTTPClient(30*time.Second, 2)))
+	codeHostClient := service.NewHTTPClient(30*time.Second, 2)
+	codeHostService := service.NewCodeHostService(codeHostRepo, tokenCipher,
+		service.NewGitLabService(cfg, codeHostClient), service.NewBitbucketService(cfg, codeHostClient))
+	feedService := service.NewFeedService(cfg, feedRepo, articleRepo)
+	badgeService := service.NewBadgeService(db, badgeRepo, notificationRepo, jobs)
+	syncService := service.NewSyncService(cfg, userRepo, integrationSyncRepo, githubService, articleService, codeHostService, feedService, badgeService, jobs)
+	deletionService := service.NewAccountDeletionService(db, userRepo, jobs)
+	mentionService := service.NewMentionService(mentionRepo, userRepo, notificationRepo, jobs)
+	notificationService := service.NewNotificationService(notificationRepo, jobs)
+	githubWebhookService := service.NewGitHubWebhookService(cfg.GitHubWebhookSecret, userRepo, githubRepo, githubActivityRepo, postRepo, notificationService, jobs)
+	dataExportService := service.NewDataExportService(db, dataExportRepo, cfg.ExportDir, cfg.UploadDir, jobs)
+	rankingService := service.NewRankingService(rankingRepo, notificationRepo, cfg.RankingWeights, jobs)
+	resumeService := service.NewResumeService(userRepo, githubRepo, projectRepo, roadmapRepo, learningGoalRepo, bookReviewRepo, articleRepo)
 
 	// Handlers
-	authHandler := handler.NewAuthHandler(authService, githubService, userRepo, passwordResetRepo)
-	userHandler := handler.NewUserHandler(userRepo)
-	followHandler := handler.NewFollowHandler(followRepo)
-	githubHandler := handler.NewGitHubHandler(githubService, authService, userRepo, githubRepo)
-	postHandler := handler.NewPostHandler(postRepo, notificationRepo)
-	rankingHandler := handler.NewRankingHandler(rankingRepo)
-	messageHandler := handler.NewMessageHandler(messageRepo, notificationRepo)
+	authHandler := handler.NewAuthHandler(authService, githubService, userRepo, passwordResetRepo, deletionService, syncService)
+	userHandler := handler.NewUserHandler(userRepo, handleService, syncService)
+	followHandler := handler.NewFollowHandler(followRepo, badgeService)
+	githubHandler := handler.NewGitHubHandler(githubService, authService, userRepo, githubRepo, syncService, githubActivityRepo)
+	githubWebhookHandler := handler.NewGitHubWebhookHandler(githubWebhookService)
+	codeHostHandler := handler.NewCodeHostHandler(codeHostService, authService, userRepo, codeHostRepo, contributionRepo, syncService)
+	postHandler := handler.NewPostHandler(postRepo, notificationService, mentionService, badgeService)
+	rankingHandler := handler.NewRankingHandler(rankingRepo, chatRoomRepo, teamRepo, rankingService)
+	messageHandler := handler.NewMessageHandler(messageRepo, notificationService, mentionService)
 	wsHandler := handler.NewWebSocketHandler(hub, authService)
-	uploadHandler := handler.NewUploadHandler()
+	uploadHandler := handler.NewUploadHandler(cfg.UploadDir)
 	notificationHandler := handler.NewNotificationHandler(notificationRepo)
-	zennHandler := handler.NewZennHandler(zennRepo, userRepo, zennService)
-	qiitaHandler := handler.NewQiitaHandler(qiitaRepo, userRepo, qiitaService)
-	learningGoalHandler := handler.NewLearningGoalHandler(learningGoalRepo)
+	feedHandler := handler.NewFeedHandler(feedService, feedRepo, userRepo, syncService)
+	articleHandler := handler.NewArticleHandler(articleRepo, userRepo, articleService, syncService)
+	learningGoalHandler := handler.NewLearningGoalHandler(learningGoalRepo, badgeService)
 	activityReportHandler := handler.NewActivityReportHandler(activityReportRepo)
 	projectHandler := handler.NewProjectHandler(projectRepo)
 	learningResourceHandler := handler.NewLearningResourceHandler(learningResourceRepo)
 	bookReviewHandler := handler.NewBookReviewHandler(bookReviewRepo)
-	questionHandler := handler.NewQuestionHandler(questionRepo)
-	answerHandler := handler.NewAnswerHandler(answerRepo, questionRepo)
+	questionHandler := handler.NewQuestionHandler(questionRepo, mentionService)
+	answerHandler := handler.NewAnswerHandler(answerRepo, questionRepo, mentionService, badgeService)
 	roadmapHandler := handler.NewRoadmapHandler(roadmapRepo)
-	chatRoomHandler := handler.NewChatRoomHandler(chatRoomRepo, groupMessageRepo, hub)
-	badgeHandler := handler.NewBadgeHandler(db, notificationRepo)
+	chatRoomHandler := handler.NewChatRoomHandler(chatRoomRepo, groupMessageRepo, hub, mentionService)
+	teamHandler := handler.NewTeamHandler(teamRepo)
+	badgeHandler := handler.NewBadgeHandler(badgeService)
+	mentionHandler := handler.NewMentionHandler(mentionRepo)
+	privacyHandler := handler.NewPrivacyHandler(privacyRepo)
+	publicProfileHandler := handler.NewPublicProfileHandler(badgeService, handleService, privacyRepo, githubRepo, contributionRepo, projectRepo, articleRepo, roadmapRepo)
+	cardHandler := handler.NewCardHandler(db, handleService, privacyRepo, githubRepo, contributionRepo, badgeService)
+	resumeHandler := handler.NewResumeHandler(resumeService)
+	dataExportHandler := handler.NewDataExportHandler(dataExportService, dataExportRepo)
 
 	// Set up Hub's GetRoomMembers callback
 	hub.GetRoomMembers = groupMessageRepo.GetMemberUserIDs
 
 	// Static file serving for uploads
-	r.Static("/uploads", "./uploads")
+	r.Static("/uploads", cfg.UploadDir)
 
 	// Public routes
 	r.GET("/health", handler.HealthCheck)
@@ -88,6 +127,9 @@ func Setup(db *gorm.DB, cfg *config.Config, hub *service.Hub) *gin.Engine {
 	// WebSocket (auth via query param)
 	r.GET("/ws", wsHandler.HandleWebSocket)
 
+	// Webhooks (authenticated by signature)
+	r.POST("/webhooks/github", githubWebhookHandler.Receive)
+
 	api := r.Group("/api/v1")
 
 	// Auth routes (public)
@@ -104,6 +146,23 @@ func Setup(db *gorm.DB, cfg *config.Config, hub *service.Hub) *gin.Engine {
 	// GitHub data-connect callback (public - called by frontend after OAuth redirect)
 	api.GET("/github/callback", githubHandler.Callback)
 
+	// GitLab and Bitbucket data-connect callback (public - called by frontend after OAuth redirect)
+	api.GET("/code-hosts/:provider/callback", codeHostHandler.Callback)
+
+	// Data export download (public - the unguessable token is the credential)
+	api.GET("/export/takeout/download/:token", dataExportHandler.Download)
+
+	// Public profiles (optional auth - owners can preview their own)
+	public := api.Group("/public")
+	public.Use(middleware.AuthOptional(authService))
+	{
+		public.GET("/users/:handle", publicProfileHandler.GetProfile)
+		public.GET("/users/:handle/cards/heatmap.svg", cardHandler.Heatmap)
+		public.GET("/users/:handle/cards/languages.svg", cardHandler.Languages)
+		public.GET("/users/:handle/cards/badges.svg", cardHandler.Badges)
+		public.GET("/users/:handle/cards/streak.svg", cardHandler.Streak)
+	}
+
 	// Protected routes
 	protected := api.Group("")
 	protected.Use(middleware.AuthRequired(authService))
@@ -111,13 +170,17 @@ func Setup(db *gorm.DB, cfg *config.Config, hub *service.Hub) *gin.Engine {
 		// Auth
 		protected.GET("/auth/me", authHandler.Me)
 		protected.DELETE("/auth/account", authHandler.DeleteAccount)
+		protected.POST("/auth/account/restore", authHandler.RestoreAccount)
 
 		// Users
 		users := protected.Group("/users")
 		{
 			users.GET("", userHandler.GetAll)
+			users.GET("/by-handle/:handle", userHandler.GetByHandle)
 			users.GET("/:id", userHandler.GetByID)
 			users.PUT("/:id", userHandler.Update)
+			users.PUT("/:id/handle", userHandler.UpdateHandle)
+			users.GET("/:id/handle-history", userHandler.GetHandleHistory)
 			users.GET("/:id/followers", followHandler.GetFollowers)
 			users.GET("/:id/following", followHandler.GetFollowing)
 			users.POST("/:id/follow", followHandler.Follow)
@@ -132,8 +195,23 @@ func Setup(db *gorm.DB, cfg *config.Config, hub *service.Hub) *gin.Engine {
 			github.POST("/sync", githubHandler.Sync)
 			github.DELETE("/disconnect", githubHandler.Disconnect)
 			github.GET("/contributions/:userId", githubHandler.GetContributions)
+			github.GET("/contributions/:userId/years", githubHandler.GetContributionYears)
 			github.GET("/languages/:userId", githubHandler.GetLanguages)
 			github.GET("/repos/:userId", githubHandler.GetRepos)
+			github.GET("/activity/:userId", githubHandler.GetActivity)
+			github.GET("/metrics/:userId", githubHandler.GetMetrics)
+		}
+
+		// GitLab and Bitbucket
+		codeHosts := protected.Group("/code-hosts")
+		{
+			codeHosts.GET("/accounts/:userId", codeHostHandler.GetAccounts)
+			codeHosts.GET("/contributions/:userId", codeHostHandler.GetContributions)
+			codeHosts.GET("/:provider/connect", codeHostHandler.Connect)
+			codeHosts.POST("/:provider/sync", codeHostHandler.Sync)
+			codeHosts.DELETE("/:provider/disconnect", codeHostHandler.Disconnect)
+			codeHosts.GET("/:provider/repos/:userId", codeHostHandler.GetRepos)
+			codeHosts.GET("/:provider/languages/:userId", codeHostHandler.GetLanguages)
 		}
 
 		// Posts
@@ -155,6 +233,10 @@ func Setup(db *gorm.DB, cfg *config.Config, hub *service.Hub) *gin.Engine {
 		// Rankings
 		rankings := protected.Group("/rankings")
 		{
+			rankings.GET("", rankingHandler.Ranking)
+			rankings.GET("/metrics", rankingHandler.Metrics)
+			rankings.GET("/me", rankingHandler.MyPosition)
+			rankings.GET("/users/:userId/history", rankingHandler.History)
 			rankings.GET("/contributions", rankingHandler.ContributionRanking)
 			rankings.GET("/languages/:lang", rankingHandler.LanguageRanking)
 			rankings.GET("/languages", rankingHandler.AvailableLanguages)
@@ -185,24 +267,45 @@ func Setup(db *gorm.DB, cfg *config.Config, hub *service.Hub) *gin.Engine {
 			notifications.DELETE("/:id", notificationHandler.Delete)
 		}
 
-		// Zenn
-		zenn := protected.Group("/zenn")
+		// Mentions
+		protected.GET("/mentions", mentionHandler.GetMyMentions)
+
+		// Settings
+		settings := protected.Group("/settings")
+		{
+			settings.GET("/privacy", privacyHandler.Get)
+			settings.PUT("/privacy", privacyHandler.Update)
+		}
+
+		// Export
+		export := protected.Group("/export")
+		{
+			export.GET("/resume", resumeHandler.Export)
+			export.POST("/takeout", dataExportHandler.Request)
+			export.GET("/takeout", dataExportHandler.List)
+			export.GET("/takeout/:id", dataExportHandler.Get)
+		}
+
+		// RSS, Atom and JSON Feeds
+		feeds := protected.Group("/feeds")
 		{
-			zenn.POST("/connect", zennHandler.Connect)
-			zenn.DELETE("/disconnect", zennHandler.Disconnect)
-			zenn.POST("/sync", zennHandler.Sync)
-			zenn.GET("/articles/:userId", zennHandler.GetArticles)
-			zenn.GET("/stats/:userId", zennHandler.GetStats)
+			feeds.GET("", feedHandler.List)
+			feeds.POST("", feedHandler.Add)
+			feeds.DELETE("/:id", feedHandler.Remove)
+			feeds.POST("/sync", feedHandler.Sync)
 		}
 
-		// Qiita
-		qiita := protected.Group("/qiita")
+		// Articles from Zenn, Qiita and feeds
+		articles := protected.Group("/articles")
 		{
-			qiita.POST("/connect", qiitaHandler.Connect)
-			qiita.DELETE("/disconnect", qiitaHandler.Disconnect)
-			qiita.POST("/sync", qiitaHandler.Sync)
-			qiita.GET("/articles/:userId", qiitaHandler.GetArticles)
-			qiita.GET("/stats/:userId", qiitaHandler.GetStats)
+			articles.POST("/sources/:source/connect", articleHandler.Connect)
+			articles.DELETE("/sources/:source/disconnect", articleHandler.Disconnect)
+			articles.POST("/sources/:source/sync", articleHandler.Sync)
+			articles.GET("/trending", articleHandler.GetTrending)
+			articles.GET("/:userId", articleHandler.GetArticles)
+			articles.GET("/:userId/stats", articleHandler.GetStats)
+			articles.GET("/:userId/engagement", articleHandler.GetEngagement)
+			articles.GET("/:userId/engagement/:articleId", articleHandler.GetArticleEngagement)
 		}
 
 		// Learning Goals
@@ -312,6 +415,19 @@ func Setup(db *gorm.DB, cfg *config.Config, hub *service.Hub) *gin.Engine {
 			chatRooms.POST("/:id/messages", chatRoomHandler.SendMessage)
 		}
 
+		// Teams
+		teams := protected.Group("/teams")
+		{
+			teams.POST("", teamHandler.Create)
+			teams.GET("", teamHandler.GetMyTeams)
+			teams.GET("/:id", teamHandler.GetByID)
+			teams.PUT("/:id", teamHandler.Update)
+			teams.DELETE("/:id", teamHandler.Delete)
+			teams.GET("/:id/members", teamHandler.GetMembers)
+			teams.POST("/:id/members", teamHandler.AddMember)
+			teams.DELETE("/:id/members/:userId", teamHandler.RemoveMember)
+		}
+
 		// Book Reviews
 		bookReviews := protected.Group("/book-reviews")
 		{
@@ -327,7 +443,7 @@ func Setup(db *gorm.DB, cfg *config.Config, hub *service.Hub) *gin.Engine {
 		badges := protected.Group("/badges")
 		{
 			badges.GET("/:userId", badgeHandler.GetUserBadges)
-			badges.POST("/notify", badgeHandler.NotifyBadgeEarned)
+			badges.GET("/:userId/closest", badgeHandler.GetClosestBadges)
 		}
 	}
 
@@ -0,0 +1,319 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"os"
+	"time"
+
+	"github.com/norman6464/devsync/backend/internal/model"
+	"github.com/norman6464/devsync/backend/internal/queue"
+	"github.com/norman6464/devsync/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+// AccountDeletionGracePeriod is how long a user can restore their account
+// after requesting deletion.
+const AccountDeletionGracePeriod = 30 * 24 * time.Hour
+
+// AccountDeletionService schedules, restores and carries out account
+// deletion. Deletion covers every table that holds user data, including
+// content other users attached to the user's posts, questions and resources,
+// and recomputes the counters on other users' content the user interacted with.
+type AccountDeletionService struct {
+	db       *gorm.DB
+	userRepo *repository.UserRepository
+}
+
+func NewAccountDeletionService(db *gorm.DB, userRepo *repository.UserRepository, jobs *queue.Queue) *AccountDeletionService {
+	s := &AccountDeletionService{db: db, userRepo: userRepo}
+	jobs.Register(queue.JobType{Name: JobAccountDeletionPurge, Handler: s.runPurgeDue})
+	return s
+}
+
+// Schedule marks the account for deletion after the grace period. Until then
+// the user can log in and restore it. Meanwhile they are left out of user
+// search, mentions, rankings and their public profile, but their posts and
+// profile by ID stay visible.
+func (s *AccountDeletionService) Schedule(user *model.User) error {
+	scheduledAt := time.Now().Add(AccountDeletionGracePeriod)
+	user.DeletionScheduledAt = &scheduledAt
+	return s.userRepo.Update(user)
+}
+
+// Restore cancels a scheduled deletion.
+func (s *AccountDeletionService) Restore(user *model.User) error {
+	user.DeletionScheduledAt = nil
+	return s.userRepo.Update(user)
+}
+
+// PurgeDue permanently deletes every account whose grace period has ended.
+func (s *AccountDeletionService) PurgeDue() (int, error) {
+	users, err := s.userRepo.FindDueForDeletion(time.Now())
+	if err != nil {
+		return 0, err
+	}
+	purged := 0
+	for _, u := range users {
+		if err := s.DeleteNow(u.ID); err != nil {
+			log.Printf("account deletion: failed to delete user %d: %v", u.ID, err)
+			continue
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+func (s *AccountDeletionService) runPurgeDue(ctx context.Context, _ json.RawMessage) error {
+	n, err := s.PurgeDue()
+	if n > 0 {
+		log.Printf("account deletion: purged %d accounts", n)
+	}
+	return err
+}
+
+// affectedContent holds other users' content whose counters change when the
+// user's likes, comments, votes, answers and saves are removed.
+type affectedContent struct {
+	postIDs     []uint
+	questionIDs []uint
+	answerIDs   []uint
+	resourceIDs []uint
+}
+
+// DeleteNow permanently deletes a user and all their data.
+func (s *AccountDeletionService) DeleteNow(userID uint) error {
+	var exportFiles []string
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// Soft-deleted rows are purged as well
+		tx = tx.Unscoped().Session(&gorm.Session{})
+
+		ownPosts := tx.Model(&model.Post{}).Select("id").Where("user_id = ?", userID)
+		ownQuestions := tx.Model(&model.Question{}).Select("id").Where("user_id = ?", userID)
+		ownResources := tx.Model(&model.LearningResource{}).Select("id").Where("user_id = ?", userID)
+		ownRoadmaps := tx.Model(&model.Roadmap{}).Select("id").Where("user_id = ?", userID)
+		// The user's answers plus everyone's answers to the user's questions
+		doomedAnswers := tx.Model(&model.Answer{}).Select("id").Where("user_id = ? OR question_id IN (?)", userID, ownQuestions)
+
+		affected, err := collectAffectedContent(tx, userID)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Model(&model.DataExport{}).Where("user_id = ? AND file_path <> ''", userID).
+			Pluck("file_path", &exportFiles).Error; err != nil {
+			return err
+		}
+
+		steps := []struct {
+			model interface{}
+			query string
+			args  []interface{}
+		}{
+			// Notifications and mentions for or by the user, or about their content
+			{&model.Notification{}, "user_id = ? OR actor_id = ? OR post_id IN (?) OR question_id IN (?)", []interface{}{userID, userID, ownPosts, ownQuestions}},
+			{&model.Mention{}, "user_id = ? OR actor_id = ? OR post_id IN (?) OR question_id IN (?)", []interface{}{userID, userID, ownPosts, ownQuestions}},
+
+			// Posts, with everyone's likes and comments on them
+			{&model.Like{}, "user_id = ? OR post_id IN (?)", []interface{}{userID, ownPosts}},
+			{&model.Comment{}, "user_id = ? OR post_id IN (?)", []interface{}{userID, ownPosts}},
+			{&model.Post{}, "user_id = ?", []interface{}{userID}},
+
+			// Q&A, with everyone's votes and answers on the user's questions
+			{&model.AnswerVote{}, "user_id = ? OR answer_id IN (?)", []interface{}{userID, doomedAnswers}},
+			{&model.QuestionVote{}, "user_id = ? OR question_id IN (?)", []interface{}{userID, ownQuestions}},
+			{&model.Answer{}, "id IN (?)", []interface{}{doomedAnswers}},
+			{&model.Question{}, "user_id = ?", []interface{}{userID}},
+
+			// Learning resources, with everyone's likes and saves on them
+			{&model.ResourceLike{}, "user_id = ? OR resource_id IN (?)", []interface{}{userID, ownResources}},
+			{&model.ResourceSave{}, "user_id = ? OR resource_id IN (?)", []interface{}{userID, ownResources}},
+			{&model.LearningResource{}, "user_id = ?", []interface{}{userID}},
+
+			{&model.RoadmapStep{}, "roadmap_id IN (?)", []interface{}{ownRoadmaps}},
+			{&model.Roadmap{}, "user_id = ?", []interface{}{userID}},
+			{&model.LearningGoal{}, "user_id = ?", []interface{}{userID}},
+			{&model.Project{}, "user_id = ?", []interface{}{userID}},
+			{&model.BookReview{}, "user_id = ?", []interface{}{userID}},
+
+			{&model.Message{}, "sender_id = ? OR receiver_id = ?", []interface{}{userID, userID}},
+			{&model.GroupMessage{}, "sender_id = ?", []interface{}{userID}},
+			{&model.ChatRoomMember{}, "user_id = ?", []interface{}{userID}},
+			{&model.TeamMember{}, "user_id = ?", []interface{}{userID}},
+			{&model.Follow{}, "follower_id = ? OR followee_id = ?", []interface{}{userID, userID}},
+
+			{&model.GitHubContribution{}, "user_id = ?", []interface{}{userID}},
+			{&model.GitHubContributionYear{}, "user_id = ?", []interface{}{userID}},
+			{&model.GitHubLanguageStat{}, "user_id = ?", []interface{}{userID}},
+			{&model.GitHubRepoLanguage{}, "repository_id IN (SELECT id FROM git_hub_repositories WHERE user_id = ?)", []interface{}{userID}},
+			{&model.GitHubRepository{}, "user_id = ?", []interface{}{userID}},
+			{&model.GitHubActivity{}, "user_id = ?", []interface{}{userID}},
+			{&model.GitHubPullRequest{}, "user_id = ?", []interface{}{userID}},
+			{&model.GitHubIssue{}, "user_id = ?", []interface{}{userID}},
+			{&model.GitHubReview{}, "user_id = ?", []interface{}{userID}},
+			{&model.CodeHostContribution{}, "user_id = ?", []interface{}{userID}},
+			{&model.CodeHostLanguageStat{}, "user_id = ?", []interface{}{userID}},
+			{&model.CodeHostRepository{}, "user_id = ?", []interface{}{userID}},
+			{&model.CodeHostAccount{}, "user_id = ?", []interface{}{userID}},
+			{&model.ArticleEngagementSnapshot{}, "user_id = ?", []interface{}{userID}},
+			{&model.ExternalArticle{}, "user_id = ?", []interface{}{userID}},
+			{&model.ArticleFeed{}, "user_id = ?", []interface{}{userID}},
+			{&model.LeaderboardEntry{}, "user_id = ?", []interface{}{userID}},
+			{&model.UserBadge{}, "user_id = ?", []interface{}{userID}},
+
+			{&model.PasswordResetToken{}, "user_id = ?", []interface{}{userID}},
+			{&model.HandleHistory{}, "user_id = ?", []interface{}{userID}},
+			{&model.PrivacySettings{}, "user_id = ?", []interface{}{userID}},
+			{&model.DataExport{}, "user_id = ?", []interface{}{userID}},
+			{&model.IntegrationSync{}, "user_id = ?", []interface{}{userID}},
+		}
+		for _, step := range steps {
+			if err := tx.Where(step.query, step.args...).Delete(step.model).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := s.handOverChatRooms(tx, userID); err != nil {
+			return err
+		}
+		if err := s.handOverTeams(tx, userID); err != nil {
+			return err
+		}
+
+		if err := tx.Delete(&model.User{}, userID).Error; err != nil {
+			return err
+		}
+
+		return recomputeCounters(tx, affected)
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, path := range exportFiles {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			log.Printf("account deletion: failed to remove export %s: %v", path, err)
+		}
+	}
+	return nil
+}
+
+// collectAffectedContent finds other users' content the user interacted with.
+func collectAffectedContent(tx *gorm.DB, userID uint) (*affectedContent, error) {
+	a := &affectedContent{}
+	if err := tx.Raw(`SELECT post_id FROM likes WHERE user_id = ?
+		UNION SELECT post_id FROM comments WHERE user_id = ?`, userID, userID).Scan(&a.postIDs).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Raw(`SELECT question_id FROM question_votes WHERE user_id = ?
+		UNION SELECT question_id FROM answers WHERE user_id = ?`, userID, userID).Scan(&a.questionIDs).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Raw(`SELECT answer_id FROM answer_votes WHERE user_id = ?`, userID).Scan(&a.answerIDs).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Raw(`SELECT resource_id FROM resource_likes WHERE user_id = ?
+		UNION SELECT resource_id FROM resource_saves WHERE user_id = ?`, userID, userID).Scan(&a.resourceIDs).Error; err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// recomputeCounters rebuilds denormalized counts from their source tables.
+// Rows that were themselves deleted simply match nothing.
+func recomputeCounters(tx *gorm.DB, a *affectedContent) error {
+	if len(a.postIDs) > 0 {
+		if err := tx.Model(&model.Post{}).Where("id IN ?", a.postIDs).Updates(map[string]interface{}{
+			"like_count":    gorm.Expr("(SELECT COUNT(*) FROM likes WHERE likes.post_id = posts.id)"),
+			"comment_count": gorm.Expr("(SELECT COUNT(*) FROM comments WHERE comments.post_id = posts.id)"),
+		}).Error; err != nil {
+			return err
+		}
+	}
+	if len(a.questionIDs) > 0 {
+		if err := tx.Model(&model.Question{}).Where("id IN ?", a.questionIDs).Updates(map[string]interface{}{
+			"vote_count":   gorm.Expr("(SELECT COALESCE(SUM(value), 0) FROM question_votes WHERE question_votes.question_id = questions.id)"),
+			"answer_count": gorm.Expr("(SELECT COUNT(*) FROM answers WHERE answers.question_id = questions.id AND answers.deleted_at IS NULL)"),
+			"is_solved":    gorm.Expr("EXISTS (SELECT 1 FROM answers WHERE answers.question_id = questions.id AND answers.is_best AND answers.deleted_at IS NULL)"),
+		}).Error; err != nil {
+			return err
+		}
+	}
+	if len(a.answerIDs) > 0 {
+		if err := tx.Model(&model.Answer{}).Where("id IN ?", a.answerIDs).
+			Update("vote_count", gorm.Expr("(SELECT COALESCE(SUM(value), 0) FROM answer_votes WHERE answer_votes.answer_id = answers.id)")).Error; err != nil {
+			return err
+		}
+	}
+	if len(a.resourceIDs) > 0 {
+		if err := tx.Model(&model.LearningResource{}).Where("id IN ?", a.resourceIDs).Updates(map[string]interface{}{
+			"like_count": gorm.Expr("(SELECT COUNT(*) FROM resource_likes WHERE resource_likes.resource_id = learning_resources.id)"),
+			"save_count": gorm.Expr("(SELECT COUNT(*) FROM resource_saves WHERE resource_saves.resource_id = learning_resources.id)"),
+		}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// handOverChatRooms gives each room the user owns to its longest-standing
+// remaining member, and deletes rooms nobody else is in. Runs after the
+// user's own memberships were removed.
+func (s *AccountDeletionService) handOverChatRooms(tx *gorm.DB, userID uint) error {
+	var rooms []model.ChatRoom
+	if err := tx.Where("owner_id = ?", userID).Find(&rooms).Error; err != nil {
+		return err
+	}
+	for _, room := range rooms {
+		var next model.ChatRoomMember
+		err := tx.Where("chat_room_id = ?", room.ID).Order("joined_at ASC").First(&next).Error
+		if err == nil {
+			if err := tx.Model(&room).Update("owner_id", next.UserID).Error; err != nil {
+				return err
+			}
+			continue
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		for _, m := range []interface{}{&model.GroupMessage{}, &model.Notification{}, &model.Mention{}} {
+			if err := tx.Where("chat_room_id = ?", room.ID).Delete(m).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Delete(&room).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// handOverTeams gives each team the user owns to its longest-standing
+// remaining member, and deletes teams nobody else is in. Runs after the
+// user's own memberships were removed.
+func (s *AccountDeletionService) handOverTeams(tx *gorm.DB, userID uint) error {
+	var teams []model.Team
+	if err := tx.Where("owner_id = ?", userID).Find(&teams).Error; err != nil {
+		return err
+	}
+	for _, team := range teams {
+		var next model.TeamMember
+		err := tx.Where("team_id = ?", team.ID).Order("joined_at ASC").First(&next).Error
+		if err == nil {
+			if err := tx.Model(&team).Update("owner_id", next.UserID).Error; err != nil {
+				return err
+			}
+			continue
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if err := tx.Delete(&team).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
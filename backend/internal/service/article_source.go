@@ -0,0 +1,204 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/norman6464/devsync/backend/internal/model"
+	"github.com/norman6464/devsync/backend/internal/repository"
+)
+
+// articleFullRefresh is how often all of a user's articles are re-read even
+// when the source is synced incrementally, so like and comment counts on
+// older articles stay current.
+const articleFullRefresh = 7 * 24 * time.Hour
+
+var (
+	ErrUnknownArticleSource    = errors.New("unknown article source")
+	ErrInvalidArticleUser      = errors.New("username not found on the article source")
+	ErrArticleTokenUnsupported = errors.New("article source does not take access tokens")
+	ErrInvalidArticleToken     = errors.New("access token is invalid or belongs to another account")
+)
+
+// ArticleFetch describes what to read from an ArticleSource.
+type ArticleFetch struct {
+	Username string
+	// Token is the user's decrypted access token, if they gave one
+	Token string
+	// Known reports whether an article is already stored. Sources that list
+	// articles newest first may stop paging once they reach known ones. It is
+	// nil when every article should be read.
+	Known func(externalID string) bool
+}
+
+// ArticleSource is a blogging platform whose articles are read by username,
+// such as Zenn or Qiita. Adding a platform means implementing it and passing
+// it to NewArticleService.
+type ArticleSource interface {
+	Source() model.IntegrationProvider
+	// Username returns the account the user has connected, or "" if none
+	Username(user *model.User) string
+	SetUsername(user *model.User, username string)
+	ValidateUsername(username string) (bool, error)
+	// FetchArticles returns the account's articles. On failure it returns
+	// the articles read before the error along with it.
+	FetchArticles(fetch ArticleFetch) ([]model.ExternalArticle, error)
+}
+
+// TokenArticleSource is an ArticleSource that can read with the user's own
+// access token, for a higher rate limit and articles only they can list.
+type TokenArticleSource interface {
+	ArticleSource
+	// Token returns the user's encrypted access token, or "" if none
+	Token(user *model.User) string
+	SetToken(user *model.User, encrypted string)
+	// ValidateToken checks the token is valid and belongs to username
+	ValidateToken(username, token string) error
+}
+
+// ArticleService connects users' accounts on article sources and keeps their
+// articles in the article table.
+type ArticleService struct {
+	articleRepo *repository.ArticleRepository
+	userRepo    *repository.UserRepository
+	tokens      *TokenCipher
+	sources     map[model.IntegrationProvider]ArticleSource
+}
+
+func NewArticleService(articleRepo *repository.ArticleRepository, userRepo *repository.UserRepository, tokens *TokenCipher, sources ...ArticleSource) *ArticleService {
+	s := &ArticleService{
+		articleRepo: articleRepo,
+		userRepo:    userRepo,
+		tokens:      tokens,
+		sources:     make(map[model.IntegrationProvider]ArticleSource, len(sources)),
+	}
+	for _, source := range sources {
+		s.sources[source.Source()] = source
+	}
+	return s
+}
+
+// Source returns the named article source.
+func (s *ArticleService) Source(name model.IntegrationProvider) (ArticleSource, error) {
+	source, ok := s.sources[name]
+	if !ok {
+		return nil, ErrUnknownArticleSource
+	}
+	return source, nil
+}
+
+// Connected reports whether the user has an account on the source connected.
+func (s *ArticleService) Connected(name model.IntegrationProvider, user *model.User) bool {
+	source, ok := s.sources[name]
+	return ok && source.Username(user) != ""
+}
+
+// Connect checks the username exists on the source and saves it on the user,
+// along with their access token, encrypted, if they gave one. Connecting
+// without a token drops any stored one.
+func (s *ArticleService) Connect(name model.IntegrationProvider, user *model.User, username, token string) error {
+	source, err := s.Source(name)
+	if err != nil {
+		return err
+	}
+	tokenSource, takesToken := source.(TokenArticleSource)
+	if token != "" && !takesToken {
+		return ErrArticleTokenUnsupported
+	}
+	if valid, err := source.ValidateUsername(username); err != nil || !valid {
+		return ErrInvalidArticleUser
+	}
+
+	if takesToken {
+		var encrypted string
+		if token != "" {
+			if err := tokenSource.ValidateToken(username, token); err != nil {
+				return err
+			}
+			if encrypted, err = s.tokens.Encrypt(token); err != nil {
+				return err
+			}
+		}
+		tokenSource.SetToken(user, encrypted)
+	}
+	source.SetUsername(user, username)
+	return s.userRepo.Update(user)
+}
+
+// Authenticated reports whether the user's account on the source is read
+// with their access token.
+func (s *ArticleService) Authenticated(name model.IntegrationProvider, user *model.User) bool {
+	tokenSource, ok := s.sources[name].(TokenArticleSource)
+	return ok && tokenSource.Token(user) != ""
+}
+
+// Disconnect clears the user's account on the source and deletes its
+// articles.
+func (s *ArticleService) Disconnect(name model.IntegrationProvider, user *model.User) error {
+	source, err := s.Source(name)
+	if err != nil {
+		return err
+	}
+	source.SetUsername(user, "")
+	if tokenSource, ok := source.(TokenArticleSource); ok {
+		tokenSource.SetToken(user, "")
+	}
+	if err := s.userRepo.Update(user); err != nil {
+		return err
+	}
+	return s.articleRepo.DeleteBySource(user.ID, name)
+}
+
+// Sync fetches the user's new and changed articles from the source, stores
+// them and snapshots the day's engagement counts of the articles it read,
+// reading them all again every articleFullRefresh. It returns
+// the number of articles synced. Articles read before a failure are stored
+// too.
+func (s *ArticleService) Sync(name model.IntegrationProvider, user *model.User) (int, error) {
+	source, err := s.Source(name)
+	if err != nil {
+		return 0, err
+	}
+	fetch := ArticleFetch{Username: source.Username(user)}
+	if tokenSource, ok := source.(TokenArticleSource); ok && tokenSource.Token(user) != "" {
+		if fetch.Token, err = s.tokens.Decrypt(tokenSource.Token(user)); err != nil {
+			return 0, fmt.Errorf("failed to decrypt %s token: %w", name, err)
+		}
+	}
+
+	known, err := s.articleRepo.KnownArticles(user.ID, name)
+	if err != nil {
+		return 0, err
+	}
+	now := time.Now()
+	incremental := len(known) > 0
+	for _, updatedAt := range known {
+		if updatedAt.Before(now.Add(-articleFullRefresh)) {
+			incremental = false
+			break
+		}
+	}
+	if incremental {
+		fetch.Known = func(externalID string) bool {
+			_, ok := known[externalID]
+			return ok
+		}
+	}
+
+	articles, fetchErr := source.FetchArticles(fetch)
+	fetched := make([]string, len(articles))
+	for i := range articles {
+		articles[i].UpdatedAt = now
+		fetched[i] = articles[i].ExternalID
+	}
+	if err := s.articleRepo.UpsertArticles(user.ID, name, articles); err != nil {
+		return 0, err
+	}
+	// Articles an incremental sync did not reach keep their old counts, so
+	// they are left out of the day's snapshot rather than recorded as flat
+	if err := s.articleRepo.RecordEngagement(user.ID, name, fetched, now.UTC().Truncate(24*time.Hour)); err != nil {
+		return 0, err
+	}
+	return len(articles), fetchErr
+}
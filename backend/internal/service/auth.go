@@ -2,6 +2,7 @@ package service
 
 import (
 	"errors"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -11,19 +12,22 @@ import (
 )
 
 type AuthService struct {
-	userRepo  *repository.UserRepository
-	jwtSecret []byte
+	userRepo      *repository.UserRepository
+	handleService *HandleService
+	jwtSecret     []byte
 }
 
-func NewAuthService(userRepo *repository.UserRepository, jwtSecret string) *AuthService {
+func NewAuthService(userRepo *repository.UserRepository, handleService *HandleService, jwtSecret string) *AuthService {
 	return &AuthService{
-		userRepo:  userRepo,
-		jwtSecret: []byte(jwtSecret),
+		userRepo:      userRepo,
+		handleService: handleService,
+		jwtSecret:     []byte(jwtSecret),
 	}
 }
 
 type RegisterInput struct {
 	Name     string `json:"name" binding:"required"`
+	Handle   string `json:"handle"`
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required,min=6"`
 }
@@ -44,6 +48,23 @@ func (s *AuthService) Register(input RegisterInput) (*AuthResponse, error) {
 		return nil, errors.New("email already registered")
 	}
 
+	handle := NormalizeHandle(input.Handle)
+	if handle != "" {
+		if err := ValidateHandle(handle); err != nil {
+			return nil, err
+		}
+		if taken, err := s.userRepo.HandleTaken(handle, 0); err != nil {
+			return nil, err
+		} else if taken {
+			return nil, ErrHandleTaken
+		}
+	} else {
+		var err error
+		if handle, err = s.handleService.Available(0, input.Name, strings.Split(input.Email, "@")[0]); err != nil {
+			return nil, err
+		}
+	}
+
 	hashed, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
 	if err != nil {
 		return nil, err
@@ -51,6 +72,7 @@ func (s *AuthService) Register(input RegisterInput) (*AuthResponse, error) {
 
 	user := &model.User{
 		Name:     input.Name,
+		Handle:   handle,
 		Email:    input.Email,
 		Password: string(hashed),
 	}
@@ -185,8 +207,14 @@ func (s *AuthService) GitHubLogin(ghUser *GitHubUserInfo, accessToken string) (*
 		email = ghUser.Login + "@github.local"
 	}
 
+	handle, err := s.handleService.Available(0, ghUser.Login, name)
+	if err != nil {
+		return nil, err
+	}
+
 	newUser := &model.User{
 		Name:            name,
+		Handle:          handle,
 		Email:           email,
 		GitHubID:        ghUser.ID,
 		GitHubUsername:  ghUser.Login,
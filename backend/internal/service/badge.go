@@ -1,9 +1,16 @@
 package service
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
 	"sort"
 	"time"
 
+	"github.com/norman6464/devsync/backend/internal/model"
+	"github.com/norman6464/devsync/backend/internal/queue"
+	"github.com/norman6464/devsync/backend/internal/repository"
 	"gorm.io/gorm"
 )
 
@@ -17,15 +24,256 @@ type BadgeStats struct {
 	FollowingCount     int
 	QAAnswerCount      int
 	CompletedGoals     int
+	TotalArticles      int
+	ArticleLikes       int
 }
 
-// BadgeResult represents a single badge with its earned status.
+// Badge metrics: the BadgeStats value a badge definition's threshold applies to
+const (
+	BadgeMetricContributions  = "contributions"
+	BadgeMetricStreak         = "streak"
+	BadgeMetricPosts          = "posts"
+	BadgeMetricLikesReceived  = "likes_received"
+	BadgeMetricFollowers      = "followers"
+	BadgeMetricFollowing      = "following"
+	BadgeMetricAnswers        = "answers"
+	BadgeMetricGoalsCompleted = "goals_completed"
+	BadgeMetricArticles       = "articles"
+	BadgeMetricArticleLikes   = "article_likes"
+)
+
+// Value returns the statistic a badge metric measures, or false for an
+// unknown metric.
+func (s *BadgeStats) Value(metric string) (int, bool) {
+	switch metric {
+	case BadgeMetricContributions:
+		return s.TotalContributions, true
+	case BadgeMetricStreak:
+		return s.CurrentStreak, true
+	case BadgeMetricPosts:
+		return s.TotalPosts, true
+	case BadgeMetricLikesReceived:
+		return s.TotalLikesReceived, true
+	case BadgeMetricFollowers:
+		return s.FollowerCount, true
+	case BadgeMetricFollowing:
+		return s.FollowingCount, true
+	case BadgeMetricAnswers:
+		return s.QAAnswerCount, true
+	case BadgeMetricGoalsCompleted:
+		return s.CompletedGoals, true
+	case BadgeMetricArticles:
+		return s.TotalArticles, true
+	case BadgeMetricArticleLikes:
+		return s.ArticleLikes, true
+	}
+	return 0, false
+}
+
+// BadgeResult represents a single badge with its earned status. Progress is
+// the user's current value of the badge's metric, out of Threshold, and
+// Rarity the percentage of users who hold the badge.
 type BadgeResult struct {
-	ID          string `json:"id"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	Category    string `json:"category"`
-	Earned      bool   `json:"earned"`
+	ID          string          `json:"id"`
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Category    string          `json:"category"`
+	Tier        model.BadgeTier `json:"tier"`
+	Icon        string          `json:"icon"`
+	Metric      string          `json:"metric"`
+	Progress    int             `json:"progress"`
+	Threshold   int             `json:"threshold"`
+	Rarity      float64         `json:"rarity"`
+	Earned      bool            `json:"earned"`
+	AwardedAt   *time.Time      `json:"awarded_at,omitempty"`
+}
+
+// Ratio returns how far the user is toward the badge's threshold, from 0 to 1.
+func (r *BadgeResult) Ratio() float64 {
+	if r.Threshold <= 0 || r.Progress >= r.Threshold {
+		return 1
+	}
+	return float64(r.Progress) / float64(r.Threshold)
+}
+
+// BadgeService awards badges. A user is evaluated in the background after
+// activity that can earn one, and everyone once a day for time-based badges
+// such as streaks. Each new badge is persisted and notified once.
+type BadgeService struct {
+	db               *gorm.DB
+	badgeRepo        *repository.BadgeRepository
+	notificationRepo *repository.NotificationRepository
+	jobs             *queue.Queue
+}
+
+type badgeJob struct {
+	UserID uint `json:"user_id"`
+}
+
+func NewBadgeService(db *gorm.DB, badgeRepo *repository.BadgeRepository, notificationRepo *repository.NotificationRepository, jobs *queue.Queue) *BadgeService {
+	s := &BadgeService{db: db, badgeRepo: badgeRepo, notificationRepo: notificationRepo, jobs: jobs}
+	jobs.Register(queue.JobType{Name: JobBadgeEvaluate, Handler: s.runEvaluate})
+	jobs.Register(queue.JobType{Name: JobBadgeEvaluateAll, Handler: s.runEvaluateAll})
+	return s
+}
+
+// Evaluate queues a check of the user's badges. A check already queued for
+// them is not queued again.
+func (s *BadgeService) Evaluate(userID uint) {
+	err := s.jobs.EnqueueWith(JobBadgeEvaluate, badgeJob{UserID: userID}, queue.Options{
+		UniqueKey: fmt.Sprintf("%s:%d", JobBadgeEvaluate, userID),
+	})
+	if err != nil {
+		log.Printf("badge: failed to enqueue evaluation for user %d: %v", userID, err)
+	}
+}
+
+// Award gives the user every badge they have reached the threshold of but
+// not yet been awarded, notifying them of each. It returns the new badges.
+func (s *BadgeService) Award(userID uint) ([]model.BadgeDefinition, error) {
+	stats, err := GetBadgeStats(s.db, userID)
+	if err != nil {
+		return nil, err
+	}
+	definitions, err := s.badgeRepo.Definitions()
+	if err != nil {
+		return nil, err
+	}
+	awards, err := s.badgeRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	held := make(map[string]bool, len(awards))
+	for _, a := range awards {
+		held[a.BadgeID] = true
+	}
+
+	now := time.Now()
+	var awarded []model.BadgeDefinition
+	for _, badge := range definitions {
+		value, ok := stats.Value(badge.Metric)
+		if held[badge.ID] || !ok || value < badge.Threshold {
+			continue
+		}
+		created, err := s.badgeRepo.Award(userID, badge.ID, now)
+		if err != nil {
+			return awarded, err
+		}
+		if !created {
+			continue
+		}
+		awarded = append(awarded, badge)
+
+		badgeID := badge.ID
+		if err := s.notificationRepo.Create(&model.Notification{
+			UserID:  userID,
+			Type:    model.NotificationTypeBadge,
+			ActorID: userID,
+			BadgeID: &badgeID,
+		}); err != nil {
+			log.Printf("badge: failed to notify user %d of %s: %v", userID, badge.ID, err)
+		}
+	}
+	return awarded, nil
+}
+
+// Results returns every badge with whether the user has been awarded it,
+// their progress toward it and how rare it is.
+func (s *BadgeService) Results(userID uint) ([]BadgeResult, error) {
+	stats, err := GetBadgeStats(s.db, userID)
+	if err != nil {
+		return nil, err
+	}
+	definitions, err := s.badgeRepo.Definitions()
+	if err != nil {
+		return nil, err
+	}
+	awards, err := s.badgeRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	holders, err := s.badgeRepo.HolderCounts()
+	if err != nil {
+		return nil, err
+	}
+	var users int64
+	if err := s.db.Model(&model.User{}).Count(&users).Error; err != nil {
+		return nil, err
+	}
+
+	awardedAt := make(map[string]time.Time, len(awards))
+	for _, a := range awards {
+		awardedAt[a.BadgeID] = a.AwardedAt
+	}
+
+	results := make([]BadgeResult, len(definitions))
+	for i, badge := range definitions {
+		results[i] = BadgeResult{
+			ID:          badge.ID,
+			Name:        badge.Name,
+			Description: badge.Description,
+			Category:    badge.Category,
+			Tier:        badge.Tier,
+			Icon:        badge.Icon,
+			Metric:      badge.Metric,
+			Threshold:   badge.Threshold,
+		}
+		results[i].Progress, _ = stats.Value(badge.Metric)
+		if users > 0 {
+			results[i].Rarity = 100 * float64(holders[badge.ID]) / float64(users)
+		}
+		if at, ok := awardedAt[badge.ID]; ok {
+			results[i].Earned = true
+			results[i].AwardedAt = &at
+		}
+	}
+	return results, nil
+}
+
+// Closest returns up to limit of the badges the user has yet to earn, those
+// they are nearest to earning first.
+func (s *BadgeService) Closest(userID uint, limit int) ([]BadgeResult, error) {
+	results, err := s.Results(userID)
+	if err != nil {
+		return nil, err
+	}
+	closest := make([]BadgeResult, 0, len(results))
+	for _, r := range results {
+		if !r.Earned && r.Progress < r.Threshold {
+			closest = append(closest, r)
+		}
+	}
+	sort.SliceStable(closest, func(i, j int) bool {
+		if ri, rj := closest[i].Ratio(), closest[j].Ratio(); ri != rj {
+			return ri > rj
+		}
+		return closest[i].Threshold-closest[i].Progress < closest[j].Threshold-closest[j].Progress
+	})
+	if len(closest) > limit {
+		closest = closest[:limit]
+	}
+	return closest, nil
+}
+
+func (s *BadgeService) runEvaluate(ctx context.Context, payload json.RawMessage) error {
+	var job badgeJob
+	if err := queue.Decode(payload, &job); err != nil {
+		return err
+	}
+	_, err := s.Award(job.UserID)
+	return err
+}
+
+// runEvaluateAll queues a check of every active user's badges.
+func (s *BadgeService) runEvaluateAll(ctx context.Context, _ json.RawMessage) error {
+	var userIDs []uint
+	if err := s.db.Model(&model.User{}).Where("deletion_scheduled_at IS NULL").Pluck("id", &userIDs).Error; err != nil {
+		return err
+	}
+	for _, userID := range userIDs {
+		s.Evaluate(userID)
+	}
+	return nil
 }
 
 // GetBadgeStats collects all statistics from the database needed for badge evaluation.
@@ -33,7 +281,7 @@ func GetBadgeStats(db *gorm.DB, userID uint) (*BadgeStats, error) {
 	stats := &BadgeStats{}
 
 	// Total contributions
-	db.Raw("SELECT COALESCE(SUM(count), 0) FROM git_hub_contributions WHERE user_id = ?", userID).Scan(&stats.TotalContributions)
+	db.Raw("SELECT COALESCE(SUM(count), 0) FROM contributions WHERE user_id = ?", userID).Scan(&stats.TotalContributions)
 
 	// Current streak
 	streak, err := calculateStreak(db, userID)
@@ -55,11 +303,15 @@ func GetBadgeStats(db *gorm.DB, userID uint) (*BadgeStats, error) {
 	db.Raw("SELECT COUNT(*) FROM follows WHERE follower_id = ?", userID).Scan(&stats.FollowingCount)
 
 	// QA answer count
-	db.Raw("SELECT COUNT(*) FROM answers WHERE user_id = ?", userID).Scan(&stats.QAAnswerCount)
+	db.Raw("SELECT COUNT(*) FROM answers WHERE user_id = ? AND deleted_at IS NULL", userID).Scan(&stats.QAAnswerCount)
 
 	// Completed goals
 	db.Raw("SELECT COUNT(*) FROM learning_goals WHERE user_id = ? AND status = ?", userID, "completed").Scan(&stats.CompletedGoals)
 
+	// Public articles on Zenn, Qiita and feeds, and the likes they received
+	db.Raw("SELECT COUNT(*), COALESCE(SUM(likes), 0) FROM external_articles WHERE user_id = ? AND NOT private", userID).
+		Row().Scan(&stats.TotalArticles, &stats.ArticleLikes)
+
 	return stats, nil
 }
 
@@ -70,7 +322,7 @@ func calculateStreak(db *gorm.DB, userID uint) (int, error) {
 		Count int
 	}
 	var contributions []DateCount
-	err := db.Raw("SELECT date, count FROM git_hub_contributions WHERE user_id = ? AND count > 0 ORDER BY date DESC", userID).Scan(&contributions).Error
+	err := db.Raw("SELECT date, count FROM contributions WHERE user_id = ? AND count > 0 ORDER BY date DESC", userID).Scan(&contributions).Error
 	if err != nil {
 		return 0, err
 	}
@@ -100,40 +352,3 @@ func calculateStreak(db *gorm.DB, userID uint) (int, error) {
 
 	return streak, nil
 }
-
-// EvaluateBadges evaluates all badges based on the given stats.
-func EvaluateBadges(stats *BadgeStats) []BadgeResult {
-	return []BadgeResult{
-		// Contribution badges
-		{ID: "first-commit", Name: "badges.firstCommit", Description: "badges.firstCommitDesc", Category: "contribution", Earned: stats.TotalContributions >= 1},
-		{ID: "contributor", Name: "badges.contributor", Description: "badges.contributorDesc", Category: "contribution", Earned: stats.TotalContributions >= 50},
-		{ID: "code-warrior", Name: "badges.codeWarrior", Description: "badges.codeWarriorDesc", Category: "contribution", Earned: stats.TotalContributions >= 200},
-		{ID: "commit-master", Name: "badges.commitMaster", Description: "badges.commitMasterDesc", Category: "contribution", Earned: stats.TotalContributions >= 500},
-		{ID: "legend", Name: "badges.legend", Description: "badges.legendDesc", Category: "contribution", Earned: stats.TotalContributions >= 1000},
-
-		// Streak badges
-		{ID: "week-streak", Name: "badges.weekStreak", Description: "badges.weekStreakDesc", Category: "streak", Earned: stats.CurrentStreak >= 7},
-		{ID: "month-streak", Name: "badges.monthStreak", Description: "badges.monthStreakDesc", Category: "streak", Earned: stats.CurrentStreak >= 30},
-
-		// Post badges
-		{ID: "first-post", Name: "badges.firstPost", Description: "badges.firstPostDesc", Category: "post", Earned: stats.TotalPosts >= 1},
-		{ID: "blogger", Name: "badges.blogger", Description: "badges.bloggerDesc", Category: "post", Earned: stats.TotalPosts >= 10},
-
-		// Engagement badges
-		{ID: "liked", Name: "badges.liked", Description: "badges.likedDesc", Category: "engagement", Earned: stats.TotalLikesReceived >= 10},
-		{ID: "popular", Name: "badges.popular", Description: "badges.popularDesc", Category: "engagement", Earned: stats.TotalLikesReceived >= 50},
-
-		// Social badges
-		{ID: "friendly", Name: "badges.friendly", Description: "badges.friendlyDesc", Category: "social", Earned: stats.FollowingCount >= 5},
-		{ID: "influencer", Name: "badges.influencer", Description: "badges.influencerDesc", Category: "social", Earned: stats.FollowerCount >= 10},
-		{ID: "star", Name: "badges.star", Description: "badges.starDesc", Category: "social", Earned: stats.FollowerCount >= 50},
-
-		// Q&A badges (new)
-		{ID: "qa-first-answer", Name: "badges.qaFirstAnswer", Description: "badges.qaFirstAnswerDesc", Category: "qa", Earned: stats.QAAnswerCount >= 1},
-		{ID: "qa-helper", Name: "badges.qaHelper", Description: "badges.qaHelperDesc", Category: "qa", Earned: stats.QAAnswerCount >= 10},
-
-		// Goal badges (new)
-		{ID: "goal-achiever", Name: "badges.goalAchiever", Description: "badges.goalAchieverDesc", Category: "goal", Earned: stats.CompletedGoals >= 5},
-		{ID: "goal-master", Name: "badges.goalMaster", Description: "badges.goalMasterDesc", Category: "goal", Earned: stats.CompletedGoals >= 20},
-	}
-}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/norman6464/devsync/backend/internal/config"
+	"github.com/norman6464/devsync/backend/internal/model"
+)
+
+// BitbucketService talks to Bitbucket Cloud through API 2.0.
+type BitbucketService struct {
+	cfg    *config.Config
+	client *http.Client
+}
+
+func NewBitbucketService(cfg *config.Config, client *http.Client) *BitbucketService {
+	return &BitbucketService{cfg: cfg, client: client}
+}
+
+func (s *BitbucketService) Provider() model.IntegrationProvider {
+	return model.IntegrationBitbucket
+}
+
+func (s *BitbucketService) Enabled() bool {
+	return s.cfg.BitbucketClientID != ""
+}
+
+func (s *BitbucketService) OAuthURL(state string) string {
+	return fmt.Sprintf(
+		"%s/site/oauth2/authorize?client_id=%s&redirect_uri=%s&response_type=code&state=%s",
+		s.cfg.BitbucketBaseURL, s.cfg.BitbucketClientID, url.QueryEscape(s.cfg.BitbucketRedirectURL), state,
+	)
+}
+
+func (s *BitbucketService) ExchangeCode(code string) (*CodeHostToken, error) {
+	return s.requestToken(url.Values{
+		"grant_type":   {"authorization_code"},
+		"code":         {code},
+		"redirect_uri": {s.cfg.BitbucketRedirectURL},
+	})
+}
+
+func (s *BitbucketService) RefreshToken(refreshToken string) (*CodeHostToken, error) {
+	return s.requestToken(url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
+	})
+}
+
+func (s *BitbucketService) requestToken(form url.Values) (*CodeHostToken, error) {
+	req, err := http.NewRequest("POST", s.cfg.BitbucketBaseURL+"/site/oauth2/access_token", strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.SetBasicAuth(s.cfg.BitbucketClientID, s.cfg.BitbucketClientSecret)
+	return doOAuthTokenRequest(s.client, "bitbucket", req)
+}
+
+type bitbucketLinks struct {
+	HTML struct {
+		Href string `json:"href"`
+	} `json:"html"`
+	Avatar struct {
+		Href string `json:"href"`
+	} `json:"avatar"`
+}
+
+func (s *BitbucketService) GetAccount(accessToken string) (*CodeHostAccountInfo, error) {
+	var user struct {
+		UUID     string         `json:"uuid"`
+		Username string         `json:"username"`
+		Links    bitbucketLinks `json:"links"`
+	}
+	if _, err := codeHostGet(s.client, "bitbucket", s.cfg.BitbucketAPIURL+"/user", accessToken, &user); err != nil {
+		return nil, err
+	}
+	return &CodeHostAccountInfo{
+		ExternalID: user.UUID,
+		Username:   user.Username,
+		AvatarURL:  user.Links.Avatar.Href,
+		ProfileURL: user.Links.HTML.Href,
+	}, nil
+}
+
+func (s *BitbucketService) FetchRepos(accessToken string, account *model.CodeHostAccount) ([]CodeHostRepo, error) {
+	var repos []CodeHostRepo
+	next := s.cfg.BitbucketAPIURL + "/repositories?role=member&sort=-updated_on&pagelen=100"
+	for next != "" {
+		var page struct {
+			Values []struct {
+				UUID        string         `json:"uuid"`
+				Name        string         `json:"name"`
+				FullName    string         `json:"full_name"`
+				Description string         `json:"description"`
+				Language    string         `json:"language"`
+				IsPrivate   bool           `json:"is_private"`
+				Size        int64          `json:"size"`
+				UpdatedOn   time.Time      `json:"updated_on"`
+				Links       bitbucketLinks `json:"links"`
+			} `json:"values"`
+			Next string `json:"next"`
+		}
+		if _, err := codeHostGet(s.client, "bitbucket", next, accessToken, &page); err != nil {
+			return nil, err
+		}
+
+		for _, r := range page.Values {
+			repos = append(repos, CodeHostRepo{
+				CodeHostRepository: model.CodeHostRepository{
+					ExternalID:  r.UUID,
+					Name:        r.Name,
+					FullName:    r.FullName,
+					Description: r.Description,
+					Language:    r.Language,
+					IsPrivate:   r.IsPrivate,
+					URL:         r.Links.HTML.Href,
+				},
+				Size:     r.Size,
+				ActiveAt: r.UpdatedOn,
+			})
+		}
+		next = page.Next
+	}
+	return repos, nil
+}
+
+// FetchLanguages attributes the whole repository to its main language;
+// Bitbucket doesn't report a breakdown.
+func (s *BitbucketService) FetchLanguages(accessToken string, repo *CodeHostRepo) (map[string]int64, error) {
+	if repo.Language == "" {
+		return nil, nil
+	}
+	return map[string]int64{repo.Language: repo.Size}, nil
+}
+
+// FetchContributions counts the account's commits per day in the
+// repositories that changed since the given time.
+func (s *BitbucketService) FetchContributions(accessToken string, account *model.CodeHostAccount, repos []CodeHostRepo, since time.Time) (map[string]int, error) {
+	counts := make(map[string]int)
+	for _, repo := range repos {
+		if repo.ActiveAt.Before(since) {
+			continue
+		}
+
+		next := fmt.Sprintf("%s/repositories/%s/commits?pagelen=100", s.cfg.BitbucketAPIURL, repo.FullName)
+		for next != "" {
+			var page struct {
+				Values []struct {
+					Date   time.Time `json:"date"`
+					Author struct {
+						User struct {
+							UUID string `json:"uuid"`
+						} `json:"user"`
+					} `json:"author"`
+				} `json:"values"`
+				Next string `json:"next"`
+			}
+			if _, err := codeHostGet(s.client, "bitbucket", next, accessToken, &page); err != nil {
+				return nil, err
+			}
+
+			// Commits come newest first
+			next = page.Next
+			for _, c := range page.Values {
+				if c.Date.Before(since) {
+					next = ""
+					break
+				}
+				if c.Author.User.UUID == account.ExternalID {
+					counts[c.Date.UTC().Format("2006-01-02")]++
+				}
+			}
+		}
+	}
+	return counts, nil
+}
@@ -0,0 +1,298 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"hash/fnv"
+	"html"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/norman6464/devsync/backend/internal/model"
+	"gorm.io/gorm"
+)
+
+// CardTheme holds the colors used to render SVG profile cards.
+type CardTheme struct {
+	Background string
+	Border     string
+	Title      string
+	Text       string
+	Accent     string
+	// Levels are the heatmap colors from "no contributions" to "most".
+	Levels [5]string
+}
+
+var cardThemes = map[string]CardTheme{
+	"light": {
+		Background: "#ffffff", Border: "#e4e2e2", Title: "#2f80ed", Text: "#434d58", Accent: "#4c71f2",
+		Levels: [5]string{"#ebedf0", "#9be9a8", "#40c463", "#30a14e", "#216e39"},
+	},
+	"dark": {
+		Background: "#0d1117", Border: "#30363d", Title: "#58a6ff", Text: "#c9d1d9", Accent: "#79c0ff",
+		Levels: [5]string{"#161b22", "#0e4429", "#006d32", "#26a641", "#39d353"},
+	},
+	"dracula": {
+		Background: "#282a36", Border: "#44475a", Title: "#ff79c6", Text: "#f8f8f2", Accent: "#bd93f9",
+		Levels: [5]string{"#44475a", "#6272a4", "#8be9fd", "#50fa7b", "#ff79c6"},
+	},
+}
+
+var hexColorPattern = regexp.MustCompile(`^[0-9a-fA-F]{3}([0-9a-fA-F]{3})?$`)
+
+// ResolveCardTheme picks a named theme (defaulting to light) and applies any
+// hex color overrides, e.g. {"bg": "000000"}. Invalid colors are ignored.
+func ResolveCardTheme(name string, overrides map[string]string) CardTheme {
+	theme, ok := cardThemes[name]
+	if !ok {
+		theme = cardThemes["light"]
+	}
+	apply := func(dst *string, key string) {
+		if v := strings.TrimPrefix(overrides[key], "#"); hexColorPattern.MatchString(v) {
+			*dst = "#" + v
+		}
+	}
+	apply(&theme.Background, "bg")
+	apply(&theme.Border, "border")
+	apply(&theme.Title, "title")
+	apply(&theme.Text, "text")
+	apply(&theme.Accent, "accent")
+	return theme
+}
+
+// StreakStats summarizes a user's daily contribution streaks.
+type StreakStats struct {
+	CurrentStreak      int
+	LongestStreak      int
+	TotalContributions int
+}
+
+// GetStreakStats returns the current and longest streak built on calculateStreak.
+func GetStreakStats(db *gorm.DB, userID uint) (*StreakStats, error) {
+	current, err := calculateStreak(db, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var dates []time.Time
+	if err := db.Model(&model.Contribution{}).
+		Where("user_id = ? AND count > 0", userID).
+		Order("date ASC").
+		Pluck("date", &dates).Error; err != nil {
+		return nil, err
+	}
+
+	stats := &StreakStats{CurrentStreak: current}
+	run := 0
+	var prev time.Time
+	for i, d := range dates {
+		d = d.UTC().Truncate(24 * time.Hour)
+		if i > 0 && d.Sub(prev) == 24*time.Hour {
+			run++
+		} else {
+			run = 1
+		}
+		if run > stats.LongestStreak {
+			stats.LongestStreak = run
+		}
+		prev = d
+	}
+
+	db.Raw("SELECT COALESCE(SUM(count), 0) FROM contributions WHERE user_id = ?", userID).Scan(&stats.TotalContributions)
+	return stats, nil
+}
+
+const cardFont = `font-family="'Segoe UI', Ubuntu, 'Helvetica Neue', Sans-Serif"`
+
+func cardHeader(buf *bytes.Buffer, width, height int, title string, theme CardTheme) {
+	fmt.Fprintf(buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" role="img" aria-label="%s">`,
+		width, height, width, height, html.EscapeString(title))
+	fmt.Fprintf(buf, `<title>%s</title>`, html.EscapeString(title))
+	fmt.Fprintf(buf, `<rect x="0.5" y="0.5" rx="6" width="%d" height="%d" fill="%s" stroke="%s"/>`, width-1, height-1, theme.Background, theme.Border)
+	fmt.Fprintf(buf, `<text x="20" y="32" %s font-size="16" font-weight="600" fill="%s">%s</text>`, cardFont, theme.Title, html.EscapeString(title))
+}
+
+func cardDisplayName(user *model.User) string {
+	if user.Handle != "" {
+		return "@" + user.Handle
+	}
+	return user.Name
+}
+
+// RenderHeatmapCard draws the last 53 weeks of contributions as a GitHub-style grid.
+func RenderHeatmapCard(user *model.User, contributions []model.Contribution, theme CardTheme) []byte {
+	const cell, gap, weeks = 10, 3, 53
+	counts := make(map[string]int, len(contributions))
+	maxCount, total := 0, 0
+	for _, c := range contributions {
+		counts[c.Date.UTC().Format("2006-01-02")] = c.Count
+		if c.Count > maxCount {
+			maxCount = c.Count
+		}
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	// Start on the Sunday of the first week so columns line up with weeks
+	start := today.AddDate(0, 0, -(weeks-1)*7-int(today.Weekday()))
+
+	width := 40 + weeks*(cell+gap)
+	height := 60 + 7*(cell+gap) + 24
+	var buf bytes.Buffer
+	cardHeader(&buf, width, height, cardDisplayName(user)+" · DevSync contributions", theme)
+
+	fmt.Fprintf(&buf, `<g transform="translate(20, 50)">`)
+	for d := start; !d.After(today); d = d.AddDate(0, 0, 1) {
+		week := int(d.Sub(start).Hours() / 24 / 7)
+		count := counts[d.Format("2006-01-02")]
+		total += count
+		fmt.Fprintf(&buf, `<rect x="%d" y="%d" width="%d" height="%d" rx="2" fill="%s"><title>%s: %d</title></rect>`,
+			week*(cell+gap), int(d.Weekday())*(cell+gap), cell, cell, theme.Levels[heatmapLevel(count, maxCount)], d.Format("2006-01-02"), count)
+	}
+	buf.WriteString(`</g>`)
+	fmt.Fprintf(&buf, `<text x="20" y="%d" %s font-size="12" fill="%s">%d contributions in the last year</text>`,
+		height-14, cardFont, theme.Text, total)
+	buf.WriteString(`</svg>`)
+	return buf.Bytes()
+}
+
+func heatmapLevel(count, maxCount int) int {
+	if count <= 0 || maxCount <= 0 {
+		return 0
+	}
+	level := 1 + (count*4-1)/maxCount
+	if level > 4 {
+		level = 4
+	}
+	return level
+}
+
+var languageColors = map[string]string{
+	"Go": "#00ADD8", "TypeScript": "#3178c6", "JavaScript": "#f1e05a", "Python": "#3572A5",
+	"Java": "#b07219", "Kotlin": "#A97BFF", "Swift": "#F05138", "Rust": "#dea584",
+	"Ruby": "#701516", "PHP": "#4F5D95", "C": "#555555", "C++": "#f34b7d", "C#": "#178600",
+	"HTML": "#e34c26", "CSS": "#563d7c", "SCSS": "#c6538c", "Shell": "#89e051", "Dart": "#00B4AB",
+	"Vue": "#41b883", "Scala": "#c22d40", "Elixir": "#6e4a7e", "Haskell": "#5e5086",
+	"Dockerfile": "#384d54", "HCL": "#844FBA", "Lua": "#000080", "R": "#198CE7",
+}
+
+func languageColor(lang string) string {
+	if c, ok := languageColors[lang]; ok {
+		return c
+	}
+	h := fnv.New32a()
+	h.Write([]byte(lang))
+	return fmt.Sprintf("#%06x", h.Sum32()&0xffffff)
+}
+
+// RenderLanguagesCard draws a stacked bar and legend of the top languages by bytes.
+func RenderLanguagesCard(user *model.User, stats []model.GitHubLanguageStat, limit int, theme CardTheme) []byte {
+	if len(stats) > limit {
+		stats = stats[:limit]
+	}
+	var total int64
+	for _, s := range stats {
+		total += s.Bytes
+	}
+
+	const width, barWidth = 350, 310
+	rows := (len(stats) + 1) / 2
+	height := 90 + rows*22
+	var buf bytes.Buffer
+	cardHeader(&buf, width, height, cardDisplayName(user)+" · Top languages", theme)
+
+	if total == 0 {
+		fmt.Fprintf(&buf, `<text x="20" y="62" %s font-size="12" fill="%s">No language data yet</text></svg>`, cardFont, theme.Text)
+		return buf.Bytes()
+	}
+
+	buf.WriteString(`<g transform="translate(20, 48)">`)
+	x := 0.0
+	for _, s := range stats {
+		w := float64(barWidth) * float64(s.Bytes) / float64(total)
+		fmt.Fprintf(&buf, `<rect x="%.2f" y="0" width="%.2f" height="8" fill="%s"/>`, x, w, languageColor(s.Language))
+		x += w
+	}
+	for i, s := range stats {
+		lx, ly := (i%2)*160, 30+(i/2)*22
+		fmt.Fprintf(&buf, `<circle cx="%d" cy="%d" r="5" fill="%s"/>`, lx+5, ly-4, languageColor(s.Language))
+		fmt.Fprintf(&buf, `<text x="%d" y="%d" %s font-size="12" fill="%s">%s %.1f%%</text>`,
+			lx+15, ly, cardFont, theme.Text, html.EscapeString(s.Language), float64(s.Bytes)*100/float64(total))
+	}
+	buf.WriteString(`</g></svg>`)
+	return buf.Bytes()
+}
+
+// RenderBadgesCard draws a shelf of the badges a user has earned.
+func RenderBadgesCard(user *model.User, badges []BadgeResult, theme CardTheme) []byte {
+	var earned []BadgeResult
+	for _, b := range badges {
+		if b.Earned {
+			earned = append(earned, b)
+		}
+	}
+
+	const width, perRow = 480, 3
+	rows := (len(earned) + perRow - 1) / perRow
+	if rows == 0 {
+		rows = 1
+	}
+	height := 60 + rows*34
+	var buf bytes.Buffer
+	cardHeader(&buf, width, height, fmt.Sprintf("%s · %d badges", cardDisplayName(user), len(earned)), theme)
+
+	if len(earned) == 0 {
+		fmt.Fprintf(&buf, `<text x="20" y="66" %s font-size="12" fill="%s">No badges earned yet</text></svg>`, cardFont, theme.Text)
+		return buf.Bytes()
+	}
+
+	for i, b := range earned {
+		x, y := 20+(i%perRow)*148, 48+(i/perRow)*34
+		fmt.Fprintf(&buf, `<rect x="%d" y="%d" width="140" height="26" rx="13" fill="none" stroke="%s"/>`, x, y, theme.Accent)
+		fmt.Fprintf(&buf, `<text x="%d" y="%d" %s font-size="11" text-anchor="middle" fill="%s">%s</text>`,
+			x+70, y+17, cardFont, theme.Text, html.EscapeString(badgeLabel(b.ID)))
+	}
+	buf.WriteString(`</svg>`)
+	return buf.Bytes()
+}
+
+// badgeLabel turns a badge ID like "week-streak" into "Week Streak", since
+// badge names are i18n keys resolved by the frontend.
+func badgeLabel(id string) string {
+	words := strings.Split(id, "-")
+	for i, w := range words {
+		if w == "qa" {
+			words[i] = "Q&A"
+		} else if w != "" {
+			words[i] = strings.ToUpper(w[:1]) + w[1:]
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// RenderStreakCard draws the current streak alongside the longest streak and total contributions.
+func RenderStreakCard(user *model.User, stats *StreakStats, theme CardTheme) []byte {
+	const width, height = 480, 140
+	var buf bytes.Buffer
+	cardHeader(&buf, width, height, cardDisplayName(user)+" · Streak", theme)
+
+	columns := []struct {
+		label string
+		value int
+	}{
+		{"Total contributions", stats.TotalContributions},
+		{"Current streak", stats.CurrentStreak},
+		{"Longest streak", stats.LongestStreak},
+	}
+	for i, col := range columns {
+		x := 80 + i*160
+		color := theme.Text
+		if i == 1 {
+			color = theme.Accent
+		}
+		fmt.Fprintf(&buf, `<text x="%d" y="86" %s font-size="28" font-weight="700" text-anchor="middle" fill="%s">%d</text>`, x, cardFont, color, col.value)
+		fmt.Fprintf(&buf, `<text x="%d" y="112" %s font-size="12" text-anchor="middle" fill="%s">%s</text>`, x, cardFont, theme.Text, col.label)
+	}
+	buf.WriteString(`</svg>`)
+	return buf.Bytes()
+}
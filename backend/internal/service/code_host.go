@@ -0,0 +1,416 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/norman6464/devsync/backend/internal/model"
+	"github.com/norman6464/devsync/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrUnknownCodeHost       = errors.New("unknown code host")
+	ErrCodeHostNotConfigured = errors.New("code host is not configured")
+	// ErrCodeHostAccountTaken is returned when the account is already
+	// connected to another user
+	ErrCodeHostAccountTaken = errors.New("account is connected to another user")
+)
+
+const (
+	// codeHostLanguageRepos caps the repositories whose languages are looked
+	// up per sync, most recently active first
+	codeHostLanguageRepos = 100
+	// codeHostContributionDays is how far back the first sync counts
+	// contributions, matching GitHub's calendar
+	codeHostContributionDays = 365
+)
+
+// CodeHost is a code hosting service, such as GitLab or Bitbucket, whose
+// accounts users can connect to import their repositories, languages and
+// daily contribution counts. GitHub has its own richer integration in
+// GitHubService; the data from every host is merged when read.
+type CodeHost interface {
+	Provider() model.IntegrationProvider
+	// Enabled reports whether an OAuth application is configured
+	Enabled() bool
+	OAuthURL(state string) string
+	ExchangeCode(code string) (*CodeHostToken, error)
+	RefreshToken(refreshToken string) (*CodeHostToken, error)
+	GetAccount(accessToken string) (*CodeHostAccountInfo, error)
+	// FetchRepos lists the repositories the account is a member of, most
+	// recently active first
+	FetchRepos(accessToken string, account *model.CodeHostAccount) ([]CodeHostRepo, error)
+	// FetchLanguages returns the approximate bytes of each language in repo
+	FetchLanguages(accessToken string, repo *CodeHostRepo) (map[string]int64, error)
+	// FetchContributions counts the account's contributions per day
+	// ("2006-01-02") since the given time
+	FetchContributions(accessToken string, account *model.CodeHostAccount, repos []CodeHostRepo, since time.Time) (map[string]int, error)
+}
+
+// CodeHostToken is the result of an OAuth code exchange or token refresh.
+type CodeHostToken struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	// ExpiresIn is the access token's lifetime in seconds; 0 if it doesn't expire
+	ExpiresIn int `json:"expires_in"`
+}
+
+// CodeHostAccountInfo identifies the account a token belongs to.
+type CodeHostAccountInfo struct {
+	ExternalID string
+	Username   string
+	AvatarURL  string
+	ProfileURL string
+}
+
+// CodeHostRepo is a repository as listed by a code host.
+type CodeHostRepo struct {
+	model.CodeHostRepository
+	// Size is the repository size in bytes, if the host reports it
+	Size int64
+	// ActiveAt is when the repository last changed
+	ActiveAt time.Time
+}
+
+// CodeHostService connects code host accounts and syncs their data.
+type CodeHostService struct {
+	repo   *repository.CodeHostRepository
+	tokens *TokenCipher
+	hosts  map[model.IntegrationProvider]CodeHost
+}
+
+// NewCodeHostService creates the service. OAuth tokens are stored encrypted
+// with tokens.
+func NewCodeHostService(repo *repository.CodeHostRepository, tokens *TokenCipher, hosts ...CodeHost) *CodeHostService {
+	s := &CodeHostService{repo: repo, tokens: tokens, hosts: make(map[model.IntegrationProvider]CodeHost)}
+	for _, host := range hosts {
+		s.hosts[host.Provider()] = host
+	}
+	return s
+}
+
+// Host returns the code host for provider if it is configured.
+func (s *CodeHostService) Host(provider model.IntegrationProvider) (CodeHost, error) {
+	host, ok := s.hosts[provider]
+	if !ok {
+		return nil, ErrUnknownCodeHost
+	}
+	if !host.Enabled() {
+		return nil, ErrCodeHostNotConfigured
+	}
+	return host, nil
+}
+
+// Connect completes the OAuth flow and stores the account for the user.
+func (s *CodeHostService) Connect(userID uint, provider model.IntegrationProvider, code string) (*model.CodeHostAccount, error) {
+	host, err := s.Host(provider)
+	if err != nil {
+		return nil, err
+	}
+	token, err := host.ExchangeCode(code)
+	if err != nil {
+		return nil, err
+	}
+	info, err := host.GetAccount(token.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.FindAccountByExternalID(provider, info.ExternalID)
+	if err == nil && existing.UserID != userID {
+		return nil, ErrCodeHostAccountTaken
+	} else if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	accessToken, refreshToken, err := s.encryptTokens(token.AccessToken, token.RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+	account := &model.CodeHostAccount{
+		UserID:          userID,
+		Provider:        provider,
+		ExternalID:      info.ExternalID,
+		Username:        info.Username,
+		AvatarURL:       info.AvatarURL,
+		ProfileURL:      info.ProfileURL,
+		AccessToken:     accessToken,
+		RefreshToken:    refreshToken,
+		TokenExpiresAt:  tokenExpiry(token),
+		TokensEncrypted: true,
+	}
+	if err := s.repo.SaveAccount(account); err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+// Connected reports whether the user has an account on provider.
+func (s *CodeHostService) Connected(provider model.IntegrationProvider, userID uint) bool {
+	_, err := s.repo.FindAccount(userID, provider)
+	return err == nil
+}
+
+// Disconnect removes the user's account on provider and its synced data.
+func (s *CodeHostService) Disconnect(provider model.IntegrationProvider, userID uint) error {
+	return s.repo.DeleteUserData(userID, provider)
+}
+
+// Sync refreshes the repositories, language stats and contributions of the
+// user's account on provider.
+func (s *CodeHostService) Sync(provider model.IntegrationProvider, userID uint) error {
+	host, err := s.Host(provider)
+	if err != nil {
+		return err
+	}
+	account, err := s.repo.FindAccount(userID, provider)
+	if err != nil {
+		return err
+	}
+	token, err := s.accessToken(host, account)
+	if err != nil {
+		return fmt.Errorf("refresh token: %w", err)
+	}
+
+	repos, err := host.FetchRepos(token, account)
+	if err != nil {
+		return fmt.Errorf("fetch repos: %w", err)
+	}
+	if err := s.syncReposAndLanguages(host, token, account, repos); err != nil {
+		return err
+	}
+	if err := s.syncContributions(host, token, account, repos); err != nil {
+		return fmt.Errorf("fetch contributions: %w", err)
+	}
+	return nil
+}
+
+// accessToken returns a usable access token, refreshing an expired one.
+// Tokens stored before encryption are encrypted along the way.
+func (s *CodeHostService) accessToken(host CodeHost, account *model.CodeHostAccount) (string, error) {
+	accessToken, refreshToken := account.AccessToken, account.RefreshToken
+	if account.TokensEncrypted {
+		var err error
+		if accessToken, refreshToken, err = s.decryptTokens(accessToken, refreshToken); err != nil {
+			return "", err
+		}
+	}
+
+	if account.TokenExpiresAt == nil || time.Now().Add(time.Minute).Before(*account.TokenExpiresAt) || refreshToken == "" {
+		if !account.TokensEncrypted {
+			if err := s.saveTokens(account, accessToken, refreshToken, account.TokenExpiresAt); err != nil {
+				return "", err
+			}
+		}
+		return accessToken, nil
+	}
+	token, err := host.RefreshToken(refreshToken)
+	if err != nil {
+		return "", err
+	}
+	if token.RefreshToken == "" {
+		token.RefreshToken = refreshToken
+	}
+	if err := s.saveTokens(account, token.AccessToken, token.RefreshToken, tokenExpiry(token)); err != nil {
+		return "", err
+	}
+	return token.AccessToken, nil
+}
+
+// saveTokens encrypts and stores the account's tokens.
+func (s *CodeHostService) saveTokens(account *model.CodeHostAccount, accessToken, refreshToken string, expiresAt *time.Time) error {
+	accessToken, refreshToken, err := s.encryptTokens(accessToken, refreshToken)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.UpdateTokens(account.ID, accessToken, refreshToken, expiresAt); err != nil {
+		return err
+	}
+	account.AccessToken, account.RefreshToken, account.TokenExpiresAt = accessToken, refreshToken, expiresAt
+	account.TokensEncrypted = true
+	return nil
+}
+
+// encryptTokens encrypts an access and refresh token pair. A missing refresh
+// token stays empty.
+func (s *CodeHostService) encryptTokens(accessToken, refreshToken string) (string, string, error) {
+	accessToken, err := s.tokens.Encrypt(accessToken)
+	if err != nil || refreshToken == "" {
+		return accessToken, refreshToken, err
+	}
+	refreshToken, err = s.tokens.Encrypt(refreshToken)
+	return accessToken, refreshToken, err
+}
+
+func (s *CodeHostService) decryptTokens(accessToken, refreshToken string) (string, string, error) {
+	accessToken, err := s.tokens.Decrypt(accessToken)
+	if err != nil || refreshToken == "" {
+		return accessToken, refreshToken, err
+	}
+	refreshToken, err = s.tokens.Decrypt(refreshToken)
+	return accessToken, refreshToken, err
+}
+
+func (s *CodeHostService) syncReposAndLanguages(host CodeHost, token string, account *model.CodeHostAccount, repos []CodeHostRepo) error {
+	langMap := make(map[string]*model.CodeHostLanguageStat)
+	stat := func(lang string) *model.CodeHostLanguageStat {
+		if langMap[lang] == nil {
+			langMap[lang] = &model.CodeHostLanguageStat{UserID: account.UserID, Provider: account.Provider, Language: lang}
+		}
+		return langMap[lang]
+	}
+
+	modelRepos := make([]model.CodeHostRepository, 0, len(repos))
+	for i := range repos {
+		repo := &repos[i]
+		if i < codeHostLanguageRepos {
+			langs, err := host.FetchLanguages(token, repo)
+			if err != nil {
+				return fmt.Errorf("fetch languages of %s: %w", repo.FullName, err)
+			}
+			for lang, bytes := range langs {
+				stat(lang).Bytes += bytes
+			}
+			if repo.Language == "" {
+				repo.Language = primaryLanguage(langs)
+			}
+		}
+		if repo.Language != "" {
+			stat(repo.Language).RepoCount++
+		}
+
+		r := repo.CodeHostRepository
+		r.UserID = account.UserID
+		r.Provider = account.Provider
+		r.UpdatedAt = time.Now()
+		modelRepos = append(modelRepos, r)
+	}
+
+	if err := s.repo.ReplaceRepos(account.UserID, account.Provider, modelRepos); err != nil {
+		return err
+	}
+	stats := make([]model.CodeHostLanguageStat, 0, len(langMap))
+	for _, st := range langMap {
+		st.UpdatedAt = time.Now()
+		stats = append(stats, *st)
+	}
+	return s.repo.ReplaceLanguageStats(account.UserID, account.Provider, stats)
+}
+
+// syncContributions recounts contributions from the last stored day onwards,
+// or for the past year on the first sync.
+func (s *CodeHostService) syncContributions(host CodeHost, token string, account *model.CodeHostAccount, repos []CodeHostRepo) error {
+	since := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -codeHostContributionDays)
+	latest, err := s.repo.LatestContributionDate(account.UserID, account.Provider)
+	if err != nil {
+		return err
+	}
+	if latest != nil && latest.After(since) {
+		since = latest.UTC().Truncate(24 * time.Hour)
+	}
+
+	counts, err := host.FetchContributions(token, account, repos, since)
+	if err != nil {
+		return err
+	}
+	contributions := make([]model.CodeHostContribution, 0, len(counts))
+	for day, count := range counts {
+		date, err := time.Parse("2006-01-02", day)
+		if err != nil || date.Before(since) || count == 0 {
+			continue
+		}
+		contributions = append(contributions, model.CodeHostContribution{
+			UserID:   account.UserID,
+			Provider: account.Provider,
+			Date:     date,
+			Count:    count,
+		})
+	}
+	return s.repo.ReplaceContributions(account.UserID, account.Provider, since, contributions)
+}
+
+func primaryLanguage(langs map[string]int64) string {
+	names := make([]string, 0, len(langs))
+	for lang := range langs {
+		names = append(names, lang)
+	}
+	// Largest first, by name on ties so the result is stable
+	sort.Slice(names, func(i, j int) bool {
+		if langs[names[i]] != langs[names[j]] {
+			return langs[names[i]] > langs[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	if len(names) == 0 {
+		return ""
+	}
+	return names[0]
+}
+
+func tokenExpiry(token *CodeHostToken) *time.Time {
+	if token.ExpiresIn <= 0 {
+		return nil
+	}
+	t := time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	return &t
+}
+
+// doOAuthTokenRequest sends a token request and decodes the tokens, turning
+// OAuth error responses into errors.
+func doOAuthTokenRequest(client *http.Client, provider string, req *http.Request) (*CodeHostToken, error) {
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		CodeHostToken
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("%s oauth: %w", provider, err)
+	}
+	if result.Error != "" {
+		return nil, fmt.Errorf("%s oauth error: %s %s", provider, result.Error, result.ErrorDescription)
+	}
+	if result.AccessToken == "" {
+		return nil, fmt.Errorf("%s oauth: no access token (status %d)", provider, resp.StatusCode)
+	}
+	return &result.CodeHostToken, nil
+}
+
+// codeHostGet fetches url with the user's token and decodes the JSON response
+// into v. Code host rate limits apply per user, so exhausting one doesn't
+// pause other accounts.
+func codeHostGet(client *http.Client, provider, url, token string, v interface{}) (*http.Response, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := checkRateLimit(provider, resp); err != nil {
+		err.(*RateLimitError).TokenScoped = true
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return resp, fmt.Errorf("%s request failed with status %d", provider, resp.StatusCode)
+	}
+	return resp, json.NewDecoder(resp.Body).Decode(v)
+}
@@ -0,0 +1,320 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/norman6464/devsync/backend/internal/model"
+	"github.com/norman6464/devsync/backend/internal/queue"
+	"github.com/norman6464/devsync/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+// DataExportLinkTTL is how long a finished export can be downloaded.
+const DataExportLinkTTL = 7 * 24 * time.Hour
+
+var uploadPathPattern = regexp.MustCompile(`/uploads/[A-Za-z0-9/_.-]+`)
+
+// DataExportService builds "takeout" archives: a ZIP of JSON files with
+// everything tied to a user, plus the images they uploaded.
+type DataExportService struct {
+	db         *gorm.DB
+	exportRepo *repository.DataExportRepository
+	exportDir  string
+	uploadDir  string
+	jobs       *queue.Queue
+}
+
+type dataExportJob struct {
+	ExportID uint `json:"export_id"`
+}
+
+func NewDataExportService(db *gorm.DB, exportRepo *repository.DataExportRepository, exportDir, uploadDir string, jobs *queue.Queue) *DataExportService {
+	s := &DataExportService{db: db, exportRepo: exportRepo, exportDir: exportDir, uploadDir: uploadDir, jobs: jobs}
+	jobs.Register(queue.JobType{Name: JobDataExportBuild, Queue: "exports", Handler: s.run, MaxAttempts: 3})
+	jobs.Register(queue.JobType{Name: JobDataExportPurge, Handler: func(ctx context.Context, _ json.RawMessage) error {
+		s.PurgeExpired()
+		return nil
+	}})
+	return s
+}
+
+// Request queues a new export for the user. If one is already in progress it
+// is returned instead of starting another.
+func (s *DataExportService) Request(userID uint) (*model.DataExport, error) {
+	if active, err := s.exportRepo.FindActiveByUserID(userID); err == nil {
+		return active, nil
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	export := &model.DataExport{
+		UserID: userID,
+		Status: model.DataExportStatusPending,
+		Token:  uuid.New().String(),
+	}
+	if err := s.exportRepo.Create(export); err != nil {
+		return nil, err
+	}
+
+	if err := s.jobs.Enqueue(JobDataExportBuild, dataExportJob{ExportID: export.ID}); err != nil {
+		export.Status = model.DataExportStatusFailed
+		export.Error = "failed to queue export"
+		s.exportRepo.Update(export)
+		return nil, err
+	}
+	return export, nil
+}
+
+// PurgeExpired removes archives whose download link has lapsed.
+func (s *DataExportService) PurgeExpired() {
+	exports, err := s.exportRepo.FindExpired(time.Now())
+	if err != nil {
+		log.Printf("data export: failed to list expired exports: %v", err)
+		return
+	}
+	for i := range exports {
+		if exports[i].FilePath != "" {
+			if err := os.Remove(exports[i].FilePath); err != nil && !os.IsNotExist(err) {
+				log.Printf("data export: failed to remove %s: %v", exports[i].FilePath, err)
+				continue
+			}
+		}
+		exports[i].Status = model.DataExportStatusExpired
+		exports[i].FilePath = ""
+		s.exportRepo.Update(&exports[i])
+	}
+}
+
+func (s *DataExportService) run(ctx context.Context, payload json.RawMessage) error {
+	var job dataExportJob
+	if err := queue.Decode(payload, &job); err != nil {
+		return err
+	}
+	export, err := s.exportRepo.FindByID(job.ExportID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// The account was deleted in the meantime
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	export.Status = model.DataExportStatusProcessing
+	s.exportRepo.Update(export)
+
+	path, size, err := s.build(export.UserID, export.Token)
+	if err != nil {
+		// Until the queue gives up, the export stays pending for the next attempt
+		export.Status = model.DataExportStatusPending
+		if queue.IsFinalAttempt(ctx) {
+			export.Status = model.DataExportStatusFailed
+			export.Error = "failed to build export"
+		}
+		s.exportRepo.Update(export)
+		return err
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(DataExportLinkTTL)
+	export.Status = model.DataExportStatusCompleted
+	export.FilePath = path
+	export.SizeBytes = size
+	export.CompletedAt = &now
+	export.ExpiresAt = &expiresAt
+	return s.exportRepo.Update(export)
+}
+
+// exportSection is one JSON file in the archive and the query that fills it.
+type exportSection struct {
+	file  string
+	dest  interface{}
+	query func(tx *gorm.DB) *gorm.DB
+	// authored marks content written by the user, which is scanned for
+	// uploaded image paths to include in the archive.
+	authored bool
+}
+
+func (s *DataExportService) sections(userID uint) []exportSection {
+	byUser := func(tx *gorm.DB) *gorm.DB { return tx.Where("user_id = ?", userID) }
+	return []exportSection{
+		{"posts.json", &[]model.Post{}, byUser, true},
+		{"comments.json", &[]model.Comment{}, byUser, true},
+		{"likes.json", &[]model.Like{}, byUser, false},
+		{"follows.json", &[]model.Follow{}, func(tx *gorm.DB) *gorm.DB {
+			return tx.Where("follower_id = ? OR followee_id = ?", userID, userID)
+		}, false},
+		{"messages.json", &[]model.Message{}, func(tx *gorm.DB) *gorm.DB {
+			return tx.Where("sender_id = ? OR receiver_id = ?", userID, userID).Order("created_at ASC")
+		}, false},
+		{"chat_rooms.json", &[]model.ChatRoom{}, func(tx *gorm.DB) *gorm.DB {
+			return tx.Where("id IN (?)", s.db.Model(&model.ChatRoomMember{}).Select("chat_room_id").Where("user_id = ?", userID))
+		}, false},
+		{"chat_room_memberships.json", &[]model.ChatRoomMember{}, byUser, false},
+		{"teams.json", &[]model.Team{}, func(tx *gorm.DB) *gorm.DB {
+			return tx.Where("id IN (?)", s.db.Model(&model.TeamMember{}).Select("team_id").Where("user_id = ?", userID))
+		}, false},
+		{"team_memberships.json", &[]model.TeamMember{}, byUser, false},
+		{"room_messages.json", &[]model.GroupMessage{}, func(tx *gorm.DB) *gorm.DB {
+			return tx.Where("sender_id = ?", userID).Order("created_at ASC")
+		}, true},
+		{"learning_goals.json", &[]model.LearningGoal{}, byUser, false},
+		{"roadmaps.json", &[]model.Roadmap{}, func(tx *gorm.DB) *gorm.DB {
+			return tx.Preload("Steps").Where("user_id = ?", userID)
+		}, true},
+		{"projects.json", &[]model.Project{}, byUser, true},
+		{"learning_resources.json", &[]model.LearningResource{}, byUser, true},
+		{"resource_likes.json", &[]model.ResourceLike{}, byUser, false},
+		{"resource_saves.json", &[]model.ResourceSave{}, byUser, false},
+		{"book_reviews.json", &[]model.BookReview{}, byUser, true},
+		{"questions.json", &[]model.Question{}, byUser, true},
+		{"answers.json", &[]model.Answer{}, byUser, true},
+		{"question_votes.json", &[]model.QuestionVote{}, byUser, false},
+		{"answer_votes.json", &[]model.AnswerVote{}, byUser, false},
+		{"notifications.json", &[]model.Notification{}, byUser, false},
+		{"badges.json", &[]model.UserBadge{}, byUser, false},
+		{"mentions.json", &[]model.Mention{}, byUser, false},
+		{"handle_history.json", &[]model.HandleHistory{}, byUser, false},
+		{"github/contributions.json", &[]model.GitHubContribution{}, byUser, false},
+		{"github/contribution_years.json", &[]model.GitHubContributionYear{}, byUser, false},
+		{"github/languages.json", &[]model.GitHubLanguageStat{}, byUser, false},
+		{"github/repositories.json", &[]model.GitHubRepository{}, byUser, false},
+		{"github/activity.json", &[]model.GitHubActivity{}, byUser, false},
+		{"github/pull_requests.json", &[]model.GitHubPullRequest{}, byUser, false},
+		{"github/issues.json", &[]model.GitHubIssue{}, byUser, false},
+		{"github/reviews.json", &[]model.GitHubReview{}, byUser, false},
+		{"code_hosts/accounts.json", &[]model.CodeHostAccount{}, byUser, false},
+		{"code_hosts/repositories.json", &[]model.CodeHostRepository{}, byUser, false},
+		{"code_hosts/languages.json", &[]model.CodeHostLanguageStat{}, byUser, false},
+		{"code_hosts/contributions.json", &[]model.CodeHostContribution{}, byUser, false},
+		{"feeds/feeds.json", &[]model.ArticleFeed{}, byUser, false},
+		{"articles.json", &[]model.ExternalArticle{}, byUser, false},
+		{"article_engagement.json", &[]model.ArticleEngagementSnapshot{}, byUser, false},
+		{"leaderboard_history.json", &[]model.LeaderboardEntry{}, func(tx *gorm.DB) *gorm.DB {
+			return tx.Preload("Snapshot").Where("user_id = ?", userID)
+		}, false},
+	}
+}
+
+// build writes the archive to a temporary file and moves it into place once
+// complete, so a half-written ZIP is never served.
+func (s *DataExportService) build(userID uint, token string) (string, int64, error) {
+	if err := os.MkdirAll(s.exportDir, 0700); err != nil {
+		return "", 0, err
+	}
+	path := filepath.Join(s.exportDir, token+".zip")
+	tmp, err := os.CreateTemp(s.exportDir, token+"-*.tmp")
+	if err != nil {
+		return "", 0, err
+	}
+	defer os.Remove(tmp.Name())
+
+	zw := zip.NewWriter(tmp)
+	images := make(map[string]bool)
+
+	var user model.User
+	if err := s.db.First(&user, userID).Error; err != nil {
+		tmp.Close()
+		return "", 0, err
+	}
+	var privacy model.PrivacySettings
+	s.db.Where("user_id = ?", userID).Limit(1).Find(&privacy)
+	profile := exportProfile{User: user, PrivacySettings: privacy}
+	if err := writeZipJSON(zw, "profile.json", profile, images); err != nil {
+		tmp.Close()
+		return "", 0, err
+	}
+
+	for _, section := range s.sections(userID) {
+		if err := section.query(s.db.Model(section.dest)).Find(section.dest).Error; err != nil {
+			tmp.Close()
+			return "", 0, fmt.Errorf("%s: %w", section.file, err)
+		}
+		collect := images
+		if !section.authored {
+			collect = nil
+		}
+		if err := writeZipJSON(zw, section.file, section.dest, collect); err != nil {
+			tmp.Close()
+			return "", 0, err
+		}
+	}
+
+	for urlPath := range images {
+		if err := s.addUpload(zw, urlPath); err != nil {
+			log.Printf("data export: skipping %s: %v", urlPath, err)
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		tmp.Close()
+		return "", 0, err
+	}
+	info, err := tmp.Stat()
+	if err != nil {
+		tmp.Close()
+		return "", 0, err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", 0, err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", 0, err
+	}
+	return path, info.Size(), nil
+}
+
+type exportProfile struct {
+	User            model.User            `json:"user"`
+	PrivacySettings model.PrivacySettings `json:"privacy_settings"`
+}
+
+// writeZipJSON adds v as an indented JSON file. If images is non-nil, any
+// /uploads/ paths referenced by the data are recorded in it.
+func writeZipJSON(zw *zip.Writer, name string, v interface{}, images map[string]bool) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	if images != nil {
+		for _, p := range uploadPathPattern.FindAllString(string(data), -1) {
+			images[p] = true
+		}
+	}
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// addUpload copies an uploaded image into the archive under images/.
+func (s *DataExportService) addUpload(zw *zip.Writer, urlPath string) error {
+	rel := filepath.Clean(strings.TrimPrefix(urlPath, "/uploads/"))
+	if rel == "." || strings.HasPrefix(rel, "..") {
+		return fmt.Errorf("invalid upload path")
+	}
+	f, err := os.Open(filepath.Join(s.uploadDir, rel))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w, err := zw.Create("images/" + filepath.ToSlash(rel))
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, f)
+	return err
+}
@@ -0,0 +1,512 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"syscall"
+	"time"
+	"unicode/utf8"
+
+	"github.com/norman6464/devsync/backend/internal/config"
+	"github.com/norman6464/devsync/backend/internal/model"
+	"github.com/norman6464/devsync/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+const (
+	maxFeedsPerUser = 10
+	// maxFeedBytes caps how much of a feed document is read
+	maxFeedBytes = 5 << 20
+	// maxFeedSummary caps the length of an entry's plain-text summary, in runes
+	maxFeedSummary = 300
+)
+
+var (
+	ErrInvalidFeedURL     = errors.New("feed URL must be an http or https URL")
+	ErrFeedExists         = errors.New("feed is already registered")
+	ErrFeedLimitReached   = fmt.Errorf("at most %d feeds can be registered", maxFeedsPerUser)
+	ErrUnsupportedFeed    = errors.New("not an RSS, Atom or JSON feed")
+	ErrFeedPrivateAddress = errors.New("feed host resolves to a private address")
+)
+
+var (
+	htmlTagPattern    = regexp.MustCompile(`<[^>]*>`)
+	whitespacePattern = regexp.MustCompile(`\s+`)
+)
+
+// feedDateLayouts are the date formats found in the wild, RFC 822 variants
+// for RSS and RFC 3339 for Atom and JSON Feed.
+var feedDateLayouts = []string{
+	time.RFC1123Z,
+	time.RFC1123,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"Mon, 02 Jan 2006 15:04 -0700",
+	"2 Jan 2006 15:04:05 -0700",
+	time.RFC822Z,
+	time.RFC822,
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+}
+
+// FeedService reads RSS, Atom and JSON Feeds that users publish articles
+// through.
+type FeedService struct {
+	feedRepo    *repository.FeedRepository
+	articleRepo *repository.ArticleRepository
+	client      *http.Client
+}
+
+// NewFeedService creates the service. Feed URLs are supplied by users, so
+// unless cfg allows it, hosts that resolve to private or loopback addresses
+// are refused.
+func NewFeedService(cfg *config.Config, feedRepo *repository.FeedRepository, articleRepo *repository.ArticleRepository) *FeedService {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if !cfg.FeedAllowPrivateHosts {
+		dialer := &net.Dialer{Timeout: 10 * time.Second, Control: publicAddressOnly}
+		transport.DialContext = dialer.DialContext
+	}
+	return &FeedService{
+		feedRepo:    feedRepo,
+		articleRepo: articleRepo,
+		client: &http.Client{
+			Timeout:   20 * time.Second,
+			Transport: &retryTransport{next: transport, maxRetries: 1, baseDelay: 500 * time.Millisecond},
+		},
+	}
+}
+
+func publicAddressOnly(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !ip.IsGlobalUnicast() || ip.IsPrivate() {
+		return ErrFeedPrivateAddress
+	}
+	return nil
+}
+
+// Connected reports whether the user has registered any feeds.
+func (s *FeedService) Connected(userID uint) bool {
+	count, err := s.feedRepo.CountByUserID(userID)
+	return err == nil && count > 0
+}
+
+// AddFeed registers a feed for the user after checking that it can be read,
+// and stores its entries. It returns the feed and the number of entries.
+func (s *FeedService) AddFeed(userID uint, rawURL string) (*model.ArticleFeed, int, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, 0, ErrInvalidFeedURL
+	}
+
+	feeds, err := s.feedRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(feeds) >= maxFeedsPerUser {
+		return nil, 0, ErrFeedLimitReached
+	}
+	for _, f := range feeds {
+		if f.URL == u.String() {
+			return nil, 0, ErrFeedExists
+		}
+	}
+
+	feed := &model.ArticleFeed{UserID: userID, URL: u.String()}
+	parsed, err := s.fetch(feed)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := s.feedRepo.Create(feed); err != nil {
+		return nil, 0, err
+	}
+	count, err := s.store(feed, parsed)
+	return feed, count, err
+}
+
+// RemoveFeed deletes one of the user's feeds and its entries.
+func (s *FeedService) RemoveFeed(userID, feedID uint) error {
+	feed, err := s.feedRepo.FindByID(feedID)
+	if err != nil {
+		return err
+	}
+	if feed.UserID != userID {
+		return gorm.ErrRecordNotFound
+	}
+	return s.feedRepo.Delete(feed)
+}
+
+// SyncUser refreshes all the user's feeds and returns the number of entries
+// read. A broken feed doesn't hold back the others: its error is kept on the
+// feed, and an error is only returned when every feed failed.
+func (s *FeedService) SyncUser(userID uint) (int, error) {
+	feeds, err := s.feedRepo.FindByUserID(userID)
+	if err != nil {
+		return 0, err
+	}
+
+	total := 0
+	var errs []error
+	for i := range feeds {
+		feed := &feeds[i]
+		count, err := s.syncFeed(feed)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", feed.URL, err))
+			continue
+		}
+		total += count
+	}
+	if len(errs) > 0 && len(errs) == len(feeds) {
+		return 0, errors.Join(errs...)
+	}
+	return total, nil
+}
+
+func (s *FeedService) syncFeed(feed *model.ArticleFeed) (int, error) {
+	parsed, err := s.fetch(feed)
+	if err != nil {
+		feed.LastError = err.Error()
+		if saveErr := s.feedRepo.SaveFetchResult(feed); saveErr != nil {
+			return 0, saveErr
+		}
+		return 0, err
+	}
+	return s.store(feed, parsed)
+}
+
+// store saves the feed's fetch result and its entries. parsed is nil when
+// the feed hasn't changed since the last fetch.
+func (s *FeedService) store(feed *model.ArticleFeed, parsed *parsedFeed) (int, error) {
+	now := time.Now()
+	feed.LastFetchedAt = &now
+	feed.LastError = ""
+	if parsed == nil {
+		return 0, s.feedRepo.SaveFetchResult(feed)
+	}
+
+	feed.Title = parsed.Title
+	feed.SiteURL = parsed.SiteURL
+	feed.Format = parsed.Format
+	if err := s.feedRepo.SaveFetchResult(feed); err != nil {
+		return 0, err
+	}
+
+	articles := make([]model.ExternalArticle, len(parsed.Entries))
+	for i, entry := range parsed.Entries {
+		articles[i] = entry
+		articles[i].FeedID = &feed.ID
+		articles[i].UpdatedAt = now
+	}
+	if err := s.articleRepo.UpsertArticles(feed.UserID, model.IntegrationFeed, articles); err != nil {
+		return 0, err
+	}
+	return len(articles), nil
+}
+
+// fetch downloads and parses the feed, sending the validators from the last
+// fetch. It updates the validators on feed and returns nil if the feed
+// hasn't changed.
+func (s *FeedService) fetch(feed *model.ArticleFeed) (*parsedFeed, error) {
+	req, err := http.NewRequest("GET", feed.URL, nil)
+	if err != nil {
+		return nil, ErrInvalidFeedURL
+	}
+	req.Header.Set("User-Agent", "DevSync feed reader")
+	req.Header.Set("Accept", "application/rss+xml, application/atom+xml, application/feed+json, application/json;q=0.9, application/xml;q=0.9, */*;q=0.8")
+	if feed.ETag != "" {
+		req.Header.Set("If-None-Match", feed.ETag)
+	}
+	if feed.LastModified != "" {
+		req.Header.Set("If-Modified-Since", feed.LastModified)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch feed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if err := checkRateLimit("feed", resp); err != nil {
+		// Limits apply per feed host, not to feeds in general
+		err.(*RateLimitError).TokenScoped = true
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("feed returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxFeedBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read feed: %w", err)
+	}
+	parsed, err := parseFeed(body)
+	if err != nil {
+		return nil, err
+	}
+	feed.ETag = resp.Header.Get("ETag")
+	feed.LastModified = resp.Header.Get("Last-Modified")
+	return parsed, nil
+}
+
+type parsedFeed struct {
+	Format  string
+	Title   string
+	SiteURL string
+	Entries []model.ExternalArticle
+}
+
+// parseFeed reads an RSS (0.9x, 1.0 or 2.0), Atom or JSON Feed document.
+// Entries without a date are skipped, as they can't be placed in time.
+func parseFeed(body []byte) (*parsedFeed, error) {
+	body = bytes.TrimPrefix(bytes.TrimSpace(body), []byte("\xef\xbb\xbf"))
+	if bytes.HasPrefix(body, []byte("{")) {
+		return parseJSONFeed(body)
+	}
+
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	decoder.Strict = false
+	decoder.CharsetReader = func(charset string, input io.Reader) (io.Reader, error) {
+		switch strings.ToLower(charset) {
+		case "utf-8", "utf8", "us-ascii", "ascii":
+			return input, nil
+		}
+		return nil, fmt.Errorf("unsupported feed charset %q", charset)
+	}
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, ErrUnsupportedFeed
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch start.Name.Local {
+		case "rss", "RDF":
+			return parseRSS(decoder, &start)
+		case "feed":
+			return parseAtom(decoder, &start)
+		default:
+			return nil, ErrUnsupportedFeed
+		}
+	}
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Links       []string `xml:"link"`
+	GUID        string   `xml:"guid"`
+	PubDate     string   `xml:"pubDate"`
+	Date        string   `xml:"http://purl.org/dc/elements/1.1/ date"`
+	Description string   `xml:"description"`
+	Categories  []string `xml:"category"`
+	Subjects    []string `xml:"http://purl.org/dc/elements/1.1/ subject"`
+}
+
+func parseRSS(decoder *xml.Decoder, start *xml.StartElement) (*parsedFeed, error) {
+	var doc struct {
+		Channel struct {
+			Title string    `xml:"title"`
+			Links []string  `xml:"link"`
+			Items []rssItem `xml:"item"`
+		} `xml:"channel"`
+		// RSS 1.0 puts items next to the channel
+		Items []rssItem `xml:"item"`
+	}
+	if err := decoder.DecodeElement(&doc, start); err != nil {
+		return nil, fmt.Errorf("invalid RSS feed: %w", err)
+	}
+
+	feed := &parsedFeed{Format: model.FeedFormatRSS, Title: cleanText(doc.Channel.Title), SiteURL: firstNonEmpty(doc.Channel.Links...)}
+	for _, item := range append(doc.Channel.Items, doc.Items...) {
+		published, ok := parseFeedDate(item.PubDate, item.Date)
+		if !ok {
+			continue
+		}
+		link := firstNonEmpty(item.Links...)
+		feed.Entries = append(feed.Entries, model.ExternalArticle{
+			ExternalID:  firstNonEmpty(item.GUID, link, item.Title),
+			Title:       cleanText(item.Title),
+			URL:         link,
+			Summary:     summarize(item.Description),
+			Tags:        joinTags(append(item.Categories, item.Subjects...)),
+			PublishedAt: published,
+		})
+	}
+	return feed, nil
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+}
+
+// alternate returns the link to the HTML page, which is the default relation.
+func atomAlternate(links []atomLink) string {
+	for _, l := range links {
+		if l.Rel == "" || l.Rel == "alternate" {
+			return l.Href
+		}
+	}
+	return ""
+}
+
+func parseAtom(decoder *xml.Decoder, start *xml.StartElement) (*parsedFeed, error) {
+	var doc struct {
+		Title   string     `xml:"title"`
+		Links   []atomLink `xml:"link"`
+		Entries []struct {
+			ID         string     `xml:"id"`
+			Title      string     `xml:"title"`
+			Links      []atomLink `xml:"link"`
+			Published  string     `xml:"published"`
+			Updated    string     `xml:"updated"`
+			Summary    string     `xml:"summary"`
+			Content    string     `xml:"content"`
+			Categories []struct {
+				Term string `xml:"term,attr"`
+			} `xml:"category"`
+		} `xml:"entry"`
+	}
+	if err := decoder.DecodeElement(&doc, start); err != nil {
+		return nil, fmt.Errorf("invalid Atom feed: %w", err)
+	}
+
+	feed := &parsedFeed{Format: model.FeedFormatAtom, Title: cleanText(doc.Title), SiteURL: atomAlternate(doc.Links)}
+	for _, entry := range doc.Entries {
+		published, ok := parseFeedDate(entry.Published, entry.Updated)
+		if !ok {
+			continue
+		}
+		link := atomAlternate(entry.Links)
+		tags := make([]string, len(entry.Categories))
+		for i, c := range entry.Categories {
+			tags[i] = c.Term
+		}
+		feed.Entries = append(feed.Entries, model.ExternalArticle{
+			ExternalID:  firstNonEmpty(entry.ID, link, entry.Title),
+			Title:       cleanText(entry.Title),
+			URL:         link,
+			Summary:     summarize(firstNonEmpty(entry.Summary, entry.Content)),
+			Tags:        joinTags(tags),
+			PublishedAt: published,
+		})
+	}
+	return feed, nil
+}
+
+func parseJSONFeed(body []byte) (*parsedFeed, error) {
+	var doc struct {
+		Version     string `json:"version"`
+		Title       string `json:"title"`
+		HomePageURL string `json:"home_page_url"`
+		Items       []struct {
+			// Version 1.0 allowed numeric IDs
+			ID            interface{} `json:"id"`
+			URL           string      `json:"url"`
+			Title         string      `json:"title"`
+			Summary       string      `json:"summary"`
+			ContentText   string      `json:"content_text"`
+			ContentHTML   string      `json:"content_html"`
+			DatePublished string      `json:"date_published"`
+			DateModified  string      `json:"date_modified"`
+			Tags          []string    `json:"tags"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(body, &doc); err != nil || !strings.HasPrefix(doc.Version, "https://jsonfeed.org/version/") {
+		return nil, ErrUnsupportedFeed
+	}
+
+	feed := &parsedFeed{Format: model.FeedFormatJSON, Title: cleanText(doc.Title), SiteURL: doc.HomePageURL}
+	for _, item := range doc.Items {
+		published, ok := parseFeedDate(item.DatePublished, item.DateModified)
+		if !ok {
+			continue
+		}
+		id := ""
+		if item.ID != nil {
+			id = fmt.Sprint(item.ID)
+		}
+		feed.Entries = append(feed.Entries, model.ExternalArticle{
+			ExternalID:  firstNonEmpty(id, item.URL, item.Title),
+			Title:       cleanText(item.Title),
+			URL:         item.URL,
+			Summary:     summarize(firstNonEmpty(item.Summary, item.ContentText, item.ContentHTML)),
+			Tags:        joinTags(item.Tags),
+			PublishedAt: published,
+		})
+	}
+	return feed, nil
+}
+
+// parseFeedDate parses the first of values that holds a recognized date.
+func parseFeedDate(values ...string) (time.Time, bool) {
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		for _, layout := range feedDateLayouts {
+			if t, err := time.Parse(layout, v); err == nil {
+				return t, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// cleanText turns a possibly HTML-formatted title into plain text.
+func cleanText(s string) string {
+	s = html.UnescapeString(htmlTagPattern.ReplaceAllString(s, " "))
+	return strings.TrimSpace(whitespacePattern.ReplaceAllString(s, " "))
+}
+
+// summarize returns the start of an entry's description as plain text.
+func summarize(s string) string {
+	s = cleanText(s)
+	if utf8.RuneCountInString(s) <= maxFeedSummary {
+		return s
+	}
+	runes := []rune(s)
+	return strings.TrimSpace(string(runes[:maxFeedSummary])) + "…"
+}
+
+func joinTags(tags []string) string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, tag := range tags {
+		for _, name := range strings.Split(tag, ",") {
+			if name = strings.TrimSpace(name); name != "" && !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
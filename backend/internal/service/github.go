@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/norman6464/devsync/backend/internal/config"
@@ -13,27 +14,39 @@ import (
 	"github.com/norman6464/devsync/backend/internal/repository"
 )
 
+const (
+	// githubLanguageBatch caps the /languages requests per sync; remaining
+	// repositories are fetched on later syncs
+	githubLanguageBatch = 100
+	// githubRateLimitReserve is left unspent by background syncs so the
+	// user's own requests keep working
+	githubRateLimitReserve = 100
+)
+
 type GitHubService struct {
 	cfg        *config.Config
 	userRepo   *repository.UserRepository
 	githubRepo *repository.GitHubRepository
+	client     *http.Client
 }
 
-func NewGitHubService(cfg *config.Config, userRepo *repository.UserRepository, githubRepo *repository.GitHubRepository) *GitHubService {
-	return &GitHubService{cfg: cfg, userRepo: userRepo, githubRepo: githubRepo}
+// NewGitHubService creates the service. The base URLs come from cfg so a
+// GitHub Enterprise instance or a fake server can be used instead of github.com.
+func NewGitHubService(cfg *config.Config, userRepo *repository.UserRepository, githubRepo *repository.GitHubRepository, client *http.Client) *GitHubService {
+	return &GitHubService{cfg: cfg, userRepo: userRepo, githubRepo: githubRepo, client: client}
 }
 
 func (s *GitHubService) GetOAuthURL(state string) string {
 	return fmt.Sprintf(
-		"https://github.com/login/oauth/authorize?client_id=%s&redirect_uri=%s&scope=read:user,repo&state=%s",
-		s.cfg.GitHubClientID, s.cfg.GitHubRedirectURL, state,
+		"%s/login/oauth/authorize?client_id=%s&redirect_uri=%s&scope=read:user,repo&state=%s",
+		s.cfg.GitHubBaseURL, s.cfg.GitHubClientID, s.cfg.GitHubRedirectURL, state,
 	)
 }
 
 func (s *GitHubService) GetLoginOAuthURL(state string) string {
 	return fmt.Sprintf(
-		"https://github.com/login/oauth/authorize?client_id=%s&redirect_uri=%s&scope=read:user,user:email,repo&state=%s",
-		s.cfg.GitHubClientID, s.cfg.GitHubRedirectURL, state,
+		"%s/login/oauth/authorize?client_id=%s&redirect_uri=%s&scope=read:user,user:email,repo&state=%s",
+		s.cfg.GitHubBaseURL, s.cfg.GitHubClientID, s.cfg.GitHubRedirectURL, state,
 	)
 }
 
@@ -44,11 +57,11 @@ func (s *GitHubService) ExchangeCode(code string) (string, error) {
 		"code":          code,
 	})
 
-	req, _ := http.NewRequest("POST", "https://github.com/login/oauth/access_token", bytes.NewBuffer(body))
+	req, _ := http.NewRequest("POST", s.cfg.GitHubBaseURL+"/login/oauth/access_token", bytes.NewBuffer(body))
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := s.client.Do(req)
 	if err != nil {
 		return "", err
 	}
@@ -76,16 +89,20 @@ type GitHubUserInfo struct {
 }
 
 func (s *GitHubService) GetGitHubUser(token string) (*GitHubUserInfo, error) {
-	req, _ := http.NewRequest("GET", "https://api.github.com/user", nil)
+	req, _ := http.NewRequest("GET", s.cfg.GitHubAPIURL+"/user", nil)
 	req.Header.Set("Authorization", "Bearer "+token)
 	req.Header.Set("Accept", "application/json")
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := s.client.Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("github user request failed with status %d", resp.StatusCode)
+	}
+
 	var user GitHubUserInfo
 	if err := json.NewDecoder(resp.Body).Decode(&user); err != nil {
 		return nil, err
@@ -100,11 +117,11 @@ func (s *GitHubService) GetGitHubUser(token string) (*GitHubUserInfo, error) {
 }
 
 func (s *GitHubService) fetchPrimaryEmail(token string) string {
-	req, _ := http.NewRequest("GET", "https://api.github.com/user/emails", nil)
+	req, _ := http.NewRequest("GET", s.cfg.GitHubAPIURL+"/user/emails", nil)
 	req.Header.Set("Authorization", "Bearer "+token)
 	req.Header.Set("Accept", "application/json")
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := s.client.Do(req)
 	if err != nil {
 		return ""
 	}
@@ -141,18 +158,131 @@ func (s *GitHubService) SyncData(user *model.User) error {
 		return fmt.Errorf("sync repos: %w", err)
 	}
 
+	// Sync pull requests, issues and reviews
+	if err := s.syncMetrics(user); err != nil {
+		return fmt.Errorf("sync pull requests, issues and reviews: %w", err)
+	}
+
 	return nil
 }
 
+// syncContributions refreshes the current and previous calendar years, which
+// together cover the last twelve months; older years are left to
+// BackfillContributions.
 func (s *GitHubService) syncContributions(user *model.User) error {
-	now := time.Now()
-	from := now.AddDate(-1, 0, 0).Format("2006-01-02T15:04:05Z")
-	to := now.Format("2006-01-02T15:04:05Z")
+	year := time.Now().UTC().Year()
+	for _, y := range []int{year, year - 1} {
+		if user.GitHubCreatedAt != nil && y < user.GitHubCreatedAt.UTC().Year() {
+			continue
+		}
+		result, err := s.syncContributionYear(user, y)
+		if err != nil {
+			return err
+		}
+		if result.CreatedAt.IsZero() || (user.GitHubCreatedAt != nil && user.GitHubCreatedAt.Equal(result.CreatedAt)) {
+			continue
+		}
+		if err := s.userRepo.UpdateGitHubCreatedAt(user.ID, result.CreatedAt); err != nil {
+			return err
+		}
+		createdAt := result.CreatedAt
+		user.GitHubCreatedAt = &createdAt
+	}
+	return nil
+}
 
-	query := fmt.Sprintf(`query {
-		user(login: "%s") {
-			contributionsCollection(from: "%s", to: "%s") {
+// NeedsBackfill reports whether any year since the GitHub account was
+// created is missing from the user's contribution history.
+func (s *GitHubService) NeedsBackfill(user *model.User) (bool, error) {
+	missing, err := s.missingYears(user)
+	return len(missing) > 0, err
+}
+
+// BackfillContributions fetches every year of contribution history missing
+// since the GitHub account was created, with the reviews submitted in it,
+// latest first. Years already fetched
+// are skipped, so an interrupted backfill resumes where it stopped.
+func (s *GitHubService) BackfillContributions(user *model.User) error {
+	missing, err := s.missingYears(user)
+	if err != nil {
+		return err
+	}
+	for _, year := range missing {
+		// Reviews first: the year's summary marks it as done
+		from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+		if err := s.syncReviews(user, from, from.AddDate(1, 0, 0).Add(-time.Second)); err != nil {
+			return fmt.Errorf("backfill %d reviews: %w", year, err)
+		}
+		if _, err := s.syncContributionYear(user, year); err != nil {
+			return fmt.Errorf("backfill %d: %w", year, err)
+		}
+	}
+	return nil
+}
+
+func (s *GitHubService) missingYears(user *model.User) ([]int, error) {
+	if user.GitHubCreatedAt == nil {
+		return nil, nil
+	}
+	synced, err := s.githubRepo.FindSyncedYears(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	have := make(map[int]bool, len(synced))
+	for _, y := range synced {
+		have[y] = true
+	}
+
+	var missing []int
+	for y := time.Now().UTC().Year(); y >= user.GitHubCreatedAt.UTC().Year(); y-- {
+		if !have[y] {
+			missing = append(missing, y)
+		}
+	}
+	return missing, nil
+}
+
+type contributionYear struct {
+	CreatedAt time.Time
+	Summary   model.GitHubContributionYear
+	Days      []model.GitHubContribution
+}
+
+// syncContributionYear fetches one calendar year of contributions and stores
+// the daily counts and the year's summary.
+func (s *GitHubService) syncContributionYear(user *model.User, year int) (*contributionYear, error) {
+	result, err := s.fetchContributionYear(user, year)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.githubRepo.UpsertContributions(result.Days); err != nil {
+		return nil, err
+	}
+	if err := s.githubRepo.UpsertContributionYear(&result.Summary); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *GitHubService) fetchContributionYear(user *model.User, year int) (*contributionYear, error) {
+	// A contributions collection can span at most one year
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(year, time.December, 31, 23, 59, 59, 0, time.UTC)
+	if now := time.Now().UTC(); to.After(now) {
+		to = now
+	}
+
+	query := `query($from: DateTime!, $to: DateTime!) {
+		viewer {
+			createdAt
+			contributionsCollection(from: $from, to: $to) {
+				totalCommitContributions
+				totalIssueContributions
+				totalPullRequestContributions
+				totalPullRequestReviewContributions
+				restrictedContributionsCount
 				contributionCalendar {
+					totalContributions
 					weeks {
 						contributionDays {
 							date
@@ -162,57 +292,131 @@ func (s *GitHubService) syncContributions(user *model.User) error {
 				}
 			}
 		}
-	}`, user.GitHubUsername, from, to)
+	}`
+
+	var data struct {
+		Viewer struct {
+			CreatedAt               time.Time `json:"createdAt"`
+			ContributionsCollection struct {
+				Commits              int `json:"totalCommitContributions"`
+				Issues               int `json:"totalIssueContributions"`
+				PullRequests         int `json:"totalPullRequestContributions"`
+				Reviews              int `json:"totalPullRequestReviewContributions"`
+				Restricted           int `json:"restrictedContributionsCount"`
+				ContributionCalendar struct {
+					TotalContributions int `json:"totalContributions"`
+					Weeks              []struct {
+						ContributionDays []struct {
+							Date              string `json:"date"`
+							ContributionCount int    `json:"contributionCount"`
+						} `json:"contributionDays"`
+					} `json:"weeks"`
+				} `json:"contributionCalendar"`
+			} `json:"contributionsCollection"`
+		} `json:"viewer"`
+	}
+	header, err := s.graphQL(user.GitHubToken, query, map[string]interface{}{
+		"from": from.Format(time.RFC3339),
+		"to":   to.Format(time.RFC3339),
+	}, &data)
+	if err != nil {
+		return nil, err
+	}
 
-	body, _ := json.Marshal(map[string]string{"query": query})
-	req, _ := http.NewRequest("POST", "https://api.github.com/graphql", bytes.NewBuffer(body))
-	req.Header.Set("Authorization", "Bearer "+user.GitHubToken)
+	collection := data.Viewer.ContributionsCollection
+	out := &contributionYear{
+		CreatedAt: data.Viewer.CreatedAt,
+		Summary: model.GitHubContributionYear{
+			UserID:       user.ID,
+			Year:         year,
+			Total:        collection.ContributionCalendar.TotalContributions,
+			Commits:      collection.Commits,
+			Issues:       collection.Issues,
+			PullRequests: collection.PullRequests,
+			Reviews:      collection.Reviews,
+			Restricted:   collection.Restricted,
+			// Contributions to private repositories are only visible with the repo scope
+			IncludesPrivate: hasOAuthScope(header, "repo"),
+		},
+	}
+	for _, week := range collection.ContributionCalendar.Weeks {
+		for _, day := range week.ContributionDays {
+			date, _ := time.Parse("2006-01-02", day.Date)
+			out.Days = append(out.Days, model.GitHubContribution{
+				UserID: user.ID,
+				Date:   date,
+				Count:  day.ContributionCount,
+			})
+		}
+	}
+	return out, nil
+}
+
+// graphQL runs a GraphQL query with the user's token and decodes the
+// response data into data. It returns the response headers, which carry the
+// token's scopes.
+func (s *GitHubService) graphQL(token, query string, variables map[string]interface{}, data interface{}) (http.Header, error) {
+	body, _ := json.Marshal(map[string]interface{}{"query": query, "variables": variables})
+	req, _ := http.NewRequest("POST", s.cfg.GitHubGraphQLURL, bytes.NewBuffer(body))
+	req = retryable(req)
+	req.Header.Set("Authorization", "Bearer "+token)
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := s.client.Do(req)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
-	var result struct {
-		Data struct {
-			User struct {
-				ContributionsCollection struct {
-					ContributionCalendar struct {
-						Weeks []struct {
-							ContributionDays []struct {
-								Date              string `json:"date"`
-								ContributionCount int    `json:"contributionCount"`
-							} `json:"contributionDays"`
-						} `json:"weeks"`
-					} `json:"contributionCalendar"`
-				} `json:"contributionsCollection"`
-			} `json:"user"`
-		} `json:"data"`
+	if err := checkGitHubRateLimit(resp); err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("github graphql request failed with status %d", resp.StatusCode)
 	}
 
+	var result struct {
+		Errors []struct {
+			Type    string `json:"type"`
+			Message string `json:"message"`
+		} `json:"errors"`
+		Data json.RawMessage `json:"data"`
+	}
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return err
+		return nil, err
 	}
-
-	var contributions []model.GitHubContribution
-	for _, week := range result.Data.User.ContributionsCollection.ContributionCalendar.Weeks {
-		for _, day := range week.ContributionDays {
-			date, _ := time.Parse("2006-01-02", day.Date)
-			contributions = append(contributions, model.GitHubContribution{
-				UserID: user.ID,
-				Date:   date,
-				Count:  day.ContributionCount,
-			})
+	if len(result.Errors) > 0 {
+		// GraphQL reports an exhausted point budget in the body
+		if result.Errors[0].Type == "RATE_LIMITED" {
+			_, resetAt, _ := rateLimitRemaining(resp)
+			if resetAt.IsZero() {
+				resetAt = time.Now().Add(defaultRateLimitWait)
+			}
+			return nil, &RateLimitError{Provider: "GitHub", ResetAt: resetAt, TokenScoped: true}
 		}
+		return nil, fmt.Errorf("github graphql error: %s", result.Errors[0].Message)
 	}
+	return resp.Header, json.Unmarshal(result.Data, data)
+}
 
-	return s.githubRepo.UpsertContributions(contributions)
+// hasOAuthScope reports whether a token was granted scope, per the
+// X-OAuth-Scopes header of a response to one of its requests.
+func hasOAuthScope(header http.Header, scope string) bool {
+	for _, granted := range strings.Split(header.Get("X-OAuth-Scopes"), ",") {
+		if strings.TrimSpace(granted) == scope {
+			return true
+		}
+	}
+	return false
 }
 
+// syncReposAndLanguages refreshes the repository list and then fetches
+// language bytes for up to githubLanguageBatch repositories, those checked
+// longest ago first, so every repository is covered over successive syncs.
+// Language requests are conditional on the stored ETag and stop while the
+// token's rate limit is nearly spent.
 func (s *GitHubService) syncReposAndLanguages(user *model.User) error {
-	var allRepos []struct {
+	type githubRepo struct {
 		ID          int64  `json:"id"`
 		Name        string `json:"name"`
 		FullName    string `json:"full_name"`
@@ -222,49 +426,39 @@ func (s *GitHubService) syncReposAndLanguages(user *model.User) error {
 		Forks       int    `json:"forks_count"`
 		Private     bool   `json:"private"`
 	}
+	var allRepos []githubRepo
 
-	page := 1
-	for {
-		url := fmt.Sprintf("https://api.github.com/user/repos?per_page=100&page=%d&sort=updated", page)
-		req, _ := http.NewRequest("GET", url, nil)
-		req.Header.Set("Authorization", "Bearer "+user.GitHubToken)
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
+	remaining := -1
+	url := s.cfg.GitHubAPIURL + "/user/repos?per_page=100&sort=updated"
+	for url != "" {
+		resp, err := s.apiGet(url, user.GitHubToken, "")
 		if err != nil {
 			return err
 		}
 
 		data, _ := io.ReadAll(resp.Body)
 		resp.Body.Close()
-
-		var repos []struct {
-			ID          int64  `json:"id"`
-			Name        string `json:"name"`
-			FullName    string `json:"full_name"`
-			Description string `json:"description"`
-			Language    string `json:"language"`
-			Stars       int    `json:"stargazers_count"`
-			Forks       int    `json:"forks_count"`
-			Private     bool   `json:"private"`
+		if err := checkGitHubRateLimit(resp); err != nil {
+			return err
+		}
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("github repos request failed with status %d", resp.StatusCode)
+		}
+		if n, _, ok := rateLimitRemaining(resp); ok {
+			remaining = n
 		}
+
+		var repos []githubRepo
 		if err := json.Unmarshal(data, &repos); err != nil {
 			return err
 		}
-		if len(repos) == 0 {
-			break
-		}
 		allRepos = append(allRepos, repos...)
-		if len(repos) < 100 {
-			break
-		}
-		page++
+		url = nextPageURL(resp.Header)
 	}
 
 	// Save repos
 	var modelRepos []model.GitHubRepository
-	langMap := make(map[string]*model.GitHubLanguageStat)
-
+	repoIDs := make([]int64, 0, len(allRepos))
 	for _, r := range allRepos {
 		modelRepos = append(modelRepos, model.GitHubRepository{
 			UserID:       user.ID,
@@ -277,63 +471,105 @@ func (s *GitHubService) syncReposAndLanguages(user *model.User) error {
 			Forks:        r.Forks,
 			IsPrivate:    r.Private,
 		})
-
-		if r.Language != "" {
-			if stat, ok := langMap[r.Language]; ok {
-				stat.RepoCount++
-			} else {
-				langMap[r.Language] = &model.GitHubLanguageStat{
-					UserID:    user.ID,
-					Language:  r.Language,
-					Bytes:     0,
-					RepoCount: 1,
-				}
-			}
-		}
+		repoIDs = append(repoIDs, r.ID)
 	}
 
 	if err := s.githubRepo.UpsertRepos(modelRepos); err != nil {
 		return err
 	}
+	if err := s.githubRepo.DeleteReposExcept(user.ID, repoIDs); err != nil {
+		return err
+	}
 
-	// Fetch language bytes for top repos (limit to 20 to avoid rate limiting)
-	limit := 20
-	if len(allRepos) < limit {
-		limit = len(allRepos)
+	langErr := s.syncRepoLanguages(user, remaining)
+	if err := s.githubRepo.RecomputeLanguageStats(user.ID); err != nil {
+		return err
 	}
-	for i := 0; i < limit; i++ {
-		r := allRepos[i]
-		url := fmt.Sprintf("https://api.github.com/repos/%s/languages", r.FullName)
-		req, _ := http.NewRequest("GET", url, nil)
-		req.Header.Set("Authorization", "Bearer "+user.GitHubToken)
-		req.Header.Set("Accept", "application/json")
+	return langErr
+}
 
-		resp, err := http.DefaultClient.Do(req)
+func (s *GitHubService) syncRepoLanguages(user *model.User, remaining int) error {
+	if remaining >= 0 && remaining <= githubRateLimitReserve {
+		return nil
+	}
+	repos, err := s.githubRepo.FindReposForLanguageSync(user.ID, githubLanguageBatch)
+	if err != nil {
+		return err
+	}
+
+	for _, repo := range repos {
+		url := fmt.Sprintf("%s/repos/%s/languages", s.cfg.GitHubAPIURL, repo.FullName)
+		resp, err := s.apiGet(url, user.GitHubToken, repo.LanguagesETag)
 		if err != nil {
+			// Left stale, so it's first in line next time
 			continue
 		}
+
 		var langs map[string]int64
-		json.NewDecoder(resp.Body).Decode(&langs)
+		if resp.StatusCode == http.StatusOK {
+			json.NewDecoder(resp.Body).Decode(&langs)
+		}
 		resp.Body.Close()
+		if err := checkGitHubRateLimit(resp); err != nil {
+			return err
+		}
 
-		for lang, b := range langs {
-			if stat, ok := langMap[lang]; ok {
-				stat.Bytes += b
-			} else {
-				langMap[lang] = &model.GitHubLanguageStat{
-					UserID:    user.ID,
-					Language:  lang,
-					Bytes:     b,
-					RepoCount: 0,
-				}
-			}
+		switch resp.StatusCode {
+		case http.StatusOK:
+			err = s.githubRepo.SaveRepoLanguages(repo.ID, resp.Header.Get("ETag"), langs)
+		case http.StatusNotModified:
+			err = s.githubRepo.TouchRepoLanguages(repo.ID)
+		case http.StatusNotFound, http.StatusForbidden:
+			// Access to the repository was lost since it was listed
+			err = s.githubRepo.SaveRepoLanguages(repo.ID, "", nil)
+		default:
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if n, _, ok := rateLimitRemaining(resp); ok && n <= githubRateLimitReserve {
+			break
 		}
 	}
+	return nil
+}
 
-	var langStats []model.GitHubLanguageStat
-	for _, stat := range langMap {
-		langStats = append(langStats, *stat)
+// nextPageURL returns the rel="next" URL of a paginated response's Link
+// header, or "" on the last page.
+func nextPageURL(header http.Header) string {
+	for _, link := range strings.Split(header.Get("Link"), ",") {
+		target, params, ok := strings.Cut(link, ";")
+		if !ok || !strings.Contains(params, `rel="next"`) {
+			continue
+		}
+		return strings.Trim(strings.TrimSpace(target), "<>")
 	}
+	return ""
+}
 
-	return s.githubRepo.UpsertLanguageStats(langStats)
+// apiGet sends an authenticated GET to the REST API, conditional on etag
+// when it's set.
+func (s *GitHubService) apiGet(url, token, etag string) (*http.Response, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/json")
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	return s.client.Do(req)
+}
+
+// checkGitHubRateLimit is checkRateLimit for GitHub, whose limits apply per
+// user token.
+func checkGitHubRateLimit(resp *http.Response) error {
+	err := checkRateLimit("GitHub", resp)
+	if rateLimit, ok := err.(*RateLimitError); ok {
+		rateLimit.TokenScoped = true
+	}
+	return err
 }
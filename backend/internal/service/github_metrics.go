@@ -0,0 +1,296 @@
+package service
+
+import (
+	"strings"
+	"time"
+
+	"github.com/norman6464/devsync/backend/internal/model"
+)
+
+// reviewSyncOverlap is how far before the latest stored review a sync looks
+// again, to pick up reviews GitHub reports late.
+const reviewSyncOverlap = 7 * 24 * time.Hour
+
+type graphQLPageInfo struct {
+	HasNextPage bool   `json:"hasNextPage"`
+	EndCursor   string `json:"endCursor"`
+}
+
+type graphQLRepository struct {
+	NameWithOwner string `json:"nameWithOwner"`
+	IsPrivate     bool   `json:"isPrivate"`
+}
+
+// syncMetrics refreshes the pull requests, issues and reviews the user
+// authored.
+func (s *GitHubService) syncMetrics(user *model.User) error {
+	if err := s.syncPullRequests(user); err != nil {
+		return err
+	}
+	if err := s.syncIssues(user); err != nil {
+		return err
+	}
+
+	// The first sync covers the same two calendar years as contributions;
+	// older reviews come with the contribution backfill
+	now := time.Now().UTC()
+	from := time.Date(now.Year()-1, time.January, 1, 0, 0, 0, 0, time.UTC)
+	latest, err := s.githubRepo.LatestReviewSubmitted(user.ID)
+	if err != nil {
+		return err
+	}
+	if latest != nil && latest.Add(-reviewSyncOverlap).After(from) {
+		from = latest.Add(-reviewSyncOverlap)
+	}
+	return s.syncReviews(user, from, now)
+}
+
+// syncPullRequests pages through the user's pull requests, most recently
+// updated first, until it reaches ones that haven't changed since the last sync.
+func (s *GitHubService) syncPullRequests(user *model.User) error {
+	since, err := s.githubRepo.LatestPullRequestUpdate(user.ID)
+	if err != nil {
+		return err
+	}
+
+	query := `query($cursor: String) {
+		viewer {
+			pullRequests(first: 100, after: $cursor, orderBy: {field: UPDATED_AT, direction: DESC}) {
+				pageInfo { hasNextPage endCursor }
+				nodes {
+					databaseId number title url state additions deletions
+					createdAt updatedAt mergedAt closedAt
+					repository { nameWithOwner isPrivate }
+				}
+			}
+		}
+	}`
+
+	var cursor interface{}
+	for {
+		var data struct {
+			Viewer struct {
+				PullRequests struct {
+					PageInfo graphQLPageInfo `json:"pageInfo"`
+					Nodes    []struct {
+						DatabaseID int64             `json:"databaseId"`
+						Number     int               `json:"number"`
+						Title      string            `json:"title"`
+						URL        string            `json:"url"`
+						State      string            `json:"state"`
+						Additions  int               `json:"additions"`
+						Deletions  int               `json:"deletions"`
+						CreatedAt  time.Time         `json:"createdAt"`
+						UpdatedAt  time.Time         `json:"updatedAt"`
+						MergedAt   *time.Time        `json:"mergedAt"`
+						ClosedAt   *time.Time        `json:"closedAt"`
+						Repository graphQLRepository `json:"repository"`
+					} `json:"nodes"`
+				} `json:"pullRequests"`
+			} `json:"viewer"`
+		}
+		if _, err := s.graphQL(user.GitHubToken, query, map[string]interface{}{"cursor": cursor}, &data); err != nil {
+			return err
+		}
+
+		page := data.Viewer.PullRequests
+		var prs []model.GitHubPullRequest
+		caughtUp := false
+		for _, n := range page.Nodes {
+			if since != nil && n.UpdatedAt.Before(*since) {
+				caughtUp = true
+				break
+			}
+			prs = append(prs, model.GitHubPullRequest{
+				UserID:          user.ID,
+				GitHubID:        n.DatabaseID,
+				RepoFullName:    n.Repository.NameWithOwner,
+				Number:          n.Number,
+				Title:           n.Title,
+				URL:             n.URL,
+				State:           strings.ToLower(n.State),
+				IsPrivate:       n.Repository.IsPrivate,
+				Additions:       n.Additions,
+				Deletions:       n.Deletions,
+				OpenedAt:        n.CreatedAt,
+				MergedAt:        n.MergedAt,
+				ClosedAt:        n.ClosedAt,
+				GitHubUpdatedAt: n.UpdatedAt,
+			})
+		}
+		if err := s.githubRepo.UpsertPullRequests(prs); err != nil {
+			return err
+		}
+		if caughtUp || !page.PageInfo.HasNextPage {
+			return nil
+		}
+		cursor = page.PageInfo.EndCursor
+	}
+}
+
+// syncIssues pages through the issues the user opened like syncPullRequests.
+func (s *GitHubService) syncIssues(user *model.User) error {
+	since, err := s.githubRepo.LatestIssueUpdate(user.ID)
+	if err != nil {
+		return err
+	}
+
+	query := `query($cursor: String) {
+		viewer {
+			issues(first: 100, after: $cursor, orderBy: {field: UPDATED_AT, direction: DESC}) {
+				pageInfo { hasNextPage endCursor }
+				nodes {
+					databaseId number title url state
+					createdAt updatedAt closedAt
+					repository { nameWithOwner isPrivate }
+				}
+			}
+		}
+	}`
+
+	var cursor interface{}
+	for {
+		var data struct {
+			Viewer struct {
+				Issues struct {
+					PageInfo graphQLPageInfo `json:"pageInfo"`
+					Nodes    []struct {
+						DatabaseID int64             `json:"databaseId"`
+						Number     int               `json:"number"`
+						Title      string            `json:"title"`
+						URL        string            `json:"url"`
+						State      string            `json:"state"`
+						CreatedAt  time.Time         `json:"createdAt"`
+						UpdatedAt  time.Time         `json:"updatedAt"`
+						ClosedAt   *time.Time        `json:"closedAt"`
+						Repository graphQLRepository `json:"repository"`
+					} `json:"nodes"`
+				} `json:"issues"`
+			} `json:"viewer"`
+		}
+		if _, err := s.graphQL(user.GitHubToken, query, map[string]interface{}{"cursor": cursor}, &data); err != nil {
+			return err
+		}
+
+		page := data.Viewer.Issues
+		var issues []model.GitHubIssue
+		caughtUp := false
+		for _, n := range page.Nodes {
+			if since != nil && n.UpdatedAt.Before(*since) {
+				caughtUp = true
+				break
+			}
+			issues = append(issues, model.GitHubIssue{
+				UserID:          user.ID,
+				GitHubID:        n.DatabaseID,
+				RepoFullName:    n.Repository.NameWithOwner,
+				Number:          n.Number,
+				Title:           n.Title,
+				URL:             n.URL,
+				State:           strings.ToLower(n.State),
+				IsPrivate:       n.Repository.IsPrivate,
+				OpenedAt:        n.CreatedAt,
+				ClosedAt:        n.ClosedAt,
+				GitHubUpdatedAt: n.UpdatedAt,
+			})
+		}
+		if err := s.githubRepo.UpsertIssues(issues); err != nil {
+			return err
+		}
+		if caughtUp || !page.PageInfo.HasNextPage {
+			return nil
+		}
+		cursor = page.PageInfo.EndCursor
+	}
+}
+
+// syncReviews fetches the reviews the user submitted between from and to,
+// split into the one-year windows a contributions collection allows.
+func (s *GitHubService) syncReviews(user *model.User, from, to time.Time) error {
+	query := `query($from: DateTime!, $to: DateTime!, $cursor: String) {
+		viewer {
+			contributionsCollection(from: $from, to: $to) {
+				pullRequestReviewContributions(first: 100, after: $cursor) {
+					pageInfo { hasNextPage endCursor }
+					nodes {
+						pullRequestReview { databaseId state url submittedAt createdAt }
+						pullRequest { number repository { nameWithOwner isPrivate } }
+					}
+				}
+			}
+		}
+	}`
+
+	if now := time.Now().UTC(); to.After(now) {
+		to = now
+	}
+	for windowStart := from; windowStart.Before(to); {
+		windowEnd := windowStart.AddDate(1, 0, 0).Add(-time.Second)
+		if windowEnd.After(to) {
+			windowEnd = to
+		}
+
+		var cursor interface{}
+		for {
+			var data struct {
+				Viewer struct {
+					ContributionsCollection struct {
+						Reviews struct {
+							PageInfo graphQLPageInfo `json:"pageInfo"`
+							Nodes    []struct {
+								Review struct {
+									DatabaseID  int64      `json:"databaseId"`
+									State       string     `json:"state"`
+									URL         string     `json:"url"`
+									SubmittedAt *time.Time `json:"submittedAt"`
+									CreatedAt   time.Time  `json:"createdAt"`
+								} `json:"pullRequestReview"`
+								PullRequest struct {
+									Number     int               `json:"number"`
+									Repository graphQLRepository `json:"repository"`
+								} `json:"pullRequest"`
+							} `json:"nodes"`
+						} `json:"pullRequestReviewContributions"`
+					} `json:"contributionsCollection"`
+				} `json:"viewer"`
+			}
+			_, err := s.graphQL(user.GitHubToken, query, map[string]interface{}{
+				"from":   windowStart.Format(time.RFC3339),
+				"to":     windowEnd.Format(time.RFC3339),
+				"cursor": cursor,
+			}, &data)
+			if err != nil {
+				return err
+			}
+
+			page := data.Viewer.ContributionsCollection.Reviews
+			reviews := make([]model.GitHubReview, 0, len(page.Nodes))
+			for _, n := range page.Nodes {
+				submittedAt := n.Review.CreatedAt
+				if n.Review.SubmittedAt != nil {
+					submittedAt = *n.Review.SubmittedAt
+				}
+				reviews = append(reviews, model.GitHubReview{
+					UserID:            user.ID,
+					GitHubID:          n.Review.DatabaseID,
+					RepoFullName:      n.PullRequest.Repository.NameWithOwner,
+					PullRequestNumber: n.PullRequest.Number,
+					State:             strings.ToLower(n.Review.State),
+					URL:               n.Review.URL,
+					IsPrivate:         n.PullRequest.Repository.IsPrivate,
+					SubmittedAt:       submittedAt,
+				})
+			}
+			if err := s.githubRepo.UpsertReviews(reviews); err != nil {
+				return err
+			}
+			if !page.PageInfo.HasNextPage {
+				break
+			}
+			cursor = page.PageInfo.EndCursor
+		}
+
+		windowStart = windowEnd.Add(time.Second)
+	}
+	return nil
+}
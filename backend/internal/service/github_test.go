@@ -0,0 +1,437 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/norman6464/devsync/backend/internal/config"
+	"github.com/norman6464/devsync/backend/internal/githubtest"
+	"github.com/norman6464/devsync/backend/internal/model"
+	"github.com/norman6464/devsync/backend/internal/repository"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestGitHubService returns a GitHubService talking to a fake GitHub
+// server and storing into a fakeDB.
+func newTestGitHubService(t *testing.T) (*GitHubService, *githubtest.Server, *fakeDB) {
+	t.Helper()
+	srv := githubtest.NewServer()
+	t.Cleanup(srv.Close)
+
+	cfg := &config.Config{
+		GitHubClientID:     "client-id",
+		GitHubClientSecret: "client-secret",
+		GitHubBaseURL:      srv.URL,
+		GitHubAPIURL:       srv.URL,
+		GitHubGraphQLURL:   srv.GraphQLURL,
+	}
+	client := &http.Client{
+		Timeout:   5 * time.Second,
+		Transport: &retryTransport{next: http.DefaultTransport, maxRetries: 2, baseDelay: time.Millisecond},
+	}
+
+	store := &fakeDB{}
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sql.OpenDB(store)}), &gorm.Config{
+		Logger: logger.Discard,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewGitHubService(cfg, repository.NewUserRepository(db), repository.NewGitHubRepository(db), client)
+	return s, srv, store
+}
+
+func testGitHubUser(srv *githubtest.Server) *model.User {
+	return &model.User{ID: 1, GitHubToken: srv.Token}
+}
+
+// countRequests returns how many requests the server received starting with prefix.
+func countRequests(srv *githubtest.Server, prefix string) int {
+	n := 0
+	for _, r := range srv.Requests() {
+		if strings.HasPrefix(r, prefix) {
+			n++
+		}
+	}
+	return n
+}
+
+func TestExchangeCode(t *testing.T) {
+	s, srv, _ := newTestGitHubService(t)
+
+	token, err := s.ExchangeCode(githubtest.Code)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != srv.Token {
+		t.Errorf("token = %q, want %q", token, srv.Token)
+	}
+
+	if _, err := s.ExchangeCode("wrong-code"); err == nil || !strings.Contains(err.Error(), "bad_verification_code") {
+		t.Errorf("bad code: err = %v, want bad_verification_code", err)
+	}
+}
+
+func TestGetGitHubUser(t *testing.T) {
+	s, srv, _ := newTestGitHubService(t)
+
+	// The recorded user's email is private, so it comes from /user/emails
+	user, err := s.GetGitHubUser(srv.Token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.ID != 583231 || user.Login != "octocat" || user.Name != "The Octocat" {
+		t.Errorf("user = %+v", user)
+	}
+	if user.Email != "octocat@github.com" {
+		t.Errorf("email = %q, want the primary verified address", user.Email)
+	}
+	if countRequests(srv, "GET /user/emails") != 1 {
+		t.Errorf("requests = %v, want one /user/emails", srv.Requests())
+	}
+
+	srv.User["email"] = "public@example.com"
+	user, err = s.GetGitHubUser(srv.Token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Email != "public@example.com" {
+		t.Errorf("email = %q, want the public address", user.Email)
+	}
+	if countRequests(srv, "GET /user/emails") != 1 {
+		t.Errorf("/user/emails fetched despite a public email")
+	}
+
+	if _, err := s.GetGitHubUser("bad-token"); err == nil {
+		t.Error("bad token: want an error")
+	}
+}
+
+func TestSyncReposFollowsLinkHeader(t *testing.T) {
+	s, srv, store := newTestGitHubService(t)
+
+	// 250 repositories take three pages of 100
+	var template map[string]any
+	json.Unmarshal(srv.Repos[0], &template)
+	srv.Repos = nil
+	for i := 1; i <= 250; i++ {
+		template["id"] = i
+		template["full_name"] = fmt.Sprintf("octocat/repo-%d", i)
+		data, _ := json.Marshal(template)
+		srv.Repos = append(srv.Repos, data)
+	}
+
+	if err := s.syncReposAndLanguages(testGitHubUser(srv)); err != nil {
+		t.Fatal(err)
+	}
+	if n := countRequests(srv, "GET /user/repos"); n != 3 {
+		t.Errorf("repo pages fetched = %d, want 3: %v", n, srv.Requests())
+	}
+	for _, page := range []string{"page=2", "page=3"} {
+		found := false
+		for _, r := range srv.Requests() {
+			found = found || (strings.HasPrefix(r, "GET /user/repos") && strings.Contains(r, page))
+		}
+		if !found {
+			t.Errorf("%s not requested: %v", page, srv.Requests())
+		}
+	}
+
+	inserts := store.statements(`INSERT INTO "git_hub_repositories"`)
+	if len(inserts) != 1 || !strings.Contains(inserts[0].query, "ON CONFLICT") {
+		t.Fatalf("repository upserts = %v, want one", inserts)
+	}
+	// Several columns per repository
+	if got := len(inserts[0].args); got < 250 {
+		t.Errorf("upserted %d values, want every repository", got)
+	}
+}
+
+func TestSyncRepoLanguagesReusesETag(t *testing.T) {
+	s, srv, store := newTestGitHubService(t)
+	store.repos = []model.GitHubRepository{{ID: 10, UserID: 1, FullName: "octocat/linguist"}}
+	user := testGitHubUser(srv)
+
+	if err := s.syncRepoLanguages(user, -1); err != nil {
+		t.Fatal(err)
+	}
+	etag := store.repos[0].LanguagesETag
+	if etag == "" {
+		t.Fatal("ETag of the /languages response not stored")
+	}
+	if n := len(store.statements(`INSERT INTO "git_hub_repo_languages"`)); n != 1 {
+		t.Errorf("language inserts = %d, want 1", n)
+	}
+
+	remaining := srv.RateLimitRemaining
+	store.reset()
+	if err := s.syncRepoLanguages(user, -1); err != nil {
+		t.Fatal(err)
+	}
+	if srv.RateLimitRemaining != remaining {
+		t.Errorf("rate limit spent on an unchanged repository: %d -> %d", remaining, srv.RateLimitRemaining)
+	}
+	if n := len(store.statements(`INSERT INTO "git_hub_repo_languages"`)); n != 0 {
+		t.Errorf("languages rewritten on 304 Not Modified")
+	}
+	touched := store.statements(`UPDATE "git_hub_repositories" SET "languages_synced_at"`)
+	if len(touched) != 1 {
+		t.Errorf("repository not marked as checked: %v", store.statements(""))
+	}
+	if store.repos[0].LanguagesETag != etag {
+		t.Errorf("ETag changed on 304: %q -> %q", etag, store.repos[0].LanguagesETag)
+	}
+
+	// An exhausted rate limit stops the sync
+	srv.RateLimitRemaining = 0
+	store.reset()
+	err := s.syncRepoLanguages(user, -1)
+	var rateLimit *RateLimitError
+	if !errors.As(err, &rateLimit) || !rateLimit.TokenScoped {
+		t.Errorf("exhausted rate limit: err = %v, want a token-scoped RateLimitError", err)
+	}
+}
+
+func TestFetchContributionYear(t *testing.T) {
+	s, srv, _ := newTestGitHubService(t)
+
+	result, err := s.fetchContributionYear(testGitHubUser(srv), 2024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	summary := result.Summary
+	if summary.Year != 2024 || summary.Total != 11 || summary.Commits != 7 || summary.PullRequests != 2 ||
+		summary.Issues != 1 || summary.Reviews != 1 {
+		t.Errorf("summary = %+v", summary)
+	}
+	if !summary.IncludesPrivate {
+		t.Error("IncludesPrivate = false, want true for a token with the repo scope")
+	}
+	if want := time.Date(2011, time.January, 25, 18, 44, 36, 0, time.UTC); !result.CreatedAt.Equal(want) {
+		t.Errorf("CreatedAt = %v, want %v", result.CreatedAt, want)
+	}
+	total := 0
+	for _, day := range result.Days {
+		total += day.Count
+		if day.UserID != 1 || day.Date.IsZero() {
+			t.Fatalf("day = %+v", day)
+		}
+	}
+	if total != summary.Total {
+		t.Errorf("daily counts sum to %d, want %d", total, summary.Total)
+	}
+}
+
+func TestSyncMetricsPages(t *testing.T) {
+	s, srv, store := newTestGitHubService(t)
+	srv.GraphQLPageSize = 1
+	user := testGitHubUser(srv)
+
+	if err := s.syncPullRequests(user); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(store.statements(`INSERT INTO "git_hub_pull_requests"`)); n != 3 {
+		t.Errorf("pull request pages stored = %d, want 3", n)
+	}
+
+	if err := s.syncIssues(user); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(store.statements(`INSERT INTO "git_hub_issues"`)); n != 2 {
+		t.Errorf("issue pages stored = %d, want 2", n)
+	}
+
+	// Two calendar years take two contributions collections
+	from := time.Date(2023, time.June, 1, 0, 0, 0, 0, time.UTC)
+	if err := s.syncReviews(user, from, from.AddDate(1, 6, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(store.statements(`INSERT INTO "git_hub_reviews"`)); n != 2 {
+		t.Errorf("review windows stored = %d, want 2", n)
+	}
+	if n := countRequests(srv, "POST /graphql"); n != 3+2+2 {
+		t.Errorf("GraphQL requests = %d, want 7", n)
+	}
+}
+
+func TestRetryTransport(t *testing.T) {
+	s, srv, _ := newTestGitHubService(t)
+
+	// GETs are retried on 5xx
+	srv.FailNext(2)
+	if _, err := s.GetGitHubUser(srv.Token); err != nil {
+		t.Fatalf("GET after two failures: %v", err)
+	}
+	if n := countRequests(srv, "GET /user"); n != 4 {
+		t.Errorf("GET /user requests = %d, want 3 tries and /user/emails", n)
+	}
+
+	// Retries give up after maxRetries
+	srv.FailNext(3)
+	if _, err := s.GetGitHubUser(srv.Token); err == nil {
+		t.Error("GET after three failures: want an error")
+	}
+
+	// GraphQL queries are marked retryable
+	srv.FailNext(1)
+	if _, err := s.fetchContributionYear(testGitHubUser(srv), 2024); err != nil {
+		t.Fatalf("GraphQL after a failure: %v", err)
+	}
+
+	// The OAuth exchange isn't
+	srv.FailNext(1)
+	before := countRequests(srv, "POST /login/oauth/access_token")
+	token, _ := s.ExchangeCode(githubtest.Code)
+	if token != "" {
+		t.Error("OAuth exchange retried")
+	}
+	if n := countRequests(srv, "POST /login/oauth/access_token") - before; n != 1 {
+		t.Errorf("OAuth exchange requests = %d, want 1", n)
+	}
+}
+
+func TestNextPageURL(t *testing.T) {
+	header := http.Header{}
+	header.Set("Link", `<https://api.github.com/user/repos?page=3>; rel="next", <https://api.github.com/user/repos?page=5>; rel="last"`)
+	if got := nextPageURL(header); got != "https://api.github.com/user/repos?page=3" {
+		t.Errorf("nextPageURL = %q", got)
+	}
+	header.Set("Link", `<https://api.github.com/user/repos?page=1>; rel="first"`)
+	if got := nextPageURL(header); got != "" {
+		t.Errorf("nextPageURL on the last page = %q, want empty", got)
+	}
+	if got := nextPageURL(http.Header{}); got != "" {
+		t.Errorf("nextPageURL without a Link header = %q, want empty", got)
+	}
+}
+
+// fakeDB is a database/sql connector standing in for Postgres. It records
+// every statement and answers the language sync's queries from repos;
+// everything else affects one row and returns none.
+type fakeDB struct {
+	mu    sync.Mutex
+	repos []model.GitHubRepository
+	log   []fakeStatement
+}
+
+type fakeStatement struct {
+	query string
+	args  []driver.Value
+}
+
+func (db *fakeDB) Connect(context.Context) (driver.Conn, error) { return &fakeConn{db: db}, nil }
+func (db *fakeDB) Driver() driver.Driver                        { return fakeDriver{} }
+
+// statements returns the recorded statements starting with prefix.
+func (db *fakeDB) statements(prefix string) []fakeStatement {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	var out []fakeStatement
+	for _, s := range db.log {
+		if strings.HasPrefix(s.query, prefix) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func (db *fakeDB) reset() {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.log = nil
+}
+
+func (db *fakeDB) record(query string, named []driver.NamedValue) []driver.Value {
+	args := make([]driver.Value, len(named))
+	for i, a := range named {
+		args[i] = a.Value
+	}
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.log = append(db.log, fakeStatement{query: query, args: args})
+	return args
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) {
+	return nil, errors.New("fakeDB: open with sql.OpenDB")
+}
+
+type fakeConn struct {
+	db *fakeDB
+}
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("fakeDB: prepared statements are not supported")
+}
+func (c *fakeConn) Close() error              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error) { return fakeTx{}, nil }
+
+// CheckNamedValue passes every argument through as is.
+func (c *fakeConn) CheckNamedValue(*driver.NamedValue) error { return nil }
+
+func (c *fakeConn) ExecContext(_ context.Context, query string, named []driver.NamedValue) (driver.Result, error) {
+	args := c.db.record(query, named)
+
+	// SaveRepoLanguages stores the ETag: SET languages_e_tag, languages_synced_at WHERE id
+	if strings.HasPrefix(query, `UPDATE "git_hub_repositories" SET "languages_e_tag"`) {
+		c.db.mu.Lock()
+		for i := range c.db.repos {
+			if fmt.Sprint(c.db.repos[i].ID) == fmt.Sprint(args[len(args)-1]) {
+				c.db.repos[i].LanguagesETag, _ = args[0].(string)
+			}
+		}
+		c.db.mu.Unlock()
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (c *fakeConn) QueryContext(_ context.Context, query string, named []driver.NamedValue) (driver.Rows, error) {
+	c.db.record(query, named)
+
+	rows := &fakeRows{}
+	if strings.HasPrefix(query, `SELECT * FROM "git_hub_repositories"`) && strings.Contains(query, "languages_synced_at") {
+		rows.columns = []string{"id", "user_id", "full_name", "languages_e_tag"}
+		c.db.mu.Lock()
+		for _, r := range c.db.repos {
+			rows.values = append(rows.values, []driver.Value{int64(r.ID), int64(r.UserID), r.FullName, r.LanguagesETag})
+		}
+		c.db.mu.Unlock()
+	}
+	return rows, nil
+}
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error   { return nil }
+func (fakeTx) Rollback() error { return nil }
+
+type fakeRows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
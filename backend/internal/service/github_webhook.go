@@ -0,0 +1,315 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/norman6464/devsync/backend/internal/model"
+	"github.com/norman6464/devsync/backend/internal/queue"
+	"github.com/norman6464/devsync/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+// ErrInvalidWebhookSignature is returned for deliveries not signed with the webhook secret.
+var ErrInvalidWebhookSignature = errors.New("invalid webhook signature")
+
+// GitHubWebhookService ingests GitHub webhook deliveries: it records an
+// activity stream for connected users, keeps repository stars and forks and
+// contribution counts current between syncs, and can post notable events to
+// the user's timeline.
+type GitHubWebhookService struct {
+	secret              string
+	userRepo            *repository.UserRepository
+	githubRepo          *repository.GitHubRepository
+	activityRepo        *repository.GitHubActivityRepository
+	postRepo            *repository.PostRepository
+	notificationService *NotificationService
+	jobs                *queue.Queue
+}
+
+// githubWebhookJob is a verified delivery waiting to be processed.
+type githubWebhookJob struct {
+	Event      string          `json:"event"`
+	DeliveryID string          `json:"delivery_id"`
+	Payload    json.RawMessage `json:"payload"`
+}
+
+// githubEvent holds the fields we use from push, pull_request, issues and
+// release payloads.
+type githubEvent struct {
+	Action     string `json:"action"`
+	Ref        string `json:"ref"`
+	Compare    string `json:"compare"`
+	Repository struct {
+		ID            int64  `json:"id"`
+		FullName      string `json:"full_name"`
+		Private       bool   `json:"private"`
+		HTMLURL       string `json:"html_url"`
+		Stars         int    `json:"stargazers_count"`
+		Forks         int    `json:"forks_count"`
+		DefaultBranch string `json:"default_branch"`
+	} `json:"repository"`
+	Sender struct {
+		ID int64 `json:"id"`
+	} `json:"sender"`
+	Commits []struct {
+		Distinct bool   `json:"distinct"`
+		Message  string `json:"message"`
+	} `json:"commits"`
+	HeadCommit *struct {
+		Message   string    `json:"message"`
+		Timestamp time.Time `json:"timestamp"`
+	} `json:"head_commit"`
+	PullRequest *struct {
+		Title     string     `json:"title"`
+		HTMLURL   string     `json:"html_url"`
+		Merged    bool       `json:"merged"`
+		UpdatedAt time.Time  `json:"updated_at"`
+		MergedAt  *time.Time `json:"merged_at"`
+	} `json:"pull_request"`
+	Issue *struct {
+		Title     string    `json:"title"`
+		HTMLURL   string    `json:"html_url"`
+		UpdatedAt time.Time `json:"updated_at"`
+	} `json:"issue"`
+	Release *struct {
+		Name        string     `json:"name"`
+		TagName     string     `json:"tag_name"`
+		HTMLURL     string     `json:"html_url"`
+		PublishedAt *time.Time `json:"published_at"`
+	} `json:"release"`
+}
+
+func NewGitHubWebhookService(secret string, userRepo *repository.UserRepository, githubRepo *repository.GitHubRepository,
+	activityRepo *repository.GitHubActivityRepository, postRepo *repository.PostRepository,
+	notificationService *NotificationService, jobs *queue.Queue) *GitHubWebhookService {
+	s := &GitHubWebhookService{
+		secret:              secret,
+		userRepo:            userRepo,
+		githubRepo:          githubRepo,
+		activityRepo:        activityRepo,
+		postRepo:            postRepo,
+		notificationService: notificationService,
+		jobs:                jobs,
+	}
+	jobs.Register(queue.JobType{Name: JobGitHubWebhook, Queue: "github", Handler: s.run})
+	return s
+}
+
+// Enabled reports whether a webhook secret is configured.
+func (s *GitHubWebhookService) Enabled() bool {
+	return s.secret != ""
+}
+
+// Verify checks the X-Hub-Signature-256 header against the payload.
+func (s *GitHubWebhookService) Verify(signature string, payload []byte) error {
+	sig, err := hex.DecodeString(strings.TrimPrefix(signature, "sha256="))
+	if err != nil || !strings.HasPrefix(signature, "sha256=") {
+		return ErrInvalidWebhookSignature
+	}
+	mac := hmac.New(sha256.New, []byte(s.secret))
+	mac.Write(payload)
+	if !hmac.Equal(sig, mac.Sum(nil)) {
+		return ErrInvalidWebhookSignature
+	}
+	return nil
+}
+
+// Enqueue queues a verified delivery for processing.
+func (s *GitHubWebhookService) Enqueue(event, deliveryID string, payload []byte) error {
+	return s.jobs.Enqueue(JobGitHubWebhook, githubWebhookJob{Event: event, DeliveryID: deliveryID, Payload: payload})
+}
+
+func (s *GitHubWebhookService) run(ctx context.Context, payload json.RawMessage) error {
+	var job githubWebhookJob
+	if err := queue.Decode(payload, &job); err != nil {
+		return err
+	}
+	var event githubEvent
+	if err := json.Unmarshal(job.Payload, &event); err != nil {
+		return fmt.Errorf("decode %s event: %w", job.Event, err)
+	}
+	return s.process(job.Event, job.DeliveryID, &event)
+}
+
+func (s *GitHubWebhookService) process(eventName, deliveryID string, event *githubEvent) error {
+	if event.Repository.ID == 0 {
+		return nil
+	}
+
+	// Every event carries the repository's current counts (including star and fork events)
+	if err := s.githubRepo.UpdateRepoCounts(event.Repository.ID, event.Repository.Stars, event.Repository.Forks); err != nil {
+		return err
+	}
+
+	activity, contributions := buildGitHubActivity(eventName, event)
+	if activity == nil {
+		return nil
+	}
+
+	// Activity belongs to the connected user who triggered it
+	user, err := s.userRepo.FindByGitHubID(event.Sender.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if !user.GitHubConnected || user.DeletionScheduledAt != nil {
+		return nil
+	}
+
+	activity.UserID = user.ID
+	activity.DeliveryID = deliveryID
+	created, err := s.activityRepo.CreateWithContributions(activity, contributions)
+	if err != nil || !created {
+		// Redelivered events were already counted
+		return err
+	}
+
+	if user.GitHubAutoPost && !activity.IsPrivate {
+		return s.autoPost(user.ID, activity)
+	}
+	return nil
+}
+
+// buildGitHubActivity turns an event into an activity and the number of
+// contributions it adds, following GitHub's rules: commits pushed to the
+// default branch, and opened pull requests and issues. It returns nil for
+// events that aren't part of the activity stream.
+func buildGitHubActivity(eventName string, event *githubEvent) (*model.GitHubActivity, int) {
+	activity := &model.GitHubActivity{
+		Event:        eventName,
+		Action:       event.Action,
+		RepoFullName: event.Repository.FullName,
+		IsPrivate:    event.Repository.Private,
+		OccurredAt:   time.Now(),
+	}
+	contributions := 0
+
+	switch eventName {
+	case "push":
+		branch := strings.TrimPrefix(event.Ref, "refs/heads/")
+		if branch == event.Ref || event.HeadCommit == nil {
+			// Tag pushes and branch deletions
+			return nil, 0
+		}
+		for _, commit := range event.Commits {
+			if commit.Distinct {
+				activity.CommitCount++
+			}
+		}
+		if activity.CommitCount == 0 {
+			return nil, 0
+		}
+		activity.Ref = branch
+		activity.Title = firstLine(event.HeadCommit.Message)
+		activity.URL = event.Compare
+		if !event.HeadCommit.Timestamp.IsZero() {
+			activity.OccurredAt = event.HeadCommit.Timestamp
+		}
+		if branch == event.Repository.DefaultBranch {
+			contributions = activity.CommitCount
+		}
+
+	case "pull_request":
+		pr := event.PullRequest
+		if pr == nil {
+			return nil, 0
+		}
+		switch event.Action {
+		case "opened":
+			contributions = 1
+		case "closed":
+			if pr.Merged {
+				activity.Action = "merged"
+			}
+		case "reopened":
+		default:
+			return nil, 0
+		}
+		activity.Title = pr.Title
+		activity.URL = pr.HTMLURL
+		activity.OccurredAt = pr.UpdatedAt
+		if pr.MergedAt != nil {
+			activity.OccurredAt = *pr.MergedAt
+		}
+
+	case "issues":
+		issue := event.Issue
+		if issue == nil {
+			return nil, 0
+		}
+		switch event.Action {
+		case "opened":
+			contributions = 1
+		case "closed", "reopened":
+		default:
+			return nil, 0
+		}
+		activity.Title = issue.Title
+		activity.URL = issue.HTMLURL
+		activity.OccurredAt = issue.UpdatedAt
+
+	case "release":
+		release := event.Release
+		if release == nil || event.Action != "published" {
+			return nil, 0
+		}
+		activity.Title = release.Name
+		if activity.Title == "" {
+			activity.Title = release.TagName
+		}
+		activity.Ref = release.TagName
+		activity.URL = release.HTMLURL
+		if release.PublishedAt != nil {
+			activity.OccurredAt = *release.PublishedAt
+		}
+
+	default:
+		return nil, 0
+	}
+
+	if activity.OccurredAt.IsZero() {
+		activity.OccurredAt = time.Now()
+	}
+	return activity, contributions
+}
+
+// autoPost shares merged pull requests and published releases on the
+// user's timeline.
+func (s *GitHubWebhookService) autoPost(userID uint, activity *model.GitHubActivity) error {
+	var title string
+	switch {
+	case activity.Event == "release":
+		title = fmt.Sprintf("Released %s %s", activity.RepoFullName, activity.Ref)
+	case activity.Event == "pull_request" && activity.Action == "merged":
+		title = fmt.Sprintf("Merged a pull request into %s", activity.RepoFullName)
+	default:
+		return nil
+	}
+
+	post := &model.Post{
+		UserID:  userID,
+		Title:   title,
+		Content: activity.Title + "\n" + activity.URL,
+	}
+	if err := s.postRepo.Create(post); err != nil {
+		return err
+	}
+	s.notificationService.NotifyFollowers(post.ID, userID)
+	return nil
+}
+
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/norman6464/devsync/backend/internal/config"
+	"github.com/norman6464/devsync/backend/internal/model"
+)
+
+// gitlabSkippedEvents are event actions that don't count as contributions.
+var gitlabSkippedEvents = map[string]bool{
+	"joined":   true,
+	"left":     true,
+	"deleted":  true,
+	"imported": true,
+	"expired":  true,
+}
+
+// GitLabService talks to gitlab.com or a self-hosted GitLab through API v4.
+type GitLabService struct {
+	cfg    *config.Config
+	client *http.Client
+}
+
+func NewGitLabService(cfg *config.Config, client *http.Client) *GitLabService {
+	return &GitLabService{cfg: cfg, client: client}
+}
+
+func (s *GitLabService) Provider() model.IntegrationProvider {
+	return model.IntegrationGitLab
+}
+
+func (s *GitLabService) Enabled() bool {
+	return s.cfg.GitLabClientID != ""
+}
+
+func (s *GitLabService) OAuthURL(state string) string {
+	return fmt.Sprintf(
+		"%s/oauth/authorize?client_id=%s&redirect_uri=%s&response_type=code&scope=read_api+read_user&state=%s",
+		s.cfg.GitLabBaseURL, s.cfg.GitLabClientID, url.QueryEscape(s.cfg.GitLabRedirectURL), state,
+	)
+}
+
+func (s *GitLabService) ExchangeCode(code string) (*CodeHostToken, error) {
+	return s.requestToken(url.Values{
+		"grant_type":   {"authorization_code"},
+		"code":         {code},
+		"redirect_uri": {s.cfg.GitLabRedirectURL},
+	})
+}
+
+func (s *GitLabService) RefreshToken(refreshToken string) (*CodeHostToken, error) {
+	return s.requestToken(url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
+		"redirect_uri":  {s.cfg.GitLabRedirectURL},
+	})
+}
+
+func (s *GitLabService) requestToken(form url.Values) (*CodeHostToken, error) {
+	form.Set("client_id", s.cfg.GitLabClientID)
+	form.Set("client_secret", s.cfg.GitLabClientSecret)
+	req, err := http.NewRequest("POST", s.cfg.GitLabBaseURL+"/oauth/token", strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	return doOAuthTokenRequest(s.client, "gitlab", req)
+}
+
+func (s *GitLabService) GetAccount(accessToken string) (*CodeHostAccountInfo, error) {
+	var user struct {
+		ID        int64  `json:"id"`
+		Username  string `json:"username"`
+		AvatarURL string `json:"avatar_url"`
+		WebURL    string `json:"web_url"`
+	}
+	if _, err := codeHostGet(s.client, "gitlab", s.cfg.GitLabBaseURL+"/api/v4/user", accessToken, &user); err != nil {
+		return nil, err
+	}
+	return &CodeHostAccountInfo{
+		ExternalID: strconv.FormatInt(user.ID, 10),
+		Username:   user.Username,
+		AvatarURL:  user.AvatarURL,
+		ProfileURL: user.WebURL,
+	}, nil
+}
+
+func (s *GitLabService) FetchRepos(accessToken string, account *model.CodeHostAccount) ([]CodeHostRepo, error) {
+	var repos []CodeHostRepo
+	page := "1"
+	for page != "" {
+		var projects []struct {
+			ID                int64     `json:"id"`
+			Name              string    `json:"name"`
+			PathWithNamespace string    `json:"path_with_namespace"`
+			Description       string    `json:"description"`
+			StarCount         int       `json:"star_count"`
+			ForksCount        int       `json:"forks_count"`
+			Visibility        string    `json:"visibility"`
+			WebURL            string    `json:"web_url"`
+			LastActivityAt    time.Time `json:"last_activity_at"`
+			Statistics        struct {
+				RepositorySize int64 `json:"repository_size"`
+			} `json:"statistics"`
+		}
+		url := fmt.Sprintf("%s/api/v4/projects?membership=true&statistics=true&order_by=last_activity_at&per_page=100&page=%s",
+			s.cfg.GitLabBaseURL, page)
+		resp, err := codeHostGet(s.client, "gitlab", url, accessToken, &projects)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, p := range projects {
+			repos = append(repos, CodeHostRepo{
+				CodeHostRepository: model.CodeHostRepository{
+					ExternalID:  strconv.FormatInt(p.ID, 10),
+					Name:        p.Name,
+					FullName:    p.PathWithNamespace,
+					Description: p.Description,
+					Stars:       p.StarCount,
+					Forks:       p.ForksCount,
+					IsPrivate:   p.Visibility != "public",
+					URL:         p.WebURL,
+				},
+				Size:     p.Statistics.RepositorySize,
+				ActiveAt: p.LastActivityAt,
+			})
+		}
+		page = resp.Header.Get("X-Next-Page")
+	}
+	return repos, nil
+}
+
+// FetchLanguages approximates bytes per language: GitLab reports languages as
+// percentages, which are applied to the repository size.
+func (s *GitLabService) FetchLanguages(accessToken string, repo *CodeHostRepo) (map[string]int64, error) {
+	var percentages map[string]float64
+	url := fmt.Sprintf("%s/api/v4/projects/%s/languages", s.cfg.GitLabBaseURL, repo.ExternalID)
+	if _, err := codeHostGet(s.client, "gitlab", url, accessToken, &percentages); err != nil {
+		return nil, err
+	}
+
+	langs := make(map[string]int64, len(percentages))
+	for lang, pct := range percentages {
+		langs[lang] = int64(pct / 100 * float64(repo.Size))
+	}
+	return langs, nil
+}
+
+// FetchContributions counts the account's events per day, like GitLab's own
+// contribution calendar.
+func (s *GitLabService) FetchContributions(accessToken string, account *model.CodeHostAccount, repos []CodeHostRepo, since time.Time) (map[string]int, error) {
+	counts := make(map[string]int)
+	// after is exclusive
+	after := since.AddDate(0, 0, -1).Format("2006-01-02")
+	page := "1"
+	for page != "" {
+		var events []struct {
+			ActionName string    `json:"action_name"`
+			CreatedAt  time.Time `json:"created_at"`
+		}
+		url := fmt.Sprintf("%s/api/v4/users/%s/events?after=%s&per_page=100&page=%s",
+			s.cfg.GitLabBaseURL, account.ExternalID, after, page)
+		resp, err := codeHostGet(s.client, "gitlab", url, accessToken, &events)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, e := range events {
+			if gitlabSkippedEvents[e.ActionName] {
+				continue
+			}
+			counts[e.CreatedAt.UTC().Format("2006-01-02")]++
+		}
+		page = resp.Header.Get("X-Next-Page")
+	}
+	return counts, nil
+}
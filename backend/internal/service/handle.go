@@ -0,0 +1,159 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/norman6464/devsync/backend/internal/model"
+	"github.com/norman6464/devsync/backend/internal/repository"
+)
+
+// HandleRedirectGracePeriod is how long an old handle keeps redirecting to its
+// previous owner (and stays unavailable to others) after a rename.
+const HandleRedirectGracePeriod = 30 * 24 * time.Hour
+
+const (
+	minHandleLength = 3
+	maxHandleLength = 39
+)
+
+var (
+	ErrHandleInvalid  = errors.New("handle must be 3-39 characters of lowercase letters, numbers or single hyphens, and cannot start or end with a hyphen")
+	ErrHandleReserved = errors.New("handle is reserved")
+	ErrHandleTaken    = repository.ErrHandleTaken
+)
+
+var handlePattern = regexp.MustCompile(`^[a-z0-9](?:[a-z0-9]|-[a-z0-9])*$`)
+
+// reservedHandles cannot be claimed because they collide with app routes or
+// could be used to impersonate staff.
+var reservedHandles = map[string]bool{
+	"about": true, "admin": true, "administrator": true, "api": true, "auth": true,
+	"badges": true, "by-handle": true, "chat-rooms": true, "devsync": true, "edit": true,
+	"explore": true, "github": true, "goals": true, "health": true, "help": true,
+	"login": true, "logout": true, "me": true, "mentions": true, "messages": true,
+	"moderator": true, "new": true, "notifications": true, "null": true, "posts": true,
+	"privacy": true, "projects": true, "public": true, "qiita": true, "questions": true,
+	"rankings": true, "register": true, "reports": true, "resources": true, "roadmaps": true,
+	"root": true, "search": true, "settings": true, "signup": true, "staff": true,
+	"support": true, "system": true, "terms": true, "undefined": true, "upload": true,
+	"uploads": true, "user": true, "users": true, "webhooks": true, "ws": true, "www": true,
+	"zenn": true,
+}
+
+type HandleService struct {
+	userRepo *repository.UserRepository
+}
+
+func NewHandleService(userRepo *repository.UserRepository) *HandleService {
+	return &HandleService{userRepo: userRepo}
+}
+
+// NormalizeHandle lowercases a handle and strips a leading @.
+func NormalizeHandle(handle string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(handle), "@"))
+}
+
+// ValidateHandle checks the format and reserved-word rules for a normalized handle.
+func ValidateHandle(handle string) error {
+	if len(handle) < minHandleLength || len(handle) > maxHandleLength || !handlePattern.MatchString(handle) {
+		return ErrHandleInvalid
+	}
+	if reservedHandles[handle] {
+		return ErrHandleReserved
+	}
+	return nil
+}
+
+// Change renames a user's handle. The previous handle redirects for
+// HandleRedirectGracePeriod.
+func (s *HandleService) Change(user *model.User, handle string) error {
+	handle = NormalizeHandle(handle)
+	if handle == user.Handle {
+		return nil
+	}
+	if err := ValidateHandle(handle); err != nil {
+		return err
+	}
+	if taken, err := s.userRepo.HandleTaken(handle, user.ID); err != nil {
+		return err
+	} else if taken {
+		return ErrHandleTaken
+	}
+	return s.userRepo.ChangeHandle(user, handle, time.Now().Add(HandleRedirectGracePeriod))
+}
+
+// Resolve finds the user for a handle. If the handle is an old one still in
+// its grace period, the current owner is returned with redirected set.
+func (s *HandleService) Resolve(handle string) (user *model.User, redirected bool, err error) {
+	handle = NormalizeHandle(handle)
+	user, err = s.userRepo.FindByHandle(handle)
+	if err == nil {
+		return user, false, nil
+	}
+
+	history, histErr := s.userRepo.FindHandleRedirect(handle)
+	if histErr != nil {
+		return nil, false, err
+	}
+	user, err = s.userRepo.FindByID(history.UserID)
+	if err != nil {
+		return nil, false, err
+	}
+	return user, true, nil
+}
+
+// Available picks a free handle derived from the given candidates (e.g. a
+// GitHub login or display name), adding a numeric suffix when needed.
+func (s *HandleService) Available(userID uint, candidates ...string) (string, error) {
+	base := "user"
+	for _, c := range candidates {
+		if slug := slugifyHandle(c); len(slug) >= minHandleLength {
+			base = slug
+			break
+		}
+	}
+
+	handle := base
+	for i := 2; ; i++ {
+		if ValidateHandle(handle) == nil {
+			taken, err := s.userRepo.HandleTaken(handle, userID)
+			if err != nil {
+				return "", err
+			}
+			if !taken {
+				return handle, nil
+			}
+		}
+		suffix := fmt.Sprintf("-%d", i)
+		trimmed := base
+		if len(trimmed)+len(suffix) > maxHandleLength {
+			trimmed = strings.TrimRight(trimmed[:maxHandleLength-len(suffix)], "-")
+		}
+		handle = trimmed + suffix
+	}
+}
+
+// slugifyHandle turns arbitrary text into something that passes handlePattern.
+func slugifyHandle(s string) string {
+	var b strings.Builder
+	lastHyphen := true
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+			lastHyphen = false
+		case !lastHyphen:
+			b.WriteByte('-')
+			lastHyphen = true
+		}
+	}
+	slug := strings.Trim(b.String(), "-")
+	if len(slug) > maxHandleLength {
+		slug = strings.TrimRight(slug[:maxHandleLength], "-")
+	}
+	return slug
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// NewHTTPClient returns a client for calling external APIs: every request
+// has a timeout, and retryable requests are retried with backoff when the
+// server fails with a 5xx status or the connection fails.
+func NewHTTPClient(timeout time.Duration, maxRetries int) *http.Client {
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &retryTransport{
+			next:       http.DefaultTransport,
+			maxRetries: maxRetries,
+			baseDelay:  500 * time.Millisecond,
+		},
+	}
+}
+
+type retryableKey struct{}
+
+// retryable marks a non-GET request as safe to send more than once, such as
+// a GraphQL query. GET and HEAD requests are always retried.
+func retryable(req *http.Request) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), retryableKey{}, true))
+}
+
+type retryTransport struct {
+	next       http.RoundTripper
+	maxRetries int
+	baseDelay  time.Duration
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	canRetry := req.Method == http.MethodGet || req.Method == http.MethodHead ||
+		req.Context().Value(retryableKey{}) != nil
+	if req.Body != nil && req.GetBody == nil {
+		// The body can't be replayed
+		canRetry = false
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := t.next.RoundTrip(req)
+		if !canRetry || attempt >= t.maxRetries || (err == nil && resp.StatusCode < 500) {
+			return resp, err
+		}
+		if err == nil {
+			resp.Body.Close()
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(t.baseDelay << uint(attempt)):
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package service
+
+import "github.com/norman6464/devsync/backend/internal/queue"
+
+// Background job types. Each service registers the handlers for its own jobs
+// when it is constructed.
+const (
+	JobGitHubSync           = "github.sync"
+	JobGitHubBackfill       = "github.backfill"
+	JobGitLabSync           = "gitlab.sync"
+	JobBitbucketSync        = "bitbucket.sync"
+	JobZennSync             = "zenn.sync"
+	JobQiitaSync            = "qiita.sync"
+	JobFeedSync             = "feed.sync"
+	JobSyncScheduleDue      = "sync.schedule_due"
+	JobGitHubWebhook        = "github.webhook"
+	JobNotifyFollowers      = "notification.followers"
+	JobNotifyMessage        = "notification.message"
+	JobMentionSync          = "mention.sync"
+	JobDataExportBuild      = "data_export.build"
+	JobDataExportPurge      = "data_export.purge_expired"
+	JobAccountDeletionPurge = "account_deletion.purge_due"
+	JobRankingSnapshot      = "ranking.snapshot"
+	JobBadgeEvaluate        = "badge.evaluate"
+	JobBadgeEvaluateAll     = "badge.evaluate_all"
+)
+
+// ScheduleJobs sets up periodic work.
+func ScheduleJobs(q *queue.Queue) error {
+	schedules := []struct {
+		name, spec, jobType string
+	}{
+		// Queue re-syncs of connected GitHub, GitLab, Bitbucket, Zenn and Qiita accounts and feeds
+		{"integration-sync", "*/5 * * * *", JobSyncScheduleDue},
+		// Permanently delete accounts whose deletion grace period has ended
+		{"account-deletion-purge", "0 * * * *", JobAccountDeletionPurge},
+		// Remove takeout archives whose download link has lapsed
+		{"data-export-purge", "30 * * * *", JobDataExportPurge},
+		// Snapshot every leaderboard, notifying users who climbed since yesterday
+		{"ranking-snapshot", "15 * * * *", JobRankingSnapshot},
+		// Award badges that depend on the day, such as contribution streaks
+		{"badge-evaluate-all", "45 0 * * *", JobBadgeEvaluateAll},
+	}
+	for _, s := range schedules {
+		if err := q.Schedule(s.name, s.spec, s.jobType, struct{}{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
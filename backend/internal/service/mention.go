@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/norman6464/devsync/backend/internal/model"
+	"github.com/norman6464/devsync/backend/internal/queue"
+	"github.com/norman6464/devsync/backend/internal/repository"
+)
+
+// maxMentionsPerContent caps how many users a single piece of content can notify
+const maxMentionsPerContent = 20
+
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@])@([A-Za-z0-9][A-Za-z0-9-]*)`)
+
+// ParseMentions extracts the unique, lowercased @handles from text in order of appearance.
+func ParseMentions(text string) []string {
+	var handles []string
+	seen := make(map[string]bool)
+	for _, m := range mentionPattern.FindAllStringSubmatch(text, -1) {
+		handle := strings.ToLower(strings.TrimRight(m[1], "-"))
+		if handle == "" || seen[handle] {
+			continue
+		}
+		seen[handle] = true
+		handles = append(handles, handle)
+		if len(handles) >= maxMentionsPerContent {
+			break
+		}
+	}
+	return handles
+}
+
+// MentionTarget describes where a mention was written, so the mention and its
+// notification can link back to the content.
+type MentionTarget struct {
+	SourceType model.MentionSourceType
+	SourceID   uint
+	PostID     *uint
+	QuestionID *uint
+	ChatRoomID *uint
+}
+
+type MentionService struct {
+	mentionRepo      *repository.MentionRepository
+	userRepo         *repository.UserRepository
+	notificationRepo *repository.NotificationRepository
+	jobs             *queue.Queue
+}
+
+type mentionSyncJob struct {
+	ActorID  uint          `json:"actor_id"`
+	Target   MentionTarget `json:"target"`
+	Content  string        `json:"content"`
+	Audience []uint        `json:"audience"`
+}
+
+func NewMentionService(mentionRepo *repository.MentionRepository, userRepo *repository.UserRepository, notificationRepo *repository.NotificationRepository, jobs *queue.Queue) *MentionService {
+	s := &MentionService{mentionRepo: mentionRepo, userRepo: userRepo, notificationRepo: notificationRepo, jobs: jobs}
+	jobs.Register(queue.JobType{Name: JobMentionSync, Handler: s.runSync})
+	return s
+}
+
+// EnqueueSync runs Sync as a background job.
+func (s *MentionService) EnqueueSync(actorID uint, target MentionTarget, content string, audience []uint) {
+	job := mentionSyncJob{ActorID: actorID, Target: target, Content: content, Audience: audience}
+	if err := s.jobs.Enqueue(JobMentionSync, job); err != nil {
+		log.Printf("mention: failed to enqueue sync for %s %d: %v", target.SourceType, target.SourceID, err)
+	}
+}
+
+func (s *MentionService) runSync(ctx context.Context, payload json.RawMessage) error {
+	var job mentionSyncJob
+	if err := queue.Decode(payload, &job); err != nil {
+		return err
+	}
+	return s.Sync(job.ActorID, job.Target, job.Content, job.Audience)
+}
+
+// Sync brings the stored mentions for target in line with content. Newly
+// mentioned users get a mention notification; users no longer mentioned are
+// removed. If audience is non-nil, only users in it can be mentioned (e.g. the
+// members of a chat room), so private content never leaks via notifications.
+func (s *MentionService) Sync(actorID uint, target MentionTarget, content string, audience []uint) error {
+	userIDs, err := s.resolve(ParseMentions(content), actorID, audience)
+	if err != nil {
+		return err
+	}
+
+	existingIDs, err := s.mentionRepo.FindUserIDsBySource(target.SourceType, target.SourceID)
+	if err != nil {
+		return err
+	}
+	existing := make(map[uint]bool, len(existingIDs))
+	for _, id := range existingIDs {
+		existing[id] = true
+	}
+	wanted := make(map[uint]bool, len(userIDs))
+	for _, id := range userIDs {
+		wanted[id] = true
+	}
+
+	var removed []uint
+	for _, id := range existingIDs {
+		if !wanted[id] {
+			removed = append(removed, id)
+		}
+	}
+	if err := s.mentionRepo.DeleteBySourceUsers(target.SourceType, target.SourceID, removed); err != nil {
+		return err
+	}
+
+	excerpt := mentionExcerpt(content)
+	var mentions []*model.Mention
+	var notifications []*model.Notification
+	for _, id := range userIDs {
+		if existing[id] {
+			continue
+		}
+		mentions = append(mentions, &model.Mention{
+			UserID:     id,
+			ActorID:    actorID,
+			SourceType: target.SourceType,
+			SourceID:   target.SourceID,
+			PostID:     target.PostID,
+			QuestionID: target.QuestionID,
+			ChatRoomID: target.ChatRoomID,
+			Excerpt:    excerpt,
+		})
+		notifications = append(notifications, &model.Notification{
+			UserID:     id,
+			Type:       model.NotificationTypeMention,
+			ActorID:    actorID,
+			PostID:     target.PostID,
+			QuestionID: target.QuestionID,
+			ChatRoomID: target.ChatRoomID,
+		})
+	}
+	if err := s.mentionRepo.CreateBatch(mentions); err != nil {
+		return err
+	}
+	return s.notificationRepo.CreateBatch(notifications)
+}
+
+// Remove deletes all mentions recorded for a piece of content.
+func (s *MentionService) Remove(sourceType model.MentionSourceType, sourceID uint) error {
+	return s.mentionRepo.DeleteBySource(sourceType, sourceID)
+}
+
+// resolve maps @handles to user IDs. The actor never mentions themselves.
+func (s *MentionService) resolve(handles []string, actorID uint, audience []uint) ([]uint, error) {
+	if len(handles) == 0 {
+		return nil, nil
+	}
+	users, err := s.userRepo.FindByHandles(handles)
+	if err != nil {
+		return nil, err
+	}
+
+	var allowed map[uint]bool
+	if audience != nil {
+		allowed = make(map[uint]bool, len(audience))
+		for _, id := range audience {
+			allowed[id] = true
+		}
+	}
+
+	var ids []uint
+	for _, u := range users {
+		if u.ID == actorID || (allowed != nil && !allowed[u.ID]) {
+			continue
+		}
+		ids = append(ids, u.ID)
+	}
+	return ids, nil
+}
+
+func mentionExcerpt(content string) string {
+	runes := []rune(strings.TrimSpace(content))
+	if len(runes) > 280 {
+		return string(runes[:280]) + "…"
+	}
+	return string(runes)
+}
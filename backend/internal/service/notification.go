@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"github.com/norman6464/devsync/backend/internal/model"
+	"github.com/norman6464/devsync/backend/internal/queue"
+	"github.com/norman6464/devsync/backend/internal/repository"
+)
+
+// NotificationService creates notifications that fan out to many users or
+// shouldn't slow down the request that triggers them, via the job queue.
+type NotificationService struct {
+	notificationRepo *repository.NotificationRepository
+	jobs             *queue.Queue
+}
+
+type followersJob struct {
+	PostID  uint `json:"post_id"`
+	ActorID uint `json:"actor_id"`
+}
+
+type messageJob struct {
+	SenderID   uint `json:"sender_id"`
+	ReceiverID uint `json:"receiver_id"`
+}
+
+func NewNotificationService(notificationRepo *repository.NotificationRepository, jobs *queue.Queue) *NotificationService {
+	s := &NotificationService{notificationRepo: notificationRepo, jobs: jobs}
+	jobs.Register(queue.JobType{Name: JobNotifyFollowers, Handler: s.runNotifyFollowers})
+	jobs.Register(queue.JobType{Name: JobNotifyMessage, Handler: s.runNotifyMessage})
+	return s
+}
+
+// NotifyFollowers tells the actor's followers about their new post.
+func (s *NotificationService) NotifyFollowers(postID, actorID uint) {
+	if err := s.jobs.Enqueue(JobNotifyFollowers, followersJob{PostID: postID, ActorID: actorID}); err != nil {
+		log.Printf("notification: failed to enqueue follower fan-out for post %d: %v", postID, err)
+	}
+}
+
+// NotifyMessage tells the receiver of a direct message about it.
+func (s *NotificationService) NotifyMessage(senderID, receiverID uint) {
+	if err := s.jobs.Enqueue(JobNotifyMessage, messageJob{SenderID: senderID, ReceiverID: receiverID}); err != nil {
+		log.Printf("notification: failed to enqueue message notification: %v", err)
+	}
+}
+
+func (s *NotificationService) runNotifyFollowers(ctx context.Context, payload json.RawMessage) error {
+	var job followersJob
+	if err := queue.Decode(payload, &job); err != nil {
+		return err
+	}
+	followerIDs, err := s.notificationRepo.GetFollowerIDs(job.ActorID)
+	if err != nil || len(followerIDs) == 0 {
+		return err
+	}
+	// A job rerun after its worker died must not notify anyone twice
+	notified, err := s.notificationRepo.FindUserIDsByPost(model.NotificationTypePost, job.PostID)
+	if err != nil {
+		return err
+	}
+	skip := make(map[uint]bool, len(notified))
+	for _, id := range notified {
+		skip[id] = true
+	}
+
+	var notifications []*model.Notification
+	for _, followerID := range followerIDs {
+		if skip[followerID] {
+			continue
+		}
+		postID := job.PostID
+		notifications = append(notifications, &model.Notification{
+			UserID:  followerID,
+			Type:    model.NotificationTypePost,
+			ActorID: job.ActorID,
+			PostID:  &postID,
+		})
+	}
+	return s.notificationRepo.CreateBatch(notifications)
+}
+
+func (s *NotificationService) runNotifyMessage(ctx context.Context, payload json.RawMessage) error {
+	var job messageJob
+	if err := queue.Decode(payload, &job); err != nil {
+		return err
+	}
+	return s.notificationRepo.Create(&model.Notification{
+		UserID:  job.ReceiverID,
+		Type:    model.NotificationTypeMessage,
+		ActorID: job.SenderID,
+	})
+}
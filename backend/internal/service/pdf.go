@@ -0,0 +1,232 @@
+package service
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"strings"
+	"unicode/utf16"
+)
+
+// A minimal PDF writer for generated documents such as résumés. It supports
+// wrapped text in a single column on A4 pages, which is all we need, without
+// pulling in a PDF library.
+//
+// Latin text uses the standard Helvetica fonts. Lines containing other
+// characters (e.g. Japanese article titles) use the non-embedded
+// HeiseiKakuGo-W5 CID font, which PDF viewers supply themselves.
+
+const (
+	pdfPageWidth  = 595.28
+	pdfPageHeight = 841.89
+	pdfMargin     = 50.0
+)
+
+type pdfDocument struct {
+	pages [][]byte
+	page  bytes.Buffer
+	y     float64
+}
+
+func newPDFDocument() *pdfDocument {
+	d := &pdfDocument{}
+	d.y = pdfPageHeight - pdfMargin
+	return d
+}
+
+// Text writes a wrapped paragraph.
+func (d *pdfDocument) Text(text string, size float64, bold bool) {
+	d.textAt(pdfMargin, text, size, bold)
+}
+
+// Heading writes a section title with a rule underneath.
+func (d *pdfDocument) Heading(text string) {
+	d.Space(10)
+	// Keep the heading on the same page as at least its first line
+	d.ensureSpace(40)
+	d.textAt(pdfMargin, text, 14, true)
+	d.ensureSpace(6)
+	fmt.Fprintf(&d.page, "0.6 G 0.5 w %.2f %.2f m %.2f %.2f l S 0 G\n", pdfMargin, d.y, pdfPageWidth-pdfMargin, d.y)
+	d.y -= 6
+}
+
+// Bullet writes a wrapped list item.
+func (d *pdfDocument) Bullet(text string) {
+	const size = 10
+	d.ensureSpace(size * 1.4)
+	d.showLine(pdfMargin+4, d.y-size, "-", size, false)
+	d.textAt(pdfMargin+14, text, size, false)
+}
+
+// Space adds vertical whitespace.
+func (d *pdfDocument) Space(points float64) {
+	d.y -= points
+}
+
+func (d *pdfDocument) textAt(x float64, text string, size float64, bold bool) {
+	lineHeight := size * 1.4
+	for _, paragraph := range strings.Split(text, "\n") {
+		for _, line := range pdfWrap(paragraph, size, pdfPageWidth-pdfMargin-x) {
+			d.ensureSpace(lineHeight)
+			d.showLine(x, d.y-size, line, size, bold)
+			d.y -= lineHeight
+		}
+	}
+}
+
+func (d *pdfDocument) ensureSpace(height float64) {
+	if d.y-height >= pdfMargin {
+		return
+	}
+	d.pages = append(d.pages, append([]byte(nil), d.page.Bytes()...))
+	d.page.Reset()
+	d.y = pdfPageHeight - pdfMargin
+}
+
+func (d *pdfDocument) showLine(x, y float64, line string, size float64, bold bool) {
+	font, encoded := pdfEncode(line)
+	if font == "F1" && bold {
+		font = "F2"
+	}
+	fmt.Fprintf(&d.page, "BT /%s %.1f Tf %.2f %.2f Td <%s> Tj ET\n", font, size, x, y, encoded)
+}
+
+// winAnsiExtras maps the few non-Latin-1 characters we use to WinAnsiEncoding.
+var winAnsiExtras = map[rune]byte{'•': 0x95, '–': 0x96, '—': 0x97, '…': 0x85, '‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94}
+
+func pdfIsLatin(r rune) bool {
+	_, ok := winAnsiExtras[r]
+	return ok || (r < 0x100 && (r < 0x80 || r >= 0xA0))
+}
+
+// pdfEncode returns the font to use for a line and the line as a hex string.
+func pdfEncode(line string) (font, hex string) {
+	latin := true
+	for _, r := range line {
+		if !pdfIsLatin(r) {
+			latin = false
+			break
+		}
+	}
+
+	var b strings.Builder
+	if latin {
+		for _, r := range line {
+			c, ok := winAnsiExtras[r]
+			if !ok {
+				c = byte(r)
+			}
+			fmt.Fprintf(&b, "%02X", c)
+		}
+		return "F1", b.String()
+	}
+
+	for _, r := range line {
+		if r > 0xFFFF || utf16.IsSurrogate(r) {
+			// The UCS2 CMap only covers the Basic Multilingual Plane
+			r = '?'
+		}
+		fmt.Fprintf(&b, "%04X", r)
+	}
+	return "F3", b.String()
+}
+
+// pdfRuneWidth approximates glyph advance as a fraction of the font size.
+func pdfRuneWidth(r rune) float64 {
+	switch {
+	case r == ' ' || r == 'i' || r == 'l' || r == 'j' || r == '.' || r == ',' || r == '\'':
+		return 0.28
+	case r >= 'A' && r <= 'Z' || r == 'm' || r == 'w':
+		return 0.67
+	case pdfIsLatin(r):
+		return 0.56
+	default:
+		return 1.0
+	}
+}
+
+// pdfWrap breaks text into lines that fit width, preferring spaces and
+// falling back to breaking anywhere for text without them (e.g. Japanese).
+func pdfWrap(text string, size, width float64) []string {
+	runes := []rune(strings.TrimRight(text, " "))
+	if len(runes) == 0 {
+		return []string{""}
+	}
+
+	var lines []string
+	start, lastSpace := 0, -1
+	lineWidth := 0.0
+	for i := 0; i < len(runes); i++ {
+		if runes[i] == ' ' {
+			lastSpace = i
+		}
+		lineWidth += pdfRuneWidth(runes[i]) * size
+		if lineWidth <= width || i == start {
+			continue
+		}
+		end := i
+		if lastSpace > start {
+			end = lastSpace
+		}
+		lines = append(lines, string(runes[start:end]))
+		for end < len(runes) && runes[end] == ' ' {
+			end++
+		}
+		start, lastSpace, lineWidth = end, -1, 0
+		i = end - 1
+	}
+	if start < len(runes) {
+		lines = append(lines, string(runes[start:]))
+	}
+	return lines
+}
+
+// Bytes serializes the document.
+func (d *pdfDocument) Bytes() []byte {
+	pages := append(d.pages, d.page.Bytes())
+
+	var out bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xE2\xE3\xCF\xD3\n")
+
+	const firstPage = 8
+	var kids []string
+	for i := range pages {
+		kids = append(kids, fmt.Sprintf("%d 0 R", firstPage+i*2))
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type0 /BaseFont /HeiseiKakuGo-W5 /Encoding /UniJIS-UCS2-HW-H /DescendantFonts [6 0 R] >>")
+	object("<< /Type /Font /Subtype /CIDFontType0 /BaseFont /HeiseiKakuGo-W5 " +
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (Japan1) /Supplement 2 >> " +
+		"/FontDescriptor 7 0 R /DW 1000 /W [231 325 500] >>")
+	object("<< /Type /FontDescriptor /FontName /HeiseiKakuGo-W5 /Flags 4 /FontBBox [-92 -250 1010 922] " +
+		"/ItalicAngle 0 /Ascent 752 /Descent -221 /CapHeight 737 /StemV 114 >>")
+
+	for i, content := range pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] "+
+			"/Resources << /Font << /F1 3 0 R /F2 4 0 R /F3 5 0 R >> >> /Contents %d 0 R >>",
+			pdfPageWidth, pdfPageHeight, firstPage+i*2+1))
+
+		var compressed bytes.Buffer
+		zw := zlib.NewWriter(&compressed)
+		zw.Write(content)
+		zw.Close()
+		object(fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", compressed.Len(), compressed.Bytes()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return out.Bytes()
+}
@@ -2,24 +2,59 @@ package service
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/norman6464/devsync/backend/internal/model"
 )
 
+// qiitaPerPage is the most items Qiita returns per page
+const qiitaPerPage = 100
+
+// maxQiitaRetryWait caps how long a rate-limited request waits to be retried;
+// longer limits end the sync and it resumes once the limit resets
+const maxQiitaRetryWait = time.Minute
+
+// QiitaService reads articles from Qiita. It is a TokenArticleSource: with
+// the user's access token it is allowed 1000 requests an hour instead of 60
+// and also reads their limited-share items.
 type QiitaService struct {
 	httpClient *http.Client
+	apiURL     string
 }
 
-func NewQiitaService() *QiitaService {
+func NewQiitaService(httpClient *http.Client) *QiitaService {
 	return &QiitaService{
-		httpClient: &http.Client{Timeout: 30 * time.Second},
+		httpClient: httpClient,
+		apiURL:     "https://qiita.com/api/v2",
 	}
 }
 
+func (s *QiitaService) Source() model.IntegrationProvider {
+	return model.IntegrationQiita
+}
+
+func (s *QiitaService) Username(user *model.User) string {
+	return user.QiitaUsername
+}
+
+func (s *QiitaService) SetUsername(user *model.User, username string) {
+	user.QiitaUsername = username
+}
+
+func (s *QiitaService) Token(user *model.User) string {
+	return user.QiitaToken
+}
+
+func (s *QiitaService) SetToken(user *model.User, encrypted string) {
+	user.QiitaToken = encrypted
+}
+
 // QiitaAPIArticle represents an article from Qiita API
 type QiitaAPIArticle struct {
 	ID            string          `json:"id"`
@@ -27,7 +62,9 @@ type QiitaAPIArticle struct {
 	URL           string          `json:"url"`
 	LikesCount    int             `json:"likes_count"`
 	CommentsCount int             `json:"comments_count"`
+	StocksCount   int             `json:"stocks_count"`
 	Tags          []QiitaAPITag   `json:"tags"`
+	Private       bool            `json:"private"`
 	CreatedAt     time.Time       `json:"created_at"`
 }
 
@@ -35,57 +72,52 @@ type QiitaAPITag struct {
 	Name string `json:"name"`
 }
 
-// FetchArticles fetches all articles for a Qiita user
-func (s *QiitaService) FetchArticles(username string) ([]model.QiitaArticle, error) {
-	var allArticles []model.QiitaArticle
-	page := 1
-	perPage := 100
-
-	for {
-		url := fmt.Sprintf("https://qiita.com/api/v2/users/%s/items?page=%d&per_page=%d", username, page, perPage)
-
-		resp, err := s.httpClient.Get(url)
-		if err != nil {
-			return nil, fmt.Errorf("failed to fetch Qiita articles: %w", err)
-		}
-		defer resp.Body.Close()
-
-		if resp.StatusCode == http.StatusNotFound {
-			return nil, fmt.Errorf("Qiita user not found")
-		}
-
-		if resp.StatusCode != http.StatusOK {
-			return nil, fmt.Errorf("Qiita API returned status %d", resp.StatusCode)
-		}
+// FetchArticles fetches a Qiita user's articles, newest first. With a token
+// it reads the authenticated user's items, limited-share ones included and
+// marked Private. It stops at the first page holding an article fetch already
+// knows.
+func (s *QiitaService) FetchArticles(fetch ArticleFetch) ([]model.ExternalArticle, error) {
+	endpoint := fmt.Sprintf("%s/users/%s/items", s.apiURL, url.PathEscape(fetch.Username))
+	if fetch.Token != "" {
+		endpoint = s.apiURL + "/authenticated_user/items"
+	}
 
+	var allArticles []model.ExternalArticle
+	for page := 1; ; page++ {
 		var apiArticles []QiitaAPIArticle
-		if err := json.NewDecoder(resp.Body).Decode(&apiArticles); err != nil {
-			return nil, fmt.Errorf("failed to decode Qiita response: %w", err)
+		pageURL := fmt.Sprintf("%s?page=%d&per_page=%d", endpoint, page, qiitaPerPage)
+		if err := s.get(pageURL, fetch.Token, &apiArticles); err != nil {
+			return allArticles, err
 		}
 
+		reachedKnown := false
 		for _, article := range apiArticles {
+			if fetch.Known != nil && fetch.Known(article.ID) {
+				reachedKnown = true
+			}
 			// Extract tag names
 			tagNames := make([]string, len(article.Tags))
 			for i, tag := range article.Tags {
 				tagNames[i] = tag.Name
 			}
 
-			allArticles = append(allArticles, model.QiitaArticle{
-				QiitaID:       article.ID,
-				Title:         article.Title,
-				URL:           article.URL,
-				LikesCount:    article.LikesCount,
-				CommentsCount: article.CommentsCount,
-				Tags:          strings.Join(tagNames, ","),
-				PublishedAt:   article.CreatedAt,
+			allArticles = append(allArticles, model.ExternalArticle{
+				ExternalID:  article.ID,
+				Title:       article.Title,
+				URL:         article.URL,
+				Likes:       article.LikesCount,
+				Comments:    article.CommentsCount,
+				Stocks:      article.StocksCount,
+				Tags:        strings.Join(tagNames, ","),
+				Private:     article.Private,
+				PublishedAt: article.CreatedAt,
 			})
 		}
 
 		// Check if there are more pages
-		if len(apiArticles) < perPage {
+		if reachedKnown || len(apiArticles) < qiitaPerPage {
 			break
 		}
-		page++
 	}
 
 	return allArticles, nil
@@ -93,13 +125,103 @@ func (s *QiitaService) FetchArticles(username string) ([]model.QiitaArticle, err
 
 // ValidateUsername checks if a Qiita username exists
 func (s *QiitaService) ValidateUsername(username string) (bool, error) {
-	url := fmt.Sprintf("https://qiita.com/api/v2/users/%s", username)
+	var user qiitaAPIUser
+	err := s.get(fmt.Sprintf("%s/users/%s", s.apiURL, url.PathEscape(username)), "", &user)
+	if errors.Is(err, errQiitaNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// ValidateToken checks the token is valid and belongs to username
+func (s *QiitaService) ValidateToken(username, token string) error {
+	var user qiitaAPIUser
+	if err := s.get(s.apiURL+"/authenticated_user", token, &user); errors.Is(err, errQiitaUnauthorized) {
+		return ErrInvalidArticleToken
+	} else if err != nil {
+		return err
+	}
+	if !strings.EqualFold(user.ID, username) {
+		return ErrInvalidArticleToken
+	}
+	return nil
+}
+
+type qiitaAPIUser struct {
+	ID string `json:"id"`
+}
+
+var (
+	errQiitaNotFound     = errors.New("Qiita user not found")
+	errQiitaUnauthorized = errors.New("Qiita rejected the access token")
+)
 
-	resp, err := s.httpClient.Get(url)
-	if err != nil {
-		return false, err
+// get fetches a Qiita API URL into v, authenticating with token if it's set.
+// Requests refused by the rate limit are retried when it resets within
+// maxQiitaRetryWait.
+func (s *QiitaService) get(apiURL, token string, v interface{}) error {
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequest("GET", apiURL, nil)
+		if err != nil {
+			return err
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		resp, err := s.httpClient.Do(req)
+		if err != nil {
+			return fmt.Errorf("failed to fetch Qiita articles: %w", err)
+		}
+		err = qiitaResponse(resp, token != "", v)
+		resp.Body.Close()
+
+		var rateLimit *RateLimitError
+		if !errors.As(err, &rateLimit) || attempt >= 2 {
+			return err
+		}
+		wait := time.Until(rateLimit.ResetAt)
+		if wait > maxQiitaRetryWait {
+			return err
+		}
+		time.Sleep(wait)
+	}
+}
+
+func qiitaResponse(resp *http.Response, authenticated bool, v interface{}) error {
+	if err := qiitaRateLimit(resp, authenticated); err != nil {
+		return err
 	}
-	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return errQiitaNotFound
+	case http.StatusUnauthorized:
+		return errQiitaUnauthorized
+	default:
+		return fmt.Errorf("Qiita API returned status %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode Qiita response: %w", err)
+	}
+	return nil
+}
 
-	return resp.StatusCode == http.StatusOK, nil
+// qiitaRateLimit returns a RateLimitError if Qiita refused the request for
+// exceeding its rate limit, which it reports with 429 or with 403 and
+// Rate-Remaining: 0. The authenticated limit belongs to the user's token.
+func qiitaRateLimit(resp *http.Response, authenticated bool) error {
+	limited := resp.StatusCode == http.StatusTooManyRequests ||
+		(resp.StatusCode == http.StatusForbidden && resp.Header.Get("Rate-Remaining") == "0")
+	if !limited {
+		return nil
+	}
+
+	resetAt := time.Now().Add(defaultRateLimitWait)
+	if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		resetAt = time.Now().Add(time.Duration(secs) * time.Second)
+	} else if unix, err := strconv.ParseInt(resp.Header.Get("Rate-Reset"), 10, 64); err == nil {
+		resetAt = time.Unix(unix, 0)
+	}
+	return &RateLimitError{Provider: "Qiita", ResetAt: resetAt, TokenScoped: authenticated}
 }
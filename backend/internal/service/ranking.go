@@ -0,0 +1,354 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/norman6464/devsync/backend/internal/model"
+	"github.com/norman6464/devsync/backend/internal/queue"
+	"github.com/norman6464/devsync/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+// RankingComposite names the weighted combination of every metric
+const RankingComposite = "composite"
+
+var ErrInvalidRankingWeights = errors.New(`weights must be "metric:weight" pairs of known metrics`)
+
+const (
+	// leaderboardMaxAge is how old a snapshot can be and still be served;
+	// past it rankings are computed live
+	leaderboardMaxAge = 3 * time.Hour
+	// leaderboardHistoryDays is how long daily snapshots are kept
+	leaderboardHistoryDays = 365
+	// rankGainNotifyThreshold is how many places a user must climb on the
+	// weekly leaderboard in a day to be notified
+	rankGainNotifyThreshold = 5
+)
+
+// RankingQuery describes a ranking: the leaderboard it comes from, the
+// period it covers and the users it includes.
+type RankingQuery struct {
+	// Metric names the leaderboard: one metric, or RankingComposite
+	Metric string
+	// Weights, when set, ranks by a custom weighting instead of Metric
+	Weights map[string]float64
+	Period  model.RankingPeriod
+	Scope   model.RankingScope
+}
+
+// RankingService ranks users by a weighted score over metrics such as
+// contributions, posts and article likes. A scheduled job snapshots every
+// leaderboard so requests don't aggregate whole tables.
+type RankingService struct {
+	rankingRepo      *repository.RankingRepository
+	notificationRepo *repository.NotificationRepository
+	// weights is the default composite weighting
+	weights map[string]float64
+}
+
+// NewRankingService creates the service with the composite weights from
+// configuration, falling back to weighing every metric equally.
+func NewRankingService(rankingRepo *repository.RankingRepository, notificationRepo *repository.NotificationRepository, weights string, jobs *queue.Queue) *RankingService {
+	parsed, err := ParseRankingWeights(weights)
+	if err != nil {
+		log.Printf("ranking: ignoring ranking weights %q: %v", weights, err)
+	}
+	if err != nil || len(parsed) == 0 {
+		parsed = make(map[string]float64)
+		for _, metric := range repository.RankingMetrics() {
+			parsed[metric] = 1
+		}
+	}
+	s := &RankingService{rankingRepo: rankingRepo, notificationRepo: notificationRepo, weights: parsed}
+	jobs.Register(queue.JobType{Name: JobRankingSnapshot, Handler: func(ctx context.Context, _ json.RawMessage) error {
+		return s.Snapshot(time.Now())
+	}})
+	return s
+}
+
+// DefaultWeights returns the composite weighting.
+func (s *RankingService) DefaultWeights() map[string]float64 {
+	weights := make(map[string]float64, len(s.weights))
+	for metric, w := range s.weights {
+		weights[metric] = w
+	}
+	return weights
+}
+
+// Weights resolves a metric name - one metric, or RankingComposite for the
+// default weighting - into the weights to rank by.
+func (s *RankingService) Weights(metric string) (map[string]float64, bool) {
+	if metric == RankingComposite {
+		return s.DefaultWeights(), true
+	}
+	for _, known := range repository.RankingMetrics() {
+		if metric == known {
+			return map[string]float64{metric: 1}, true
+		}
+	}
+	return nil, false
+}
+
+// ParseRankingWeights parses "metric:weight" or "metric=weight" pairs
+// separated by commas.
+func ParseRankingWeights(spec string) (map[string]float64, error) {
+	weights := make(map[string]float64)
+	known := make(map[string]bool)
+	for _, metric := range repository.RankingMetrics() {
+		known[metric] = true
+	}
+	for _, pair := range strings.Split(spec, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(pair, ":")
+		if !ok {
+			name, value, ok = strings.Cut(pair, "=")
+		}
+		weight, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		name = strings.TrimSpace(name)
+		if !ok || err != nil || !known[name] {
+			return nil, ErrInvalidRankingWeights
+		}
+		weights[name] = weight
+	}
+	return weights, nil
+}
+
+// Leaderboard returns the top limit users of a ranking, from its latest
+// snapshot while that is fresh and computed live otherwise.
+func (s *RankingService) Leaderboard(q RankingQuery, limit int) ([]model.RankingEntry, error) {
+	if snapshot := s.freshSnapshot(q); snapshot != nil {
+		entries, err := s.rankingRepo.SnapshotEntries(snapshot.ID, q.Scope, limit)
+		if err != nil {
+			return nil, err
+		}
+		return entries, s.rankingRepo.FillUsers(entries)
+	}
+
+	entries, err := s.Rank(q)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries, s.rankingRepo.FillUsers(entries)
+}
+
+// Position returns where the user stands in a ranking, however far down.
+func (s *RankingService) Position(userID uint, q RankingQuery) (*model.RankingPosition, error) {
+	var position *model.RankingPosition
+	if snapshot := s.freshSnapshot(q); snapshot != nil {
+		var err error
+		if position, err = s.rankingRepo.SnapshotPosition(snapshot.ID, userID, q.Scope); err != nil {
+			return nil, err
+		}
+		position.ComputedAt = &snapshot.ComputedAt
+	} else {
+		entries, err := s.Rank(q)
+		if err != nil {
+			return nil, err
+		}
+		position = &model.RankingPosition{Total: len(entries)}
+		for _, e := range entries {
+			if e.UserID == userID {
+				position.Rank, position.Score = e.Rank, e.Score
+				break
+			}
+		}
+	}
+	if position.Rank > 0 {
+		position.Percentile = 100 * float64(position.Total-position.Rank+1) / float64(position.Total)
+	}
+	return position, nil
+}
+
+// History returns the user's daily places on a leaderboard since the given
+// date.
+func (s *RankingService) History(userID uint, metric string, period model.RankingPeriod, since time.Time) ([]model.RankHistoryPoint, error) {
+	return s.rankingRepo.RankHistory(userID, metric, period, since)
+}
+
+// freshSnapshot returns the snapshot a query can be served from, or nil when
+// it needs ranking live: it has custom weights, or its leaderboard hasn't
+// been snapshotted recently.
+func (s *RankingService) freshSnapshot(q RankingQuery) *model.LeaderboardSnapshot {
+	if q.Weights != nil {
+		return nil
+	}
+	snapshot, err := s.rankingRepo.LatestSnapshot(q.Metric, q.Period)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Printf("ranking: failed to load %s %s leaderboard: %v", q.Period, q.Metric, err)
+		}
+		return nil
+	}
+	if time.Since(snapshot.ComputedAt) > leaderboardMaxAge {
+		return nil
+	}
+	return snapshot
+}
+
+// Snapshot ranks everyone on every leaderboard and stores the results as
+// the day's snapshots. The first snapshot of a day is compared against the
+// day before, and users who climbed the weekly leaderboard are notified.
+func (s *RankingService) Snapshot(now time.Time) error {
+	now = now.UTC()
+	date := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	periods := []model.RankingPeriod{
+		model.RankingPeriodWeekly, model.RankingPeriodMonthly, model.RankingPeriodYearly, model.RankingPeriodAllTime,
+	}
+	metrics := append([]string{RankingComposite}, repository.RankingMetrics()...)
+
+	var failed error
+	for _, metric := range metrics {
+		for _, period := range periods {
+			entries, err := s.Rank(RankingQuery{Metric: metric, Period: period})
+			if err != nil {
+				log.Printf("ranking: failed to rank %s %s leaderboard: %v", period, metric, err)
+				failed = err
+				continue
+			}
+			snapshot := &model.LeaderboardSnapshot{
+				Metric:     metric,
+				Period:     period,
+				Date:       date,
+				Total:      len(entries),
+				ComputedAt: now,
+			}
+			created, err := s.rankingRepo.SaveSnapshot(snapshot, entries)
+			if err != nil {
+				log.Printf("ranking: failed to store %s %s leaderboard: %v", period, metric, err)
+				failed = err
+				continue
+			}
+			if created && metric == RankingComposite && period == model.RankingPeriodWeekly {
+				s.notifyRankGains(snapshot)
+			}
+		}
+	}
+
+	if err := s.rankingRepo.PruneSnapshots(date.AddDate(0, 0, -leaderboardHistoryDays)); err != nil {
+		log.Printf("ranking: failed to prune leaderboard snapshots: %v", err)
+	}
+	return failed
+}
+
+// notifyRankGains tells users who climbed a leaderboard since its previous
+// snapshot how many places they moved up.
+func (s *RankingService) notifyRankGains(snapshot *model.LeaderboardSnapshot) {
+	previous, err := s.rankingRepo.SnapshotBefore(snapshot.Metric, snapshot.Period, snapshot.Date)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return
+	} else if err != nil {
+		log.Printf("ranking: failed to load previous leaderboard: %v", err)
+		return
+	}
+	changes, err := s.rankingRepo.RankGains(snapshot.ID, previous.ID, rankGainNotifyThreshold)
+	if err != nil {
+		log.Printf("ranking: failed to compare leaderboards: %v", err)
+		return
+	}
+
+	notifications := make([]*model.Notification, len(changes))
+	for i, change := range changes {
+		rank, gain := change.Rank, change.Change
+		notifications[i] = &model.Notification{
+			UserID:     change.UserID,
+			Type:       model.NotificationTypeRank,
+			ActorID:    change.UserID,
+			Rank:       &rank,
+			RankChange: &gain,
+		}
+	}
+	if err := s.notificationRepo.CreateBatch(notifications); err != nil {
+		log.Printf("ranking: failed to notify rank gains: %v", err)
+	}
+}
+
+// Rank scores every user in scope on the query's metrics and returns them
+// best first, identified only by ID. Users with equal scores share a rank.
+func (s *RankingService) Rank(q RankingQuery) ([]model.RankingEntry, error) {
+	weights := q.Weights
+	if weights == nil {
+		var ok bool
+		if weights, ok = s.Weights(q.Metric); !ok {
+			return nil, fmt.Errorf("ranking: unknown metric %q", q.Metric)
+		}
+	}
+	since := q.Period.Since(time.Now())
+	scores := make(map[uint]*model.RankingEntry)
+	for metric, weight := range weights {
+		if weight == 0 {
+			continue
+		}
+		values, err := s.rankingRepo.MetricScores(metric, since, q.Scope)
+		if err != nil {
+			return nil, fmt.Errorf("ranking %s: %w", metric, err)
+		}
+		for userID, value := range values {
+			entry := scores[userID]
+			if entry == nil {
+				entry = &model.RankingEntry{UserID: userID, Breakdown: make(map[string]int64)}
+				scores[userID] = entry
+			}
+			entry.Score += weight * float64(value)
+			entry.Breakdown[metric] = value
+		}
+	}
+	if len(weights) == 1 {
+		// A single metric's score is its value
+		for _, entry := range scores {
+			entry.Breakdown = nil
+		}
+	}
+	return s.finish(scores), nil
+}
+
+// RankLanguage ranks the users in scope by their work in a language.
+func (s *RankingService) RankLanguage(language string, period model.RankingPeriod, scope model.RankingScope) ([]model.RankingEntry, error) {
+	values, err := s.rankingRepo.LanguageScores(language, period.Since(time.Now()), scope)
+	if err != nil {
+		return nil, err
+	}
+	scores := make(map[uint]*model.RankingEntry, len(values))
+	for userID, value := range values {
+		scores[userID] = &model.RankingEntry{UserID: userID, Score: float64(value)}
+	}
+	entries := s.finish(scores)
+	return entries, s.rankingRepo.FillUsers(entries)
+}
+
+// finish orders the scored users and assigns ranks.
+func (s *RankingService) finish(scores map[uint]*model.RankingEntry) []model.RankingEntry {
+	entries := make([]model.RankingEntry, 0, len(scores))
+	for _, entry := range scores {
+		if entry.Score > 0 {
+			entries = append(entries, *entry)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Score != entries[j].Score {
+			return entries[i].Score > entries[j].Score
+		}
+		return entries[i].UserID < entries[j].UserID
+	})
+	for i := range entries {
+		if i > 0 && entries[i].Score == entries[i-1].Score {
+			entries[i].Rank = entries[i-1].Rank
+		} else {
+			entries[i].Rank = i + 1
+		}
+	}
+	return entries
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RateLimitError is returned when an external API refuses a request because
+// its rate limit is exhausted.
+type RateLimitError struct {
+	Provider string
+	// ResetAt is when requests are allowed again
+	ResetAt time.Time
+	// TokenScoped is set when the limit belongs to the user's own token, so
+	// other accounts can keep syncing
+	TokenScoped bool
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("%s rate limit exceeded until %s", e.Provider, e.ResetAt.Format(time.RFC3339))
+}
+
+// defaultRateLimitWait is assumed when an API doesn't say when to retry
+const defaultRateLimitWait = 15 * time.Minute
+
+// checkRateLimit returns a RateLimitError if resp reports an exhausted rate
+// limit, via 429 or GitHub-style 403 with X-RateLimit-Remaining: 0.
+func checkRateLimit(provider string, resp *http.Response) error {
+	limited := resp.StatusCode == http.StatusTooManyRequests ||
+		(resp.StatusCode == http.StatusForbidden && resp.Header.Get("X-RateLimit-Remaining") == "0")
+	if !limited {
+		return nil
+	}
+
+	resetAt := time.Now().Add(defaultRateLimitWait)
+	if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		resetAt = time.Now().Add(time.Duration(secs) * time.Second)
+	} else if unix, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		resetAt = time.Unix(unix, 0)
+	}
+	return &RateLimitError{Provider: provider, ResetAt: resetAt}
+}
+
+// rateLimitRemaining reads the X-RateLimit-Remaining and X-RateLimit-Reset
+// headers. ok is false when the API didn't send them.
+func rateLimitRemaining(resp *http.Response) (remaining int, resetAt time.Time, ok bool) {
+	remaining, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return 0, time.Time{}, false
+	}
+	if unix, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		resetAt = time.Unix(unix, 0)
+	}
+	return remaining, resetAt, true
+}
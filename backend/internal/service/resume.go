@@ -0,0 +1,603 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/norman6464/devsync/backend/internal/model"
+	"github.com/norman6464/devsync/backend/internal/repository"
+)
+
+// maxResumeArticles caps how many articles are listed on a résumé
+const maxResumeArticles = 10
+
+// maxResumeLanguages caps how many top languages are listed on a résumé
+const maxResumeLanguages = 8
+
+// Resume is the format-independent content of a user's exported résumé.
+type Resume struct {
+	User              *model.User
+	Languages         []string
+	Frameworks        []string
+	TopLanguages      []ResumeLanguage
+	Projects          []ResumeProject
+	CompletedRoadmaps []model.Roadmap
+	CompletedGoals    []model.LearningGoal
+	BookReviews       []model.BookReview
+	Articles          []ResumeArticle
+	GeneratedAt       time.Time
+}
+
+type ResumeLanguage struct {
+	Name    string
+	Percent float64
+}
+
+type ResumeProject struct {
+	Title       string
+	Role        string
+	Description string
+	TechStack   []string
+	URL         string
+	DemoURL     string
+	StartDate   *time.Time
+	EndDate     *time.Time
+}
+
+// ResumeArticle is a Zenn or Qiita article in a common shape.
+type ResumeArticle struct {
+	Title       string
+	Platform    string
+	URL         string
+	Likes       int
+	PublishedAt time.Time
+}
+
+type ResumeService struct {
+	userRepo         *repository.UserRepository
+	githubRepo       *repository.GitHubRepository
+	projectRepo      *repository.ProjectRepository
+	roadmapRepo      *repository.RoadmapRepository
+	learningGoalRepo *repository.LearningGoalRepository
+	bookReviewRepo   *repository.BookReviewRepository
+	articleRepo      *repository.ArticleRepository
+}
+
+func NewResumeService(
+	userRepo *repository.UserRepository,
+	githubRepo *repository.GitHubRepository,
+	projectRepo *repository.ProjectRepository,
+	roadmapRepo *repository.RoadmapRepository,
+	learningGoalRepo *repository.LearningGoalRepository,
+	bookReviewRepo *repository.BookReviewRepository,
+	articleRepo *repository.ArticleRepository,
+) *ResumeService {
+	return &ResumeService{
+		userRepo:         userRepo,
+		githubRepo:       githubRepo,
+		projectRepo:      projectRepo,
+		roadmapRepo:      roadmapRepo,
+		learningGoalRepo: learningGoalRepo,
+		bookReviewRepo:   bookReviewRepo,
+		articleRepo:      articleRepo,
+	}
+}
+
+// Build gathers everything that goes on a user's résumé.
+func (s *ResumeService) Build(userID uint) (*Resume, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	resume := &Resume{
+		User:        user,
+		Languages:   splitList(user.SkillsLanguages),
+		Frameworks:  splitList(user.SkillsFrameworks),
+		GeneratedAt: time.Now(),
+	}
+
+	stats, err := s.githubRepo.GetLanguageStats(userID)
+	if err != nil {
+		return nil, err
+	}
+	var totalBytes int64
+	for _, st := range stats {
+		totalBytes += st.Bytes
+	}
+	for i, st := range stats {
+		if i >= maxResumeLanguages || totalBytes == 0 {
+			break
+		}
+		resume.TopLanguages = append(resume.TopLanguages, ResumeLanguage{
+			Name:    st.Language,
+			Percent: float64(st.Bytes) * 100 / float64(totalBytes),
+		})
+	}
+
+	projects, err := s.projectRepo.FindFeaturedByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range projects {
+		resume.Projects = append(resume.Projects, ResumeProject{
+			Title:       p.Title,
+			Role:        p.Role,
+			Description: p.Description,
+			TechStack:   parseTechStack(p.TechStack),
+			URL:         p.GithubURL,
+			DemoURL:     p.DemoURL,
+			StartDate:   p.StartDate,
+			EndDate:     p.EndDate,
+		})
+	}
+
+	roadmaps, err := s.roadmapRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range roadmaps {
+		if r.Status == model.RoadmapStatusCompleted {
+			resume.CompletedRoadmaps = append(resume.CompletedRoadmaps, r)
+		}
+	}
+
+	goals, err := s.learningGoalRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, g := range goals {
+		if g.Status == model.GoalStatusCompleted {
+			resume.CompletedGoals = append(resume.CompletedGoals, g)
+		}
+	}
+
+	if resume.BookReviews, err = s.bookReviewRepo.FindByUserID(userID); err != nil {
+		return nil, err
+	}
+
+	// Most-liked articles are the best showcase
+	articles, err := s.articleRepo.GetTopArticles(userID, maxResumeArticles)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range articles {
+		resume.Articles = append(resume.Articles, ResumeArticle{
+			Title:       a.Title,
+			Platform:    articlePlatform(a),
+			URL:         a.URL,
+			Likes:       a.Likes,
+			PublishedAt: a.PublishedAt,
+		})
+	}
+
+	return resume, nil
+}
+
+// articlePlatform names where an article was published: Zenn, Qiita or, for
+// feed entries, the site's host.
+func articlePlatform(a model.ExternalArticle) string {
+	switch a.Source {
+	case model.IntegrationZenn:
+		return "Zenn"
+	case model.IntegrationQiita:
+		return "Qiita"
+	}
+	if u, err := url.Parse(a.URL); err == nil && u.Host != "" {
+		return strings.TrimPrefix(u.Host, "www.")
+	}
+	return "Blog"
+}
+
+// splitList parses the comma-separated skill lists stored on User.
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseTechStack reads Project.TechStack, which is normally a JSON array but
+// may be a plain comma-separated list for older projects.
+func parseTechStack(s string) []string {
+	var stack []string
+	if err := json.Unmarshal([]byte(s), &stack); err == nil {
+		return stack
+	}
+	return splitList(s)
+}
+
+func resumeDateRange(start, end *time.Time) string {
+	if start == nil {
+		return ""
+	}
+	if end == nil {
+		return start.Format("2006-01") + " – Present"
+	}
+	return start.Format("2006-01") + " – " + end.Format("2006-01")
+}
+
+func resumeProfileURL(user *model.User) string {
+	if user.GitHubUsername != "" {
+		return "https://github.com/" + user.GitHubUsername
+	}
+	return ""
+}
+
+// RenderResumeMarkdown renders the résumé as a Markdown document.
+func RenderResumeMarkdown(r *Resume) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# %s\n\n", r.User.Name)
+	var contact []string
+	if r.User.Handle != "" {
+		contact = append(contact, "@"+r.User.Handle)
+	}
+	contact = append(contact, r.User.Email)
+	if url := resumeProfileURL(r.User); url != "" {
+		contact = append(contact, fmt.Sprintf("[GitHub](%s)", url))
+	}
+	buf.WriteString(strings.Join(contact, " · ") + "\n\n")
+	if r.User.Bio != "" {
+		buf.WriteString(r.User.Bio + "\n\n")
+	}
+
+	if len(r.Languages) > 0 || len(r.Frameworks) > 0 || len(r.TopLanguages) > 0 {
+		buf.WriteString("## Skills\n\n")
+		if len(r.Languages) > 0 {
+			fmt.Fprintf(&buf, "- **Languages:** %s\n", strings.Join(r.Languages, ", "))
+		}
+		if len(r.Frameworks) > 0 {
+			fmt.Fprintf(&buf, "- **Frameworks:** %s\n", strings.Join(r.Frameworks, ", "))
+		}
+		if len(r.TopLanguages) > 0 {
+			var langs []string
+			for _, l := range r.TopLanguages {
+				langs = append(langs, fmt.Sprintf("%s (%.1f%%)", l.Name, l.Percent))
+			}
+			fmt.Fprintf(&buf, "- **Most used on GitHub:** %s\n", strings.Join(langs, ", "))
+		}
+		buf.WriteString("\n")
+	}
+
+	if len(r.Projects) > 0 {
+		buf.WriteString("## Projects\n\n")
+		for _, p := range r.Projects {
+			fmt.Fprintf(&buf, "### %s\n\n", p.Title)
+			var meta []string
+			if p.Role != "" {
+				meta = append(meta, p.Role)
+			}
+			if dates := resumeDateRange(p.StartDate, p.EndDate); dates != "" {
+				meta = append(meta, dates)
+			}
+			if len(meta) > 0 {
+				fmt.Fprintf(&buf, "*%s*\n\n", strings.Join(meta, " · "))
+			}
+			if p.Description != "" {
+				buf.WriteString(p.Description + "\n\n")
+			}
+			if len(p.TechStack) > 0 {
+				fmt.Fprintf(&buf, "**Tech:** %s\n\n", strings.Join(p.TechStack, ", "))
+			}
+			var links []string
+			if p.URL != "" {
+				links = append(links, fmt.Sprintf("[Source](%s)", p.URL))
+			}
+			if p.DemoURL != "" {
+				links = append(links, fmt.Sprintf("[Demo](%s)", p.DemoURL))
+			}
+			if len(links) > 0 {
+				buf.WriteString(strings.Join(links, " · ") + "\n\n")
+			}
+		}
+	}
+
+	if len(r.CompletedRoadmaps) > 0 || len(r.CompletedGoals) > 0 {
+		buf.WriteString("## Learning\n\n")
+		for _, rm := range r.CompletedRoadmaps {
+			fmt.Fprintf(&buf, "- %s (roadmap, %d steps%s)\n", rm.Title, rm.StepCount, completedSuffix(rm.CompletedAt))
+		}
+		for _, g := range r.CompletedGoals {
+			fmt.Fprintf(&buf, "- %s (goal%s)\n", g.Title, completedSuffix(g.CompletedAt))
+		}
+		buf.WriteString("\n")
+	}
+
+	if len(r.Articles) > 0 {
+		buf.WriteString("## Writing\n\n")
+		for _, a := range r.Articles {
+			fmt.Fprintf(&buf, "- [%s](%s) — %s, %s, %d likes\n", a.Title, a.URL, a.Platform, a.PublishedAt.Format("2006-01-02"), a.Likes)
+		}
+		buf.WriteString("\n")
+	}
+
+	if len(r.BookReviews) > 0 {
+		buf.WriteString("## Reading\n\n")
+		for _, b := range r.BookReviews {
+			line := "- " + b.Title
+			if b.Author != "" {
+				line += " by " + b.Author
+			}
+			fmt.Fprintf(&buf, "%s (%d/5)\n", line, b.Rating)
+		}
+		buf.WriteString("\n")
+	}
+
+	fmt.Fprintf(&buf, "---\n\n*Generated by DevSync on %s*\n", r.GeneratedAt.Format("2006-01-02"))
+	return buf.Bytes()
+}
+
+func completedSuffix(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return ", completed " + t.Format("2006-01")
+}
+
+// JSONResume follows the JSON Resume schema (https://jsonresume.org/schema).
+type JSONResume struct {
+	Schema       string                  `json:"$schema"`
+	Basics       JSONResumeBasics        `json:"basics"`
+	Skills       []JSONResumeSkill       `json:"skills,omitempty"`
+	Projects     []JSONResumeProject     `json:"projects,omitempty"`
+	Publications []JSONResumePublication `json:"publications,omitempty"`
+	Certificates []JSONResumeCertificate `json:"certificates,omitempty"`
+	Awards       []JSONResumeAward       `json:"awards,omitempty"`
+	Interests    []JSONResumeInterest    `json:"interests,omitempty"`
+	Meta         JSONResumeMeta          `json:"meta"`
+}
+
+type JSONResumeBasics struct {
+	Name     string              `json:"name"`
+	Email    string              `json:"email,omitempty"`
+	Image    string              `json:"image,omitempty"`
+	URL      string              `json:"url,omitempty"`
+	Summary  string              `json:"summary,omitempty"`
+	Profiles []JSONResumeProfile `json:"profiles,omitempty"`
+}
+
+type JSONResumeProfile struct {
+	Network  string `json:"network"`
+	Username string `json:"username"`
+	URL      string `json:"url,omitempty"`
+}
+
+type JSONResumeSkill struct {
+	Name     string   `json:"name"`
+	Level    string   `json:"level,omitempty"`
+	Keywords []string `json:"keywords,omitempty"`
+}
+
+type JSONResumeProject struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Keywords    []string `json:"keywords,omitempty"`
+	Roles       []string `json:"roles,omitempty"`
+	StartDate   string   `json:"startDate,omitempty"`
+	EndDate     string   `json:"endDate,omitempty"`
+	URL         string   `json:"url,omitempty"`
+}
+
+type JSONResumePublication struct {
+	Name        string `json:"name"`
+	Publisher   string `json:"publisher"`
+	ReleaseDate string `json:"releaseDate,omitempty"`
+	URL         string `json:"url,omitempty"`
+}
+
+type JSONResumeCertificate struct {
+	Name   string `json:"name"`
+	Date   string `json:"date,omitempty"`
+	Issuer string `json:"issuer"`
+}
+
+type JSONResumeAward struct {
+	Title   string `json:"title"`
+	Date    string `json:"date,omitempty"`
+	Awarder string `json:"awarder"`
+	Summary string `json:"summary,omitempty"`
+}
+
+type JSONResumeInterest struct {
+	Name     string   `json:"name"`
+	Keywords []string `json:"keywords,omitempty"`
+}
+
+type JSONResumeMeta struct {
+	LastModified string `json:"lastModified"`
+	Version      string `json:"version"`
+}
+
+// ToJSONResume converts the résumé to the JSON Resume schema. Completed
+// roadmaps map to certificates, completed goals to awards and book reviews to
+// a "Reading" interest, the closest fits the schema offers.
+func ToJSONResume(r *Resume) *JSONResume {
+	out := &JSONResume{
+		Schema: "https://raw.githubusercontent.com/jsonresume/resume-schema/v1.0.0/schema.json",
+		Basics: JSONResumeBasics{
+			Name:    r.User.Name,
+			Email:   r.User.Email,
+			Image:   r.User.AvatarURL,
+			URL:     resumeProfileURL(r.User),
+			Summary: r.User.Bio,
+		},
+		Meta: JSONResumeMeta{LastModified: r.GeneratedAt.UTC().Format(time.RFC3339), Version: "v1.0.0"},
+	}
+	if r.User.GitHubUsername != "" {
+		out.Basics.Profiles = append(out.Basics.Profiles, JSONResumeProfile{Network: "GitHub", Username: r.User.GitHubUsername, URL: "https://github.com/" + r.User.GitHubUsername})
+	}
+	if r.User.ZennUsername != "" {
+		out.Basics.Profiles = append(out.Basics.Profiles, JSONResumeProfile{Network: "Zenn", Username: r.User.ZennUsername, URL: "https://zenn.dev/" + r.User.ZennUsername})
+	}
+	if r.User.QiitaUsername != "" {
+		out.Basics.Profiles = append(out.Basics.Profiles, JSONResumeProfile{Network: "Qiita", Username: r.User.QiitaUsername, URL: "https://qiita.com/" + r.User.QiitaUsername})
+	}
+
+	if len(r.Languages) > 0 {
+		out.Skills = append(out.Skills, JSONResumeSkill{Name: "Languages", Keywords: r.Languages})
+	}
+	if len(r.Frameworks) > 0 {
+		out.Skills = append(out.Skills, JSONResumeSkill{Name: "Frameworks", Keywords: r.Frameworks})
+	}
+	for _, l := range r.TopLanguages {
+		out.Skills = append(out.Skills, JSONResumeSkill{Name: l.Name, Level: fmt.Sprintf("%.1f%% of GitHub code", l.Percent)})
+	}
+
+	for _, p := range r.Projects {
+		project := JSONResumeProject{
+			Name:        p.Title,
+			Description: p.Description,
+			Keywords:    p.TechStack,
+			URL:         p.URL,
+		}
+		if project.URL == "" {
+			project.URL = p.DemoURL
+		}
+		if p.Role != "" {
+			project.Roles = []string{p.Role}
+		}
+		if p.StartDate != nil {
+			project.StartDate = p.StartDate.Format("2006-01-02")
+		}
+		if p.EndDate != nil {
+			project.EndDate = p.EndDate.Format("2006-01-02")
+		}
+		out.Projects = append(out.Projects, project)
+	}
+
+	for _, a := range r.Articles {
+		out.Publications = append(out.Publications, JSONResumePublication{
+			Name:        a.Title,
+			Publisher:   a.Platform,
+			ReleaseDate: a.PublishedAt.Format("2006-01-02"),
+			URL:         a.URL,
+		})
+	}
+
+	for _, rm := range r.CompletedRoadmaps {
+		cert := JSONResumeCertificate{Name: rm.Title, Issuer: "DevSync Roadmap"}
+		if rm.CompletedAt != nil {
+			cert.Date = rm.CompletedAt.Format("2006-01-02")
+		}
+		out.Certificates = append(out.Certificates, cert)
+	}
+
+	for _, g := range r.CompletedGoals {
+		award := JSONResumeAward{Title: g.Title, Awarder: "DevSync Learning Goal", Summary: g.Description}
+		if g.CompletedAt != nil {
+			award.Date = g.CompletedAt.Format("2006-01-02")
+		}
+		out.Awards = append(out.Awards, award)
+	}
+
+	if len(r.BookReviews) > 0 {
+		reading := JSONResumeInterest{Name: "Reading"}
+		for _, b := range r.BookReviews {
+			reading.Keywords = append(reading.Keywords, b.Title)
+		}
+		out.Interests = append(out.Interests, reading)
+	}
+
+	return out
+}
+
+// RenderResumePDF lays the résumé out as a simple single-column A4 PDF.
+func RenderResumePDF(r *Resume) []byte {
+	doc := newPDFDocument()
+	doc.Text(r.User.Name, 22, true)
+	var contact []string
+	if r.User.Handle != "" {
+		contact = append(contact, "@"+r.User.Handle)
+	}
+	contact = append(contact, r.User.Email)
+	if url := resumeProfileURL(r.User); url != "" {
+		contact = append(contact, url)
+	}
+	doc.Text(strings.Join(contact, "  |  "), 10, false)
+	if r.User.Bio != "" {
+		doc.Space(6)
+		doc.Text(r.User.Bio, 11, false)
+	}
+
+	if len(r.Languages) > 0 || len(r.Frameworks) > 0 || len(r.TopLanguages) > 0 {
+		doc.Heading("Skills")
+		if len(r.Languages) > 0 {
+			doc.Bullet("Languages: " + strings.Join(r.Languages, ", "))
+		}
+		if len(r.Frameworks) > 0 {
+			doc.Bullet("Frameworks: " + strings.Join(r.Frameworks, ", "))
+		}
+		if len(r.TopLanguages) > 0 {
+			var langs []string
+			for _, l := range r.TopLanguages {
+				langs = append(langs, fmt.Sprintf("%s %.1f%%", l.Name, l.Percent))
+			}
+			doc.Bullet("Most used on GitHub: " + strings.Join(langs, ", "))
+		}
+	}
+
+	if len(r.Projects) > 0 {
+		doc.Heading("Projects")
+		for _, p := range r.Projects {
+			doc.Text(p.Title, 12, true)
+			var meta []string
+			if p.Role != "" {
+				meta = append(meta, p.Role)
+			}
+			if dates := resumeDateRange(p.StartDate, p.EndDate); dates != "" {
+				meta = append(meta, dates)
+			}
+			if len(meta) > 0 {
+				doc.Text(strings.Join(meta, "  |  "), 9, false)
+			}
+			if p.Description != "" {
+				doc.Text(p.Description, 10, false)
+			}
+			if len(p.TechStack) > 0 {
+				doc.Text("Tech: "+strings.Join(p.TechStack, ", "), 10, false)
+			}
+			if p.URL != "" {
+				doc.Text(p.URL, 9, false)
+			}
+			doc.Space(6)
+		}
+	}
+
+	if len(r.CompletedRoadmaps) > 0 || len(r.CompletedGoals) > 0 {
+		doc.Heading("Learning")
+		for _, rm := range r.CompletedRoadmaps {
+			doc.Bullet(fmt.Sprintf("%s (roadmap, %d steps%s)", rm.Title, rm.StepCount, completedSuffix(rm.CompletedAt)))
+		}
+		for _, g := range r.CompletedGoals {
+			doc.Bullet(fmt.Sprintf("%s (goal%s)", g.Title, completedSuffix(g.CompletedAt)))
+		}
+	}
+
+	if len(r.Articles) > 0 {
+		doc.Heading("Writing")
+		for _, a := range r.Articles {
+			doc.Bullet(fmt.Sprintf("%s (%s, %s, %d likes)", a.Title, a.Platform, a.PublishedAt.Format("2006-01-02"), a.Likes))
+		}
+	}
+
+	if len(r.BookReviews) > 0 {
+		doc.Heading("Reading")
+		for _, b := range r.BookReviews {
+			line := b.Title
+			if b.Author != "" {
+				line += " by " + b.Author
+			}
+			doc.Bullet(fmt.Sprintf("%s (%d/5)", line, b.Rating))
+		}
+	}
+
+	doc.Space(12)
+	doc.Text("Generated by DevSync on "+r.GeneratedAt.Format("2006-01-02"), 8, false)
+	return doc.Bytes()
+}
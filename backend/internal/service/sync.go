@@ -0,0 +1,340 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/norman6464/devsync/backend/internal/config"
+	"github.com/norman6464/devsync/backend/internal/model"
+	"github.com/norman6464/devsync/backend/internal/queue"
+	"github.com/norman6464/devsync/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+const (
+	// syncScheduleWindow is how often due integrations are looked up; their
+	// syncs are spread over the window so they don't hit the APIs at once.
+	syncScheduleWindow = 5 * time.Minute
+	maxSyncBackoff     = 24 * time.Hour
+)
+
+// syncProvider configures scheduled syncing for one provider.
+type syncProvider struct {
+	jobType  string
+	queue    string
+	interval time.Duration
+	// batch caps how many syncs are queued per window, keeping well within
+	// the provider's rate limit
+	batch int
+}
+
+type syncJob struct {
+	UserID uint `json:"user_id"`
+}
+
+// SyncService keeps connected GitHub, GitLab, Bitbucket, Zenn and Qiita
+// accounts and registered article feeds up to date. It
+// syncs on demand and periodically re-syncs every connected account, and
+// records the outcome of each sync per integration.
+type SyncService struct {
+	userRepo      *repository.UserRepository
+	syncRepo      *repository.IntegrationSyncRepository
+	githubService *GitHubService
+	articles      *ArticleService
+	codeHosts     *CodeHostService
+	feedService   *FeedService
+	badges        *BadgeService
+	jobs          *queue.Queue
+	providers     map[model.IntegrationProvider]syncProvider
+
+	mu sync.Mutex
+	// pausedUntil holds providers whose rate limit is exhausted
+	pausedUntil map[model.IntegrationProvider]time.Time
+}
+
+func NewSyncService(cfg *config.Config, userRepo *repository.UserRepository, syncRepo *repository.IntegrationSyncRepository,
+	githubService *GitHubService, articles *ArticleService, codeHosts *CodeHostService,
+	feedService *FeedService, badges *BadgeService, jobs *queue.Queue) *SyncService {
+	s := &SyncService{
+		userRepo:      userRepo,
+		syncRepo:      syncRepo,
+		githubService: githubService,
+		articles:      articles,
+		codeHosts:     codeHosts,
+		feedService:   feedService,
+		badges:        badges,
+		jobs:          jobs,
+		providers: map[model.IntegrationProvider]syncProvider{
+			model.IntegrationGitHub:    {JobGitHubSync, "github", cfg.GitHubSyncInterval, 100},
+			model.IntegrationZenn:      {JobZennSync, "zenn", cfg.ZennSyncInterval, 50},
+			model.IntegrationQiita:     {JobQiitaSync, "qiita", cfg.QiitaSyncInterval, 50},
+			model.IntegrationGitLab:    {JobGitLabSync, "gitlab", cfg.GitLabSyncInterval, 50},
+			model.IntegrationBitbucket: {JobBitbucketSync, "bitbucket", cfg.BitbucketSyncInterval, 50},
+			model.IntegrationFeed:      {JobFeedSync, "feeds", cfg.FeedSyncInterval, 100},
+		},
+		pausedUntil: make(map[model.IntegrationProvider]time.Time),
+	}
+	for provider, p := range s.providers {
+		provider := provider
+		jobs.Register(queue.JobType{
+			Name:    p.jobType,
+			Queue:   p.queue,
+			Timeout: 5 * time.Minute,
+			Handler: func(ctx context.Context, payload json.RawMessage) error {
+				return s.runSync(provider, payload)
+			},
+		})
+	}
+	jobs.Register(queue.JobType{Name: JobSyncScheduleDue, Handler: s.runScheduleDue})
+	jobs.Register(queue.JobType{Name: JobGitHubBackfill, Queue: "github", Timeout: 10 * time.Minute, Handler: s.runBackfill})
+	return s
+}
+
+// Enqueue schedules a background sync of one integration. A sync that is
+// already queued for it is not queued again.
+func (s *SyncService) Enqueue(provider model.IntegrationProvider, userID uint) error {
+	return s.enqueue(provider, userID, time.Time{})
+}
+
+func (s *SyncService) enqueue(provider model.IntegrationProvider, userID uint, runAt time.Time) error {
+	p, ok := s.providers[provider]
+	if !ok {
+		return fmt.Errorf("unknown provider %q", provider)
+	}
+	return s.jobs.EnqueueWith(p.jobType, syncJob{UserID: userID}, queue.Options{
+		RunAt:     runAt,
+		UniqueKey: fmt.Sprintf("%s:%d", p.jobType, userID),
+	})
+}
+
+// Sync fetches the user's data from the provider now and records the
+// outcome. It returns the number of articles synced for Zenn, Qiita and
+// feeds.
+func (s *SyncService) Sync(provider model.IntegrationProvider, user *model.User) (int, error) {
+	var count int
+	var err error
+	switch provider {
+	case model.IntegrationGitHub:
+		err = s.githubService.SyncData(user)
+		if err == nil {
+			s.enqueueBackfill(user)
+		}
+	case model.IntegrationZenn, model.IntegrationQiita:
+		count, err = s.articles.Sync(provider, user)
+	case model.IntegrationFeed:
+		count, err = s.feedService.SyncUser(user.ID)
+	case model.IntegrationGitLab, model.IntegrationBitbucket:
+		err = s.codeHosts.Sync(provider, user.ID)
+	default:
+		return 0, fmt.Errorf("unknown provider %q", provider)
+	}
+	s.record(user.ID, provider, err)
+	if err == nil {
+		// Synced contributions and articles can earn badges
+		s.badges.Evaluate(user.ID)
+	}
+	return count, err
+}
+
+// Status returns the sync state of each integration the user has synced.
+func (s *SyncService) Status(userID uint) ([]model.IntegrationSync, error) {
+	return s.syncRepo.FindByUserID(userID)
+}
+
+// Forget drops the sync state of a disconnected integration.
+func (s *SyncService) Forget(provider model.IntegrationProvider, userID uint) error {
+	return s.syncRepo.Delete(userID, provider)
+}
+
+// record stores the outcome of a sync and when the integration is next due.
+// Failing integrations back off exponentially; rate-limited ones wait for the
+// limit to reset.
+func (s *SyncService) record(userID uint, provider model.IntegrationProvider, syncErr error) {
+	state, err := s.syncRepo.FindOrNew(userID, provider)
+	if err != nil {
+		log.Printf("sync: failed to load %s state for user %d: %v", provider, userID, err)
+		return
+	}
+
+	now := time.Now()
+	interval := s.providers[provider].interval
+	state.LastAttemptAt = &now
+
+	var rateLimit *RateLimitError
+	var next time.Time
+	switch {
+	case syncErr == nil:
+		state.LastSyncedAt = &now
+		state.LastSyncError = ""
+		state.ConsecutiveFailures = 0
+		next = now.Add(jitter(interval))
+	case errors.As(syncErr, &rateLimit):
+		state.LastSyncError = syncErr.Error()
+		if !rateLimit.TokenScoped {
+			s.pause(provider, rateLimit.ResetAt)
+		}
+		next = rateLimit.ResetAt.Add(jitter(syncScheduleWindow))
+	default:
+		state.LastSyncError = syncErr.Error()
+		state.ConsecutiveFailures++
+		delay := interval << uint(state.ConsecutiveFailures-1)
+		if delay <= 0 || delay > maxSyncBackoff {
+			delay = maxSyncBackoff
+		}
+		next = now.Add(jitter(delay))
+	}
+	state.NextSyncAt = &next
+
+	if err := s.syncRepo.Save(state); err != nil {
+		log.Printf("sync: failed to save %s state for user %d: %v", provider, userID, err)
+	}
+}
+
+// jitter returns d adjusted by up to ±10% so syncs drift apart over time.
+func jitter(d time.Duration) time.Duration {
+	spread := int64(d) / 5
+	if spread <= 0 {
+		return d
+	}
+	return d - time.Duration(spread/2) + time.Duration(rand.Int63n(spread))
+}
+
+func (s *SyncService) pause(provider model.IntegrationProvider, until time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if until.After(s.pausedUntil[provider]) {
+		s.pausedUntil[provider] = until
+	}
+}
+
+// paused reports whether the provider's rate limit is exhausted and until when.
+func (s *SyncService) paused(provider model.IntegrationProvider) (time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	until := s.pausedUntil[provider]
+	return until, time.Now().Before(until)
+}
+
+func (s *SyncService) runSync(provider model.IntegrationProvider, payload json.RawMessage) error {
+	var job syncJob
+	if err := queue.Decode(payload, &job); err != nil {
+		return err
+	}
+	user, err := s.userRepo.FindByID(job.UserID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	// The account may have been disconnected since the sync was queued
+	if !s.connected(provider, user) {
+		return nil
+	}
+
+	// A rate-limited provider is retried once the limit resets
+	if until, paused := s.paused(provider); paused {
+		return s.syncRepo.SetNextSyncAt(user.ID, provider, until.Add(jitter(syncScheduleWindow)))
+	}
+
+	// Failures are recorded on the integration and retried on its own
+	// schedule rather than by the queue
+	if _, err := s.Sync(provider, user); err != nil {
+		log.Printf("sync: %s sync for user %d failed: %v", provider, user.ID, err)
+	}
+	return nil
+}
+
+// connected reports whether the user still has the provider connected.
+func (s *SyncService) connected(provider model.IntegrationProvider, user *model.User) bool {
+	switch provider {
+	case model.IntegrationGitHub:
+		return user.GitHubConnected && user.GitHubToken != ""
+	case model.IntegrationZenn, model.IntegrationQiita:
+		return s.articles.Connected(provider, user)
+	case model.IntegrationFeed:
+		return s.feedService.Connected(user.ID)
+	default:
+		return s.codeHosts.Connected(provider, user.ID)
+	}
+}
+
+// enqueueBackfill queues fetching the user's older contribution history if
+// any of it is missing.
+func (s *SyncService) enqueueBackfill(user *model.User) {
+	needed, err := s.githubService.NeedsBackfill(user)
+	if err != nil || !needed {
+		return
+	}
+	err = s.jobs.EnqueueWith(JobGitHubBackfill, syncJob{UserID: user.ID}, queue.Options{
+		UniqueKey: fmt.Sprintf("%s:%d", JobGitHubBackfill, user.ID),
+	})
+	if err != nil {
+		log.Printf("sync: failed to queue contribution backfill for user %d: %v", user.ID, err)
+	}
+}
+
+func (s *SyncService) runBackfill(ctx context.Context, payload json.RawMessage) error {
+	var job syncJob
+	if err := queue.Decode(payload, &job); err != nil {
+		return err
+	}
+	user, err := s.userRepo.FindByID(job.UserID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if !user.GitHubConnected || user.GitHubToken == "" {
+		return nil
+	}
+
+	err = s.githubService.BackfillContributions(user)
+	var rateLimit *RateLimitError
+	if errors.As(err, &rateLimit) {
+		// Fetched years are kept; the next successful sync queues the rest
+		log.Printf("sync: contribution backfill for user %d paused: %v", user.ID, err)
+		return nil
+	}
+	return err
+}
+
+// runScheduleDue queues a sync for every integration whose next sync is due,
+// spread over the scheduling window. A provider that fails to schedule is
+// logged and left for the next run rather than holding up the others.
+func (s *SyncService) runScheduleDue(ctx context.Context, _ json.RawMessage) error {
+	now := time.Now()
+	for provider, p := range s.providers {
+		if _, paused := s.paused(provider); paused || p.interval <= 0 {
+			continue
+		}
+		if err := s.scheduleDue(provider, p, now); err != nil {
+			log.Printf("sync: failed to schedule %s syncs: %v", provider, err)
+		}
+	}
+	return nil
+}
+
+func (s *SyncService) scheduleDue(provider model.IntegrationProvider, p syncProvider, now time.Time) error {
+	userIDs, err := s.syncRepo.FindDueUserIDs(provider, now, p.batch)
+	if err != nil {
+		return err
+	}
+	for _, userID := range userIDs {
+		runAt := now.Add(time.Duration(rand.Int63n(int64(syncScheduleWindow))))
+		if err := s.enqueue(provider, userID, runAt); err != nil {
+			return err
+		}
+		// Not due again until the sync records its real next time
+		if err := s.syncRepo.SetNextSyncAt(userID, provider, now.Add(p.interval)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -4,11 +4,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/norman6464/devsync/backend/internal/model"
 )
 
+// ZennService reads articles from Zenn. It is an ArticleSource.
 type ZennService struct {
 	httpClient *http.Client
 }
@@ -19,6 +21,18 @@ func NewZennService() *ZennService {
 	}
 }
 
+func (s *ZennService) Source() model.IntegrationProvider {
+	return model.IntegrationZenn
+}
+
+func (s *ZennService) Username(user *model.User) string {
+	return user.ZennUsername
+}
+
+func (s *ZennService) SetUsername(user *model.User, username string) {
+	user.ZennUsername = username
+}
+
 // ZennAPIResponse represents the response from Zenn API
 type ZennAPIResponse struct {
 	Articles []ZennAPIArticle `json:"articles"`
@@ -27,19 +41,21 @@ type ZennAPIResponse struct {
 
 // ZennAPIArticle represents an article from Zenn API
 type ZennAPIArticle struct {
-	ID            int64     `json:"id"`
-	Title         string    `json:"title"`
-	Slug          string    `json:"slug"`
-	Emoji         string    `json:"emoji"`
-	ArticleType   string    `json:"article_type"`
-	LikedCount    int       `json:"liked_count"`
-	CommentsCount int       `json:"comments_count"`
-	PublishedAt   time.Time `json:"published_at"`
+	ID              int64     `json:"id"`
+	Title           string    `json:"title"`
+	Slug            string    `json:"slug"`
+	Emoji           string    `json:"emoji"`
+	ArticleType     string    `json:"article_type"`
+	LikedCount      int       `json:"liked_count"`
+	CommentsCount   int       `json:"comments_count"`
+	BookmarkedCount int       `json:"bookmarked_count"`
+	PublishedAt     time.Time `json:"published_at"`
 }
 
 // FetchArticles fetches all articles for a Zenn user
-func (s *ZennService) FetchArticles(username string) ([]model.ZennArticle, error) {
-	var allArticles []model.ZennArticle
+func (s *ZennService) FetchArticles(fetch ArticleFetch) ([]model.ExternalArticle, error) {
+	username := fetch.Username
+	var allArticles []model.ExternalArticle
 	page := 1
 
 	for {
@@ -51,6 +67,9 @@ func (s *ZennService) FetchArticles(username string) ([]model.ZennArticle, error
 		}
 		defer resp.Body.Close()
 
+		if err := checkRateLimit("Zenn", resp); err != nil {
+			return nil, err
+		}
 		if resp.StatusCode != http.StatusOK {
 			return nil, fmt.Errorf("Zenn API returned status %d", resp.StatusCode)
 		}
@@ -61,15 +80,16 @@ func (s *ZennService) FetchArticles(username string) ([]model.ZennArticle, error
 		}
 
 		for _, article := range apiResp.Articles {
-			allArticles = append(allArticles, model.ZennArticle{
-				ZennID:        article.ID,
-				Title:         article.Title,
-				Slug:          article.Slug,
-				Emoji:         article.Emoji,
-				ArticleType:   article.ArticleType,
-				LikedCount:    article.LikedCount,
-				CommentsCount: article.CommentsCount,
-				PublishedAt:   article.PublishedAt,
+			allArticles = append(allArticles, model.ExternalArticle{
+				ExternalID:  strconv.FormatInt(article.ID, 10),
+				Title:       article.Title,
+				URL:         fmt.Sprintf("https://zenn.dev/%s/articles/%s", username, article.Slug),
+				Emoji:       article.Emoji,
+				Category:    article.ArticleType,
+				Likes:       article.LikedCount,
+				Comments:    article.CommentsCount,
+				Stocks:      article.BookmarkedCount,
+				PublishedAt: article.PublishedAt,
 			})
 		}
 
@@ -1,11 +1,14 @@
 package main
 
 import (
+	"context"
 	"log"
+	"os"
 
 	"github.com/joho/godotenv"
 	"github.com/norman6464/devsync/backend/internal/config"
-	"github.com/norman6464/devsync/backend/internal/model"
+	"github.com/norman6464/devsync/backend/internal/migrate"
+	"github.com/norman6464/devsync/backend/internal/queue"
 	"github.com/norman6464/devsync/backend/internal/router"
 	"github.com/norman6464/devsync/backend/internal/service"
 	"gorm.io/driver/postgres"
@@ -17,52 +20,57 @@ func main() {
 
 	cfg := config.Load()
 
-	db, err := gorm.Open(postgres.Open(cfg.DSN()), &gorm.Config{})
+	// TranslateError turns constraint violations into gorm.ErrDuplicatedKey
+	// and gorm.ErrForeignKeyViolated, which repositories map to their own errors
+	db, err := gorm.Open(postgres.Open(cfg.DSN()), &gorm.Config{TranslateError: true})
 	if err != nil {
 		log.Fatalf("failed to connect to database: %v", err)
 	}
 
-	if err := db.AutoMigrate(
-		&model.User{},
-		&model.Follow{},
-		&model.GitHubContribution{},
-		&model.GitHubLanguageStat{},
-		&model.GitHubRepository{},
-		&model.Post{},
-		&model.Like{},
-		&model.Comment{},
-		&model.Message{},
-		&model.Notification{},
-		&model.PasswordResetToken{},
-		&model.ZennArticle{},
-		&model.QiitaArticle{},
-		&model.LearningGoal{},
-		&model.Project{},
-		&model.LearningResource{},
-		&model.ResourceLike{},
-		&model.ResourceSave{},
-		&model.BookReview{},
-		&model.Question{},
-		&model.QuestionVote{},
-		&model.Answer{},
-		&model.AnswerVote{},
-		&model.Roadmap{},
-		&model.RoadmapStep{},
-		&model.ChatRoom{},
-		&model.ChatRoomMember{},
-		&model.GroupMessage{},
-	); err != nil {
-		log.Fatalf("failed to run migrations: %v", err)
+	migrator, err := migrate.New(db)
+	if err != nil {
+		log.Fatalf("failed to load migrations: %v", err)
 	}
 
-	// Mark existing users as onboarding completed
-	db.Model(&model.User{}).Where("onboarding_completed = ?", false).Update("onboarding_completed", true)
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		runMigrateCommand(migrator, os.Args[2:])
+		return
+	}
+
+	if cfg.MigrateOnStart {
+		applied, err := migrator.Up()
+		if err != nil {
+			log.Fatalf("failed to run migrations: %v", err)
+		}
+		for _, m := range applied {
+			log.Printf("applied migration %04d_%s", m.Version, m.Name)
+		}
+	} else {
+		pending, err := migrator.Pending()
+		if err != nil {
+			log.Fatalf("failed to check migrations: %v", err)
+		}
+		if len(pending) > 0 {
+			log.Fatalf("%d pending migrations (first: %04d_%s); run `server migrate up` or set MIGRATE_ON_START=true",
+				len(pending), pending[0].Version, pending[0].Name)
+		}
+	}
 
 	// Start WebSocket hub
 	hub := service.NewHub()
 	go hub.Run()
 
-	r := router.Setup(db, cfg, hub)
+	// Services register their background jobs while the router is set up
+	jobs := queue.New(db, map[string]int{
+		queue.DefaultQueue: cfg.JobWorkers,
+		"github":           2,
+		"exports":          1,
+	})
+	r := router.Setup(db, cfg, hub, jobs)
+	if err := service.ScheduleJobs(jobs); err != nil {
+		log.Fatalf("failed to schedule jobs: %v", err)
+	}
+	jobs.Start(context.Background())
 
 	log.Printf("Server starting on :%s", cfg.Port)
 	if err := r.Run(":" + cfg.Port); err != nil {
@@ -0,0 +1,75 @@
+import client from './client';
+
+export type ArticleSource = 'zenn' | 'qiita' | 'feed';
+
+export interface Article {
+  id: number;
+  user_id: number;
+  source: ArticleSource;
+  external_id: string;
+  feed_id?: number;
+  title: string;
+  url: string;
+  summary?: string;
+  emoji?: string;
+  category?: string;
+  likes: number;
+  comments: number;
+  stocks: number;
+  tags: string;
+  published_at: string;
+  updated_at: string;
+}
+
+export interface ArticleSourceStats {
+  source: ArticleSource;
+  articles: number;
+  likes: number;
+  comments: number;
+}
+
+export interface ArticleStats {
+  total_articles: number;
+  total_likes: number;
+  total_comments: number;
+  sources: ArticleSourceStats[];
+}
+
+export interface ArticleEngagement {
+  date: string;
+  likes: number;
+  comments: number;
+  stocks: number;
+}
+
+export interface TrendingArticle extends Article {
+  author_name: string;
+  author_avatar_url: string;
+  likes_gained: number;
+  comments_gained: number;
+  stocks_gained: number;
+}
+
+export const connectArticleSource = (source: ArticleSource, username: string, token?: string) =>
+  client.post(`/articles/sources/${source}/connect`, { username, token });
+
+export const disconnectArticleSource = (source: ArticleSource) =>
+  client.delete(`/articles/sources/${source}/disconnect`);
+
+export const syncArticleSource = (source: ArticleSource) =>
+  client.post(`/articles/sources/${source}/sync`);
+
+export const getArticles = (userId: number, source?: ArticleSource, limit = 100) =>
+  client.get<Article[]>(`/articles/${userId}`, { params: { source, limit } });
+
+export const getArticleStats = (userId: number, source?: ArticleSource) =>
+  client.get<ArticleStats>(`/articles/${userId}/stats`, { params: { source } });
+
+export const getArticleEngagement = (userId: number, days = 30, source?: ArticleSource) =>
+  client.get<ArticleEngagement[]>(`/articles/${userId}/engagement`, { params: { days, source } });
+
+export const getSingleArticleEngagement = (userId: number, articleId: number, days = 30) =>
+  client.get<{ article: Article; engagement: ArticleEngagement[] }>(`/articles/${userId}/engagement/${articleId}`, { params: { days } });
+
+export const getTrendingArticles = (days = 7, limit = 20) =>
+  client.get<TrendingArticle[]>('/articles/trending', { params: { days, limit } });
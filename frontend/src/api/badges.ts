@@ -4,5 +4,5 @@ import type { BadgeResult } from '../types/badge';
 export const getUserBadges = (userId: number) =>
   client.get<{ badges: BadgeResult[] }>(`/badges/${userId}`);
 
-export const notifyBadgeEarned = (badgeId: string) =>
-  client.post('/badges/notify', { badge_id: badgeId });
+export const getClosestBadges = (userId: number, limit = 3) =>
+  client.get<{ badges: BadgeResult[] }>(`/badges/${userId}/closest`, { params: { limit } });
@@ -1,11 +1,33 @@
 import client from './client';
-import type { RankingEntry } from '../types/ranking';
+import type {
+  RankingEntry, RankingPeriod, RankingScope, RankingMetrics, RankingPosition, RankHistoryPoint,
+} from '../types/ranking';
 
-export const getContributionRanking = (period: 'weekly' | 'monthly' = 'weekly') =>
-  client.get<RankingEntry[]>('/rankings/contributions', { params: { period } });
+export interface RankingParams {
+  period?: RankingPeriod;
+  scope?: RankingScope;
+  room_id?: number;
+  team_id?: number;
+  limit?: number;
+}
 
-export const getLanguageRanking = (language: string, period: 'weekly' | 'monthly' = 'weekly') =>
-  client.get<RankingEntry[]>(`/rankings/languages/${language}`, { params: { period } });
+export const getRanking = (metric = 'composite', params: RankingParams = {}) =>
+  client.get<RankingEntry[]>('/rankings', { params: { metric, ...params } });
+
+export const getMyRanking = (metric = 'composite', params: RankingParams = {}) =>
+  client.get<RankingPosition>('/rankings/me', { params: { metric, ...params } });
+
+export const getRankHistory = (userId: number, metric = 'composite', period: RankingPeriod = 'weekly', days = 30) =>
+  client.get<RankHistoryPoint[]>(`/rankings/users/${userId}/history`, { params: { metric, period, days } });
+
+export const getRankingMetrics = () =>
+  client.get<RankingMetrics>('/rankings/metrics');
+
+export const getContributionRanking = (period: RankingPeriod = 'weekly', params: RankingParams = {}) =>
+  client.get<RankingEntry[]>('/rankings/contributions', { params: { period, ...params } });
+
+export const getLanguageRanking = (language: string, period: RankingPeriod = 'weekly', params: RankingParams = {}) =>
+  client.get<RankingEntry[]>(`/rankings/languages/${language}`, { params: { period, ...params } });
 
 export const getAvailableLanguages = () =>
   client.get<string[]>('/rankings/languages');
@@ -0,0 +1,26 @@
+import client from './client';
+import type { Team, TeamMember } from '../types/team';
+
+export const getTeams = () =>
+  client.get<Team[]>('/teams');
+
+export const createTeam = (data: { name: string; description?: string; member_ids?: number[] }) =>
+  client.post<Team>('/teams', data);
+
+export const getTeam = (id: number) =>
+  client.get<Team>(`/teams/${id}`);
+
+export const updateTeam = (id: number, data: { name?: string; description?: string }) =>
+  client.put<Team>(`/teams/${id}`, data);
+
+export const deleteTeam = (id: number) =>
+  client.delete(`/teams/${id}`);
+
+export const getTeamMembers = (id: number) =>
+  client.get<TeamMember[]>(`/teams/${id}/members`);
+
+export const addTeamMember = (id: number, userId: number) =>
+  client.post(`/teams/${id}/members`, { user_id: userId });
+
+export const removeTeamMember = (id: number, userId: number) =>
+  client.delete(`/teams/${id}/members/${userId}`);
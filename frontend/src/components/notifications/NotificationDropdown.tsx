@@ -49,6 +49,8 @@ export default function NotificationDropdown() {
         return t('notifications.newAnswer', { name: notification.actor.name });
       case 'badge':
         return t('notifications.newBadge');
+      case 'rank':
+        return t('notifications.newRank', { change: notification.rank_change, rank: notification.rank });
       default:
         return '';
     }
@@ -68,6 +70,8 @@ export default function NotificationDropdown() {
         return notification.question_id ? `/qa/${notification.question_id}` : '/';
       case 'badge':
         return `/profile/${notification.actor_id}`;
+      case 'rank':
+        return '/rankings';
       default:
         return '/';
     }
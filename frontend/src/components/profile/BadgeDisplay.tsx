@@ -1,39 +1,59 @@
 import { useTranslation } from 'react-i18next';
 import {
   Sprout, Monitor, Swords, Crown, Trophy, Flame, Zap,
-  PenLine, FileText, Heart, Star, Handshake, Megaphone, StarIcon,
+  PenLine, FileText, Heart, Star, Handshake, Megaphone, Sparkles,
   Medal, MessageCircleQuestion, HelpCircle, Award, GraduationCap,
+  Newspaper, BookOpen, Glasses,
   type LucideIcon,
 } from 'lucide-react';
-import type { BadgeResult } from '../../types/badge';
+import type { BadgeResult, BadgeTier } from '../../types/badge';
 
 interface BadgeDisplayProps {
   badges: BadgeResult[];
 }
 
-/** Badge ID → icon, color, bgColor mapping (UI responsibility) */
-const badgeMeta: Record<string, { icon: LucideIcon; color: string; bgColor: string }> = {
-  'first-commit':    { icon: Sprout,     color: 'text-green-400',  bgColor: 'bg-green-500/10 border-green-500/30' },
-  'contributor':     { icon: Monitor,    color: 'text-blue-400',   bgColor: 'bg-blue-500/10 border-blue-500/30' },
-  'code-warrior':    { icon: Swords,     color: 'text-purple-400', bgColor: 'bg-purple-500/10 border-purple-500/30' },
-  'commit-master':   { icon: Crown,      color: 'text-yellow-400', bgColor: 'bg-yellow-500/10 border-yellow-500/30' },
-  'legend':          { icon: Trophy,     color: 'text-orange-400', bgColor: 'bg-orange-500/10 border-orange-500/30' },
-  'week-streak':     { icon: Flame,      color: 'text-orange-400', bgColor: 'bg-orange-500/10 border-orange-500/30' },
-  'month-streak':    { icon: Zap,        color: 'text-yellow-400', bgColor: 'bg-yellow-500/10 border-yellow-500/30' },
-  'first-post':      { icon: PenLine,    color: 'text-cyan-400',   bgColor: 'bg-cyan-500/10 border-cyan-500/30' },
-  'blogger':         { icon: FileText,   color: 'text-indigo-400', bgColor: 'bg-indigo-500/10 border-indigo-500/30' },
-  'liked':           { icon: Heart,      color: 'text-pink-400',   bgColor: 'bg-pink-500/10 border-pink-500/30' },
-  'popular':         { icon: Star,       color: 'text-rose-400',   bgColor: 'bg-rose-500/10 border-rose-500/30' },
-  'friendly':        { icon: Handshake,  color: 'text-teal-400',   bgColor: 'bg-teal-500/10 border-teal-500/30' },
-  'influencer':      { icon: Megaphone,  color: 'text-violet-400', bgColor: 'bg-violet-500/10 border-violet-500/30' },
-  'star':            { icon: StarIcon,   color: 'text-amber-400',  bgColor: 'bg-amber-500/10 border-amber-500/30' },
-  'qa-first-answer': { icon: MessageCircleQuestion, color: 'text-blue-400', bgColor: 'bg-blue-500/10 border-blue-500/30' },
-  'qa-helper':       { icon: HelpCircle, color: 'text-cyan-400',   bgColor: 'bg-cyan-500/10 border-cyan-500/30' },
-  'goal-achiever':   { icon: Award,      color: 'text-green-400',  bgColor: 'bg-green-500/10 border-green-500/30' },
-  'goal-master':     { icon: GraduationCap, color: 'text-purple-400', bgColor: 'bg-purple-500/10 border-purple-500/30' },
+/** Badge icon name → icon (UI responsibility) */
+const badgeIcons: Record<string, LucideIcon> = {
+  'sprout': Sprout,
+  'monitor': Monitor,
+  'swords': Swords,
+  'crown': Crown,
+  'trophy': Trophy,
+  'flame': Flame,
+  'zap': Zap,
+  'pen-line': PenLine,
+  'file-text': FileText,
+  'heart': Heart,
+  'star': Star,
+  'handshake': Handshake,
+  'megaphone': Megaphone,
+  'sparkles': Sparkles,
+  'message-circle-question': MessageCircleQuestion,
+  'help-circle': HelpCircle,
+  'award': Award,
+  'graduation-cap': GraduationCap,
+  'newspaper': Newspaper,
+  'book-open': BookOpen,
+  'glasses': Glasses,
 };
 
-const fallbackMeta = { icon: Medal, color: 'text-gray-400', bgColor: 'bg-gray-500/10 border-gray-500/30' };
+/** Badge tier → color, bgColor */
+const tierStyles: Record<BadgeTier, { color: string; bgColor: string }> = {
+  bronze:   { color: 'text-amber-500',  bgColor: 'bg-amber-500/10 border-amber-500/30' },
+  silver:   { color: 'text-gray-300',   bgColor: 'bg-gray-400/10 border-gray-400/30' },
+  gold:     { color: 'text-yellow-400', bgColor: 'bg-yellow-500/10 border-yellow-500/30' },
+  platinum: { color: 'text-cyan-300',   bgColor: 'bg-cyan-400/10 border-cyan-400/30' },
+};
+
+const fallbackStyle = { color: 'text-gray-400', bgColor: 'bg-gray-500/10 border-gray-500/30' };
+
+export function badgeMeta(badge: BadgeResult) {
+  return { icon: badgeIcons[badge.icon] || Medal, ...(tierStyles[badge.tier] || fallbackStyle) };
+}
+
+export function formatRarity(rarity: number) {
+  return rarity.toLocaleString(undefined, { maximumFractionDigits: 1 });
+}
 
 export default function BadgeDisplay({ badges }: BadgeDisplayProps) {
   const { t } = useTranslation();
@@ -72,7 +92,7 @@ export default function BadgeDisplay({ badges }: BadgeDisplayProps) {
             <h3 className="text-xs text-gray-400 uppercase tracking-wide mb-3">{t('profile.earned')}</h3>
             <div className="flex flex-wrap gap-2">
               {earnedBadges.map((badge) => {
-                const meta = badgeMeta[badge.id] || fallbackMeta;
+                const meta = badgeMeta(badge);
                 const Icon = meta.icon;
                 return (
                   <div
@@ -86,6 +106,9 @@ export default function BadgeDisplay({ badges }: BadgeDisplayProps) {
                     <div className="absolute bottom-full left-1/2 -translate-x-1/2 mb-2 px-3 py-2 bg-gray-800 rounded-lg text-xs text-center opacity-0 invisible group-hover:opacity-100 group-hover:visible transition-all whitespace-nowrap z-10 shadow-lg">
                       <div className="font-medium text-white">{t(badge.name)}</div>
                       <div className="text-gray-400">{t(badge.description)}</div>
+                      <div className="text-gray-500 mt-0.5">
+                        {t(`badges.tier.${badge.tier}`)} · {t('badges.rarity', { percent: formatRarity(badge.rarity) })}
+                      </div>
                       <div className="absolute top-full left-1/2 -translate-x-1/2 border-4 border-transparent border-t-gray-800" />
                     </div>
                   </div>
@@ -101,7 +124,7 @@ export default function BadgeDisplay({ badges }: BadgeDisplayProps) {
             <h3 className="text-xs text-gray-500 uppercase tracking-wide mb-3">{t('profile.locked')}</h3>
             <div className="flex flex-wrap gap-2">
               {lockedBadges.map((badge) => {
-                const meta = badgeMeta[badge.id] || fallbackMeta;
+                const meta = badgeMeta(badge);
                 const Icon = meta.icon;
                 return (
                   <div
@@ -112,9 +135,23 @@ export default function BadgeDisplay({ badges }: BadgeDisplayProps) {
                       <Icon className="w-5 h-5 text-gray-500" />
                       <span className="text-sm font-medium text-gray-500">{t(badge.name)}</span>
                     </div>
+                    {badge.threshold > 0 && (
+                      <div className="mt-1.5 h-1 bg-gray-700/50 rounded-full overflow-hidden">
+                        <div
+                          className="h-full bg-gray-500 rounded-full"
+                          style={{ width: `${Math.min(100, (badge.progress / badge.threshold) * 100)}%` }}
+                        />
+                      </div>
+                    )}
                     <div className="absolute bottom-full left-1/2 -translate-x-1/2 mb-2 px-3 py-2 bg-gray-800 rounded-lg text-xs text-center opacity-0 invisible group-hover:opacity-100 group-hover:visible transition-all whitespace-nowrap z-10 shadow-lg">
                       <div className="font-medium text-white">{t(badge.name)}</div>
                       <div className="text-gray-400">{t(badge.description)}</div>
+                      <div className="text-gray-300 mt-0.5">
+                        {t('badges.progress', { progress: badge.progress.toLocaleString(), threshold: badge.threshold.toLocaleString() })}
+                      </div>
+                      <div className="text-gray-500 mt-0.5">
+                        {t(`badges.tier.${badge.tier}`)} · {t('badges.rarity', { percent: formatRarity(badge.rarity) })}
+                      </div>
                       <div className="absolute top-full left-1/2 -translate-x-1/2 border-4 border-transparent border-t-gray-800" />
                     </div>
                   </div>
@@ -1,7 +1,6 @@
 import { useEffect, useRef } from 'react';
 import { useTranslation } from 'react-i18next';
 import { useToast } from '../contexts/ToastContext';
-import { notifyBadgeEarned } from '../api/badges';
 import type { BadgeResult } from '../types/badge';
 
 const STORAGE_KEY = 'devsync_earned_badges';
@@ -33,11 +32,9 @@ export function useBadgeNotifier(badges: BadgeResult[]) {
     const newBadges = earnedBadges.filter((b) => !previousIds.includes(b.id));
 
     if (newBadges.length > 0) {
-      // Show toast for each new badge
+      // Show toast for each new badge. The server notifies of them itself.
       newBadges.forEach((badge) => {
         showToast(t('badges.badgeEarned', { name: t(badge.name) }), 'success');
-        // Notify backend
-        notifyBadgeEarned(badge.id).catch(() => {});
       });
 
       // Update localStorage
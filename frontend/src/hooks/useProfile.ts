@@ -1,8 +1,7 @@
 import { getUser, getFollowers, getFollowing } from '../api/users';
 import { getUserPosts } from '../api/posts';
 import { getContributions, getLanguages, getRepos } from '../api/github';
-import { getZennArticles, getZennStats, type ZennArticle, type ZennStats } from '../api/zenn';
-import { getQiitaArticles, getQiitaStats, type QiitaArticle, type QiitaStats } from '../api/qiita';
+import { getArticles, getArticleStats, type Article, type ArticleStats } from '../api/articles';
 import { getUserGoals, getGoalStats, type LearningGoal, type LearningGoalStats } from '../api/goals';
 import { getUserBadges } from '../api/badges';
 import type { User } from '../types/user';
@@ -17,10 +16,10 @@ interface ProfileData {
   contributions: GitHubContribution[];
   languages: GitHubLanguageStat[];
   repos: GitHubRepository[];
-  zennArticles: ZennArticle[];
-  zennStats: ZennStats | null;
-  qiitaArticles: QiitaArticle[];
-  qiitaStats: QiitaStats | null;
+  zennArticles: Article[];
+  zennStats: ArticleStats | null;
+  qiitaArticles: Article[];
+  qiitaStats: ArticleStats | null;
   goals: LearningGoal[];
   goalStats: LearningGoalStats | null;
   followerCount: number;
@@ -56,23 +55,23 @@ export function useProfile(id: string | undefined) {
         repos = reposRes.data || [];
       }
 
-      let zennArticles: ZennArticle[] = [];
-      let zennStats: ZennStats | null = null;
+      let zennArticles: Article[] = [];
+      let zennStats: ArticleStats | null = null;
       if (userData.zenn_username) {
         const [articlesRes, statsRes] = await Promise.all([
-          getZennArticles(userId),
-          getZennStats(userId),
+          getArticles(userId, 'zenn'),
+          getArticleStats(userId, 'zenn'),
         ]);
         zennArticles = articlesRes.data || [];
         zennStats = statsRes.data;
       }
 
-      let qiitaArticles: QiitaArticle[] = [];
-      let qiitaStats: QiitaStats | null = null;
+      let qiitaArticles: Article[] = [];
+      let qiitaStats: ArticleStats | null = null;
       if (userData.qiita_username) {
         const [articlesRes, statsRes] = await Promise.all([
-          getQiitaArticles(userId),
-          getQiitaStats(userId),
+          getArticles(userId, 'qiita'),
+          getArticleStats(userId, 'qiita'),
         ]);
         qiitaArticles = articlesRes.data || [];
         qiitaStats = statsRes.data;
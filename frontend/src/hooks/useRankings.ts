@@ -1,6 +1,13 @@
 import { useState } from 'react';
-import { getContributionRanking, getLanguageRanking, getAvailableLanguages } from '../api/rankings';
-import type { RankingEntry } from '../types/ranking';
+import {
+  getRanking, getMyRanking, getContributionRanking, getLanguageRanking, getAvailableLanguages,
+} from '../api/rankings';
+import type { RankingParams } from '../api/rankings';
+import { getTeams } from '../api/teams';
+import { getChatRooms } from '../api/chatRooms';
+import type { RankingEntry, RankingPeriod, RankingPosition, RankingScope } from '../types/ranking';
+import type { Team } from '../types/team';
+import type { ChatRoom } from '../types/chat';
 import { useAsyncData } from './useAsyncData';
 
 const DEFAULT_LANGUAGES = [
@@ -9,10 +16,22 @@ const DEFAULT_LANGUAGES = [
 ];
 
 export function useRankings() {
-  const [tab, setTab] = useState<'contributions' | 'languages'>('contributions');
-  const [period, setPeriod] = useState<'weekly' | 'monthly'>('weekly');
+  const [tab, setTab] = useState<'overall' | 'contributions' | 'languages'>('contributions');
+  const [period, setPeriod] = useState<RankingPeriod>('weekly');
+  const [scope, setScopeState] = useState<RankingScope>('all');
+  // The team or chat room ranked when scope is 'team' or 'room'
+  const [groupId, setGroupId] = useState<number | null>(null);
   const [language, setLanguage] = useState('JavaScript');
 
+  const setScope = (next: RankingScope, id: number | null = null) => {
+    setScopeState(next);
+    setGroupId(id);
+  };
+
+  const params: RankingParams = { scope };
+  if (scope === 'team' && groupId) params.team_id = groupId;
+  if (scope === 'room' && groupId) params.room_id = groupId;
+
   const { data: languages } = useAsyncData(
     async () => {
       const { data } = await getAvailableLanguages();
@@ -25,29 +44,67 @@ export function useRankings() {
     { initialData: DEFAULT_LANGUAGES }
   );
 
+  const { data: teams, refetch: refetchTeams } = useAsyncData(
+    async () => {
+      const { data } = await getTeams();
+      return data || [];
+    },
+    { initialData: [] as Team[] }
+  );
+
+  const { data: rooms } = useAsyncData(
+    async () => {
+      const { data } = await getChatRooms();
+      return data || [];
+    },
+    { initialData: [] as ChatRoom[] }
+  );
+
   const { data: rankings, loading } = useAsyncData(
     async () => {
+      if (tab === 'overall') {
+        const { data } = await getRanking('composite', { period, ...params });
+        return data || [];
+      }
       if (tab === 'contributions') {
-        const { data } = await getContributionRanking(period);
+        const { data } = await getContributionRanking(period, params);
         return data || [];
       }
       if (language) {
-        const { data } = await getLanguageRanking(language, period);
+        const { data } = await getLanguageRanking(language, period, params);
         return data || [];
       }
       return [];
     },
-    { initialData: [] as RankingEntry[], deps: [tab, period, language] }
+    { initialData: [] as RankingEntry[], deps: [tab, period, scope, groupId, language] }
+  );
+
+  // Languages aren't snapshotted as leaderboards, so have no "my rank"
+  const { data: position } = useAsyncData(
+    async () => {
+      if (tab === 'languages') return null;
+      const metric = tab === 'overall' ? 'composite' : 'contributions';
+      const { data } = await getMyRanking(metric, { period, ...params });
+      return data;
+    },
+    { initialData: null as RankingPosition | null, deps: [tab, period, scope, groupId] }
   );
 
   return {
     rankings,
+    position,
     languages,
+    teams,
+    rooms,
+    refetchTeams,
     loading,
     tab,
     setTab,
     period,
     setPeriod,
+    scope,
+    groupId,
+    setScope,
     language,
     setLanguage,
   };
@@ -1,15 +1,16 @@
 import { Link } from 'react-router-dom';
 import { useTranslation } from 'react-i18next';
-import { Target, Bell, TrendingUp, CheckCircle2, Clock, ChevronRight } from 'lucide-react';
+import { Target, Bell, TrendingUp, CheckCircle2, Clock, ChevronRight, Award } from 'lucide-react';
 import { useAuthStore } from '../store/authStore';
 import { usePosts, useDashboard, useBadgeNotifier } from '../hooks';
-import { getUserBadges } from '../api/badges';
+import { getUserBadges, getClosestBadges } from '../api/badges';
 import { useAsyncData } from '../hooks/useAsyncData';
 import type { BadgeResult } from '../types/badge';
 import PostCard from '../components/posts/PostCard';
 import PostForm from '../components/posts/PostForm';
 import { PostCardSkeleton } from '../components/common/Skeleton';
 import Avatar from '../components/common/Avatar';
+import { badgeMeta } from '../components/profile/BadgeDisplay';
 import { formatDistanceToNow } from '../utils/timeFormat';
 
 export default function DashboardPage() {
@@ -36,6 +37,15 @@ export default function DashboardPage() {
   );
   useBadgeNotifier(badges);
 
+  const { data: nextBadges, loading: nextBadgesLoading } = useAsyncData(
+    async () => {
+      if (!user) return [] as BadgeResult[];
+      const res = await getClosestBadges(user.id);
+      return res.data?.badges || [];
+    },
+    { initialData: [] as BadgeResult[], deps: [user?.id], enabled: !!user }
+  );
+
   const handleCreatePost = async (title: string, content: string, imageUrls?: string) => {
     await createPost(title, content, imageUrls);
   };
@@ -239,6 +249,56 @@ export default function DashboardPage() {
           )}
         </div>
 
+        {/* Next Badges Widget */}
+        <div className="bg-gray-900 border border-gray-800 rounded-xl p-4">
+          <div className="flex items-center justify-between mb-3">
+            <h3 className="flex items-center gap-2 text-sm font-medium text-white">
+              <Award className="w-4 h-4 text-yellow-400" />
+              {t('dashboard.nextBadges')}
+            </h3>
+            {user && (
+              <Link to={`/profile/${user.id}`} className="text-xs text-gray-400 hover:text-blue-400 transition-colors">
+                {t('dashboard.viewAll')}
+              </Link>
+            )}
+          </div>
+
+          {nextBadgesLoading ? (
+            <div className="space-y-3">
+              <div className="h-4 bg-gray-800 rounded animate-pulse" />
+              <div className="h-4 bg-gray-800 rounded animate-pulse w-2/3" />
+            </div>
+          ) : nextBadges.length === 0 ? (
+            <p className="text-xs text-gray-500 text-center py-4">{t('dashboard.noNextBadges')}</p>
+          ) : (
+            <div className="space-y-3">
+              {nextBadges.map((badge) => {
+                const meta = badgeMeta(badge);
+                const Icon = meta.icon;
+                return (
+                  <div key={badge.id}>
+                    <div className="flex items-center justify-between mb-1">
+                      <span className="flex items-center gap-1.5 text-xs text-gray-300 truncate flex-1 mr-2">
+                        <Icon className={`w-3.5 h-3.5 shrink-0 ${meta.color}`} />
+                        {t(badge.name)}
+                      </span>
+                      <span className="text-xs text-gray-500 shrink-0 tabular-nums">
+                        {t('badges.progress', { progress: badge.progress.toLocaleString(), threshold: badge.threshold.toLocaleString() })}
+                      </span>
+                    </div>
+                    <div className="h-1.5 bg-gray-800 rounded-full overflow-hidden">
+                      <div
+                        className="h-full rounded-full bg-yellow-500 transition-all"
+                        style={{ width: `${Math.min(100, (badge.progress / badge.threshold) * 100)}%` }}
+                      />
+                    </div>
+                  </div>
+                );
+              })}
+            </div>
+          )}
+        </div>
+
         {/* Recent Notifications Widget */}
         <div className="bg-gray-900 border border-gray-800 rounded-xl p-4">
           <div className="flex items-center justify-between mb-3">
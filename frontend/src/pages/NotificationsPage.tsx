@@ -15,6 +15,7 @@ const FILTER_TYPES: { key: NotificationType | ''; labelKey: string }[] = [
   { key: 'message', labelKey: 'notifications.filterMessage' },
   { key: 'answer', labelKey: 'notifications.filterAnswer' },
   { key: 'badge', labelKey: 'notifications.filterBadge' },
+  { key: 'rank', labelKey: 'notifications.filterRank' },
 ];
 
 function getNotificationLink(notification: Notification): string {
@@ -31,6 +32,8 @@ function getNotificationLink(notification: Notification): string {
       return notification.question_id ? `/qa/${notification.question_id}` : '/';
     case 'badge':
       return `/profile/${notification.actor_id}`;
+    case 'rank':
+      return '/rankings';
     default:
       return '/';
   }
@@ -75,6 +78,8 @@ export default function NotificationsPage() {
         return t('notifications.newAnswer', { name: notification.actor.name });
       case 'badge':
         return t('notifications.newBadge');
+      case 'rank':
+        return t('notifications.newRank', { change: notification.rank_change, rank: notification.rank });
       default:
         return '';
     }
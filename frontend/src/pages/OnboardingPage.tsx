@@ -5,8 +5,7 @@ import { User, Code, Link, CheckCircle, ChevronRight, ChevronLeft } from 'lucide
 import { useAuthStore } from '../store/authStore';
 import { updateUser } from '../api/users';
 import { getGitHubConnectURL } from '../api/github';
-import { connectZenn } from '../api/zenn';
-import { connectQiita } from '../api/qiita';
+import { connectArticleSource } from '../api/articles';
 import toast from 'react-hot-toast';
 
 const LANGUAGES = [
@@ -112,7 +111,7 @@ export default function OnboardingPage() {
     if (!zennUsername.trim()) return;
     setConnectingZenn(true);
     try {
-      await connectZenn(zennUsername.trim());
+      await connectArticleSource('zenn', zennUsername.trim());
       setUser({ ...user, zenn_username: zennUsername.trim() });
       setZennUsername('');
       toast.success(t('settings.zennConnected'));
@@ -127,7 +126,7 @@ export default function OnboardingPage() {
     if (!qiitaUsername.trim()) return;
     setConnectingQiita(true);
     try {
-      await connectQiita(qiitaUsername.trim());
+      await connectArticleSource('qiita', qiitaUsername.trim());
       setUser({ ...user, qiita_username: qiitaUsername.trim() });
       setQiitaUsername('');
       toast.success(t('settings.qiitaConnected'));
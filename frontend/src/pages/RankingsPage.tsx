@@ -1,15 +1,41 @@
+import { useState } from 'react';
 import { Link } from 'react-router-dom';
 import { useTranslation } from 'react-i18next';
+import { Plus } from 'lucide-react';
 import { useRankings } from '../hooks';
+import { useAuthStore } from '../store/authStore';
+import { getFollowing } from '../api/users';
 import Avatar from '../components/common/Avatar';
 import LoadingSpinner from '../components/common/LoadingSpinner';
+import CreateTeamModal from '../components/teams/CreateTeamModal';
+import type { RankingPeriod } from '../types/ranking';
+import type { User } from '../types/user';
+
+const PERIODS: { value: RankingPeriod; label: string }[] = [
+  { value: 'weekly', label: 'rankings.thisWeek' },
+  { value: 'monthly', label: 'rankings.thisMonth' },
+  { value: 'yearly', label: 'rankings.thisYear' },
+  { value: 'all', label: 'rankings.allTime' },
+];
 
 export default function RankingsPage() {
   const { t } = useTranslation();
   const {
-    rankings, languages, loading,
-    tab, setTab, period, setPeriod, language, setLanguage,
+    rankings, position, languages, teams, rooms, refetchTeams, loading,
+    tab, setTab, period, setPeriod, scope, groupId, setScope, language, setLanguage,
   } = useRankings();
+  const currentUser = useAuthStore((s) => s.user);
+  const [showCreateTeam, setShowCreateTeam] = useState(false);
+  const [followingUsers, setFollowingUsers] = useState<User[]>([]);
+
+  const openCreateTeam = () => {
+    setShowCreateTeam(true);
+    if (currentUser) {
+      getFollowing(currentUser.id)
+        .then(({ data }) => setFollowingUsers(data || []))
+        .catch(() => {});
+    }
+  };
 
   const medalColor = (index: number) => {
     if (index === 0) return 'text-yellow-400';
@@ -25,6 +51,16 @@ export default function RankingsPage() {
       {/* Tab & Period Controls */}
       <div className="flex items-center gap-3">
         <div className="flex bg-gray-900 border border-gray-800 rounded-lg p-1">
+          <button
+            onClick={() => setTab('overall')}
+            className={`px-4 py-1.5 rounded-md text-sm font-medium transition-colors ${
+              tab === 'overall'
+                ? 'bg-gray-700 text-white'
+                : 'text-gray-400 hover:text-white'
+            }`}
+          >
+            {t('rankings.overall')}
+          </button>
           <button
             onClick={() => setTab('contributions')}
             className={`px-4 py-1.5 rounded-md text-sm font-medium transition-colors ${
@@ -58,29 +94,97 @@ export default function RankingsPage() {
         </div>
 
         <div className="ml-auto flex bg-gray-900 border border-gray-800 rounded-lg p-1">
-          <button
-            onClick={() => setPeriod('weekly')}
-            className={`px-3 py-1.5 rounded-md text-xs font-medium transition-colors ${
-              period === 'weekly'
-                ? 'bg-gray-700 text-white'
-                : 'text-gray-400 hover:text-white'
-            }`}
-          >
-            {t('rankings.thisWeek')}
-          </button>
-          <button
-            onClick={() => setPeriod('monthly')}
-            className={`px-3 py-1.5 rounded-md text-xs font-medium transition-colors ${
-              period === 'monthly'
-                ? 'bg-gray-700 text-white'
-                : 'text-gray-400 hover:text-white'
-            }`}
-          >
-            {t('rankings.thisMonth')}
-          </button>
+          {PERIODS.map(({ value, label }) => (
+            <button
+              key={value}
+              onClick={() => setPeriod(value)}
+              className={`px-3 py-1.5 rounded-md text-xs font-medium transition-colors ${
+                period === value
+                  ? 'bg-gray-700 text-white'
+                  : 'text-gray-400 hover:text-white'
+              }`}
+            >
+              {t(label)}
+            </button>
+          ))}
         </div>
       </div>
 
+      <div className="flex flex-wrap items-center gap-3">
+        <div className="flex bg-gray-900 border border-gray-800 rounded-lg p-1 w-fit">
+          {(['all', 'following'] as const).map((value) => (
+            <button
+              key={value}
+              onClick={() => setScope(value)}
+              className={`px-3 py-1.5 rounded-md text-xs font-medium transition-colors ${
+                scope === value
+                  ? 'bg-gray-700 text-white'
+                  : 'text-gray-400 hover:text-white'
+              }`}
+            >
+              {value === 'all' ? t('rankings.everyone') : t('rankings.following')}
+            </button>
+          ))}
+        </div>
+
+        {(teams.length > 0 || rooms.length > 0) && (
+          <select
+            value={scope === 'team' || scope === 'room' ? `${scope}:${groupId}` : ''}
+            onChange={(e) => {
+              const [kind, id] = e.target.value.split(':');
+              if (kind === 'team' || kind === 'room') {
+                setScope(kind, Number(id));
+              } else {
+                setScope('all');
+              }
+            }}
+            className="bg-gray-900 border border-gray-800 rounded-lg px-3 py-2 text-xs text-gray-300 focus:ring-2 focus:ring-blue-500 focus:border-transparent"
+          >
+            <option value="">{t('rankings.selectGroup')}</option>
+            {teams.length > 0 && (
+              <optgroup label={t('rankings.teams')}>
+                {teams.map((team) => (
+                  <option key={team.id} value={`team:${team.id}`}>{team.name}</option>
+                ))}
+              </optgroup>
+            )}
+            {rooms.length > 0 && (
+              <optgroup label={t('rankings.chatRooms')}>
+                {rooms.map((room) => (
+                  <option key={room.id} value={`room:${room.id}`}>{room.name}</option>
+                ))}
+              </optgroup>
+            )}
+          </select>
+        )}
+
+        <button
+          onClick={openCreateTeam}
+          className="flex items-center gap-1 text-xs text-blue-400 hover:text-blue-300 transition-colors"
+        >
+          <Plus className="w-4 h-4" />
+          {t('teams.create')}
+        </button>
+      </div>
+
+      {/* My Rank */}
+      {tab !== 'languages' && position && (
+        <div className="bg-gray-900 border border-gray-800 rounded-xl p-4 flex items-center justify-between">
+          <span className="text-sm text-gray-400">{t('rankings.yourRank')}</span>
+          {position.rank > 0 ? (
+            <span className="text-sm text-white">
+              <span className="text-lg font-bold">#{position.rank}</span>
+              <span className="text-gray-400"> / {position.total.toLocaleString()}</span>
+              <span className="ml-3 text-xs text-gray-400">
+                {t('rankings.percentile', { percent: Math.round(position.percentile) })}
+              </span>
+            </span>
+          ) : (
+            <span className="text-sm text-gray-500">{t('rankings.unranked')}</span>
+          )}
+        </div>
+      )}
+
       {/* Language Filter */}
       {tab === 'languages' && (
         <div className="bg-gray-900 border border-gray-800 rounded-xl p-4">
@@ -117,21 +221,21 @@ export default function RankingsPage() {
           <div className="px-6 py-3 border-b border-gray-800 grid grid-cols-[3rem_1fr_auto] gap-4 text-xs font-medium text-gray-500 uppercase tracking-wider">
             <span className="text-center">{t('rankings.rank')}</span>
             <span>{t('rankings.developer')}</span>
-            <span className="text-right">{tab === 'contributions' ? t('rankings.contributions') : t('rankings.total')}</span>
+            <span className="text-right">{tab === 'contributions' ? t('rankings.contributions') : tab === 'overall' ? t('rankings.score') : t('rankings.total')}</span>
           </div>
-          {rankings.map((entry, index) => (
+          {rankings.map((entry) => (
             <Link
               key={entry.user_id}
               to={`/profile/${entry.user_id}`}
               className="grid grid-cols-[3rem_1fr_auto] gap-4 items-center px-6 py-3 hover:bg-gray-800/50 transition-colors border-b border-gray-800/50 last:border-b-0"
             >
-              <span className={`text-center font-bold text-lg ${medalColor(index)}`}>
-                {index < 3 ? (
+              <span className={`text-center font-bold text-lg ${medalColor(entry.rank - 1)}`}>
+                {entry.rank <= 3 ? (
                   <svg className="w-6 h-6 mx-auto" fill="currentColor" viewBox="0 0 24 24">
                     <path d="M12 2l2.4 7.4H22l-6.2 4.5 2.4 7.4L12 16.8l-6.2 4.5 2.4-7.4L2 9.4h7.6z" />
                   </svg>
                 ) : (
-                  index + 1
+                  entry.rank
                 )}
               </span>
               <div className="flex items-center gap-3 min-w-0">
@@ -139,12 +243,24 @@ export default function RankingsPage() {
                 <span className="font-medium text-sm truncate">{entry.name}</span>
               </div>
               <span className="text-green-400 font-semibold text-sm tabular-nums">
-                {entry.score.toLocaleString()}
+                {entry.score.toLocaleString(undefined, { maximumFractionDigits: 1 })}
               </span>
             </Link>
           ))}
         </div>
       )}
+
+      {showCreateTeam && (
+        <CreateTeamModal
+          followingUsers={followingUsers}
+          onClose={() => setShowCreateTeam(false)}
+          onCreated={(team) => {
+            setShowCreateTeam(false);
+            refetchTeams();
+            setScope('team', team.id);
+          }}
+        />
+      )}
     </div>
   );
 }
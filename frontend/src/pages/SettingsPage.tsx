@@ -5,8 +5,7 @@ import { Sparkles } from 'lucide-react';
 import { useAuthStore } from '../store/authStore';
 import { updateUser } from '../api/users';
 import { getGitHubConnectURL, disconnectGitHub, syncGitHub } from '../api/github';
-import { connectZenn, disconnectZenn, syncZenn } from '../api/zenn';
-import { connectQiita, disconnectQiita, syncQiita } from '../api/qiita';
+import { connectArticleSource, disconnectArticleSource, syncArticleSource } from '../api/articles';
 import { deleteAccount } from '../api/auth';
 import toast from 'react-hot-toast';
 
@@ -44,6 +43,7 @@ export default function SettingsPage() {
   const [connectingZenn, setConnectingZenn] = useState(false);
   const [syncingZenn, setSyncingZenn] = useState(false);
   const [qiitaUsername, setQiitaUsername] = useState('');
+  const [qiitaToken, setQiitaToken] = useState('');
   const [connectingQiita, setConnectingQiita] = useState(false);
   const [syncingQiita, setSyncingQiita] = useState(false);
   const [showDeleteModal, setShowDeleteModal] = useState(false);
@@ -144,7 +144,7 @@ export default function SettingsPage() {
     if (!zennUsername.trim()) return;
     setConnectingZenn(true);
     try {
-      await connectZenn(zennUsername.trim());
+      await connectArticleSource('zenn', zennUsername.trim());
       setUser({ ...user, zenn_username: zennUsername.trim() });
       setZennUsername('');
       toast.success(t('settings.zennConnected'));
@@ -157,7 +157,7 @@ export default function SettingsPage() {
 
   const handleDisconnectZenn = async () => {
     try {
-      await disconnectZenn();
+      await disconnectArticleSource('zenn');
       setUser({ ...user, zenn_username: '' });
       toast.success(t('settings.saved'));
     } catch {
@@ -168,7 +168,7 @@ export default function SettingsPage() {
   const handleSyncZenn = async () => {
     setSyncingZenn(true);
     try {
-      await syncZenn();
+      await syncArticleSource('zenn');
       toast.success(t('settings.saved'));
     } catch {
       toast.error(t('errors.somethingWrong'));
@@ -182,9 +182,10 @@ export default function SettingsPage() {
     if (!qiitaUsername.trim()) return;
     setConnectingQiita(true);
     try {
-      await connectQiita(qiitaUsername.trim());
+      await connectArticleSource('qiita', qiitaUsername.trim(), qiitaToken.trim() || undefined);
       setUser({ ...user, qiita_username: qiitaUsername.trim() });
       setQiitaUsername('');
+      setQiitaToken('');
       toast.success(t('settings.qiitaConnected'));
     } catch {
       toast.error(t('settings.qiitaInvalidUsername'));
@@ -195,7 +196,7 @@ export default function SettingsPage() {
 
   const handleDisconnectQiita = async () => {
     try {
-      await disconnectQiita();
+      await disconnectArticleSource('qiita');
       setUser({ ...user, qiita_username: '' });
       toast.success(t('settings.saved'));
     } catch {
@@ -206,7 +207,7 @@ export default function SettingsPage() {
   const handleSyncQiita = async () => {
     setSyncingQiita(true);
     try {
-      await syncQiita();
+      await syncArticleSource('qiita');
       toast.success(t('settings.saved'));
     } catch {
       toast.error(t('errors.somethingWrong'));
@@ -497,6 +498,17 @@ export default function SettingsPage() {
                   className={inputClass}
                 />
               </div>
+              <div>
+                <label className="block text-sm font-medium text-gray-300 mb-1.5">{t('settings.qiitaToken')}</label>
+                <input
+                  type="password"
+                  value={qiitaToken}
+                  onChange={(e) => setQiitaToken(e.target.value)}
+                  autoComplete="off"
+                  className={inputClass}
+                />
+                <p className="text-xs text-gray-500 mt-1">{t('settings.qiitaTokenHint')}</p>
+              </div>
               <button
                 type="submit"
                 disabled={connectingQiita || !qiitaUsername.trim()}
@@ -1,7 +1,17 @@
+export type BadgeTier = 'bronze' | 'silver' | 'gold' | 'platinum';
+
 export interface BadgeResult {
   id: string;
   name: string;
   description: string;
   category: string;
+  tier: BadgeTier;
+  icon: string;
+  metric: string;
+  progress: number;
+  threshold: number;
+  /** Percentage of users who hold the badge */
+  rarity: number;
   earned: boolean;
+  awarded_at?: string;
 }
@@ -1,7 +1,7 @@
 import type { User } from './user';
 import type { Post } from './post';
 
-export type NotificationType = 'post' | 'message' | 'like' | 'comment' | 'follow' | 'answer' | 'badge';
+export type NotificationType = 'post' | 'message' | 'like' | 'comment' | 'follow' | 'answer' | 'badge' | 'rank';
 
 export interface Notification {
   id: number;
@@ -14,6 +14,8 @@ export interface Notification {
   question_id?: number;
   question?: { id: number; title: string };
   badge_id?: string;
+  rank?: number;
+  rank_change?: number;
   read: boolean;
   created_at: string;
 }
@@ -1,7 +1,32 @@
+export type RankingPeriod = 'weekly' | 'monthly' | 'yearly' | 'all';
+
+export type RankingScope = 'all' | 'following' | 'room' | 'team';
+
 export interface RankingEntry {
   user_id: number;
   name: string;
   avatar_url: string;
   score: number;
   rank: number;
+  breakdown?: Record<string, number>;
+}
+
+export interface RankingMetrics {
+  metrics: string[];
+  default_weights: Record<string, number>;
+}
+
+export interface RankingPosition {
+  rank: number;
+  score: number;
+  total: number;
+  percentile: number;
+  computed_at?: string;
+}
+
+export interface RankHistoryPoint {
+  date: string;
+  rank: number;
+  score: number;
+  total: number;
 }
@@ -0,0 +1,19 @@
+import type { User } from './user';
+
+export interface Team {
+  id: number;
+  name: string;
+  description: string;
+  owner_id: number;
+  owner?: User;
+  created_at: string;
+  updated_at: string;
+}
+
+export interface TeamMember {
+  id: number;
+  team_id: number;
+  user_id: number;
+  user?: User;
+  joined_at: string;
+}